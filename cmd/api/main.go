@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	_ "github.com/lib/pq"
 	config "github.com/tudormiron/medical-reports/internal/configs"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/importer"
 	"github.com/tudormiron/medical-reports/internal/repository/postgres"
 	"github.com/tudormiron/medical-reports/internal/services"
 	"github.com/tudormiron/medical-reports/server"
@@ -34,10 +39,29 @@ func main() {
 	reportRepo := postgres.NewReportRepository(db)
 	referenceRepo := postgres.NewReferenceRepository(db)
 	userRepo := postgres.NewUserRepository(db)
+	auditRepo := postgres.NewAuditRepository(db)
+	patientRepo := postgres.NewPatientRepository(db)
+	duplicateRepo := postgres.NewDuplicateRepository(db)
+	encounterRepo := postgres.NewEncounterRepository(db)
+	hospitalRepo := postgres.NewHospitalRepository(db)
+	departmentRepo := postgres.NewDepartmentRepository(db)
+	templateRepo := postgres.NewTemplateRepository(db)
+	handoffRepo := postgres.NewHandoffRepository(db)
+	printLayoutRepo := postgres.NewPrintLayoutRepository(db)
+
+	// Load clinical knowledge bases
+	interactions := loadInteractions(cfg.Clinical.InteractionsFile)
+	grouper := loadGrouper(cfg.Billing.DRGDefinitionsDir)
 
 	// Initialize services
-	reportService := services.NewReportService(reportRepo)
+	safetyService := services.NewMedicationSafetyService(referenceRepo, interactions)
+	reportService := services.NewReportService(reportRepo, referenceRepo, auditRepo, patientRepo, encounterRepo, templateRepo, departmentRepo, handoffRepo, safetyService, grouper)
+	patientService := services.NewPatientService(patientRepo, reportRepo)
+	mergeService := services.NewPatientMergeService(patientRepo, reportRepo, duplicateRepo, auditRepo)
+	encounterService := services.NewEncounterService(encounterRepo, patientRepo, reportRepo, departmentRepo, handoffRepo)
+	hospitalService := services.NewHospitalService(hospitalRepo, departmentRepo, templateRepo)
 	referenceService := services.NewReferenceService(referenceRepo)
+	documentService := services.NewDocumentService(reportRepo, hospitalRepo, userRepo, printLayoutRepo, cfg.Server.VerificationURL)
 
 	// JWT secret (should be in config/env var in production)
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -48,7 +72,10 @@ func main() {
 	authService := services.NewAuthService(userRepo, jwtSecret)
 
 	// Initialize server
-	srv := server.NewServer(cfg, reportService, referenceService, authService)
+	srv := server.NewServer(cfg, reportService, referenceService, authService, patientService, mergeService, encounterService, hospitalService, documentService)
+
+	// Background jobs
+	go runDuplicateScan(mergeService, cfg.Jobs.DuplicateScanInterval)
 
 	// Start server
 	log.Printf("Medical Reports API starting...")
@@ -57,3 +84,81 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadInteractions reads the drug interaction table; a missing file disables
+// interaction checking rather than preventing startup
+func loadInteractions(path string) *domain.InteractionKnowledgeBase {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("Warning: drug interaction checks disabled: %v", err)
+		return domain.NewInteractionKnowledgeBase(nil)
+	}
+	defer f.Close()
+
+	interactions, err := importer.ParseInteractionsCSV(f)
+	if err != nil {
+		log.Fatalf("Failed to load drug interactions from %s: %v", path, err)
+	}
+
+	kb := domain.NewInteractionKnowledgeBase(interactions)
+	log.Printf("Loaded %d drug interactions", kb.Size())
+	return kb
+}
+
+// loadGrouper reads the DRG list and rule table of a grouper definition
+// directory, named after the grouper version. A missing directory disables
+// DRG grouping rather than preventing startup.
+func loadGrouper(dir string) domain.DRGGrouper {
+	drgFile, err := os.Open(filepath.Join(dir, "drgs.csv"))
+	if err != nil {
+		log.Printf("Warning: DRG grouping disabled: %v", err)
+		return nil
+	}
+	defer drgFile.Close()
+
+	drgs, err := importer.ParseDRGsCSV(drgFile)
+	if err != nil {
+		log.Fatalf("Failed to load DRGs from %s: %v", dir, err)
+	}
+
+	ruleFile, err := os.Open(filepath.Join(dir, "rules.csv"))
+	if err != nil {
+		log.Fatalf("Failed to load DRG rules from %s: %v", dir, err)
+	}
+	defer ruleFile.Close()
+
+	rules, err := importer.ParseDRGRulesCSV(ruleFile)
+	if err != nil {
+		log.Fatalf("Failed to load DRG rules from %s: %v", dir, err)
+	}
+
+	grouper, err := domain.NewTableGrouper(filepath.Base(dir), drgs, rules)
+	if err != nil {
+		log.Fatalf("Invalid DRG grouper definitions in %s: %v", dir, err)
+	}
+
+	log.Printf("Loaded DRG grouper %s: %d DRGs, %d rules", grouper.Version(), len(drgs), len(rules))
+	return grouper
+}
+
+// runDuplicateScan periodically queues probable duplicate patients for review
+func runDuplicateScan(mergeService *services.PatientMergeService, interval time.Duration) {
+	if interval <= 0 {
+		log.Println("Duplicate patient scan disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		results, err := mergeService.DetectAllDuplicates(context.Background())
+		if err != nil {
+			log.Printf("Duplicate patient scan failed: %v", err)
+			continue
+		}
+		for _, r := range results {
+			log.Printf("Duplicate patient scan: hospital %s, %d patients, %d candidates, %d new", r.HospitalID, r.Patients, r.Found, r.Queued)
+		}
+	}
+}
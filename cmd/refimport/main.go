@@ -0,0 +1,218 @@
+// Command refimport loads official reference data releases into the database.
+//
+// Usage:
+//
+//	refimport lab-tests <file.csv>
+//	refimport icd10 [-format csv|claml] [-version V] <file>
+//	refimport medications [-format csv|xml] <file>
+//	refimport procedures <file.csv>
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/lib/pq"
+	config "github.com/tudormiron/medical-reports/internal/configs"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/importer"
+	"github.com/tudormiron/medical-reports/internal/repository/postgres"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: refimport <command> [flags] <file>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  lab-tests   Import the LOINC lab test catalog from CSV")
+	fmt.Fprintln(os.Stderr, "  icd10       Import an official ICD-10 release from CSV or ClaML XML")
+	fmt.Fprintln(os.Stderr, "  medications Import the national medicines nomenclature from CSV or XML")
+	fmt.Fprintln(os.Stderr, "  procedures  Import the ACHI procedure classification from CSV")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cfg := config.Load()
+
+	db, err := sql.Open("postgres", cfg.Database.URL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	ctx := context.Background()
+	refRepo := postgres.NewReferenceRepository(db)
+
+	switch os.Args[1] {
+	case "lab-tests":
+		err = importLabTests(ctx, refRepo, os.Args[2:])
+	case "icd10":
+		err = importICD10(ctx, refRepo, os.Args[2:])
+	case "medications":
+		err = importMedications(ctx, refRepo, os.Args[2:])
+	case "procedures":
+		err = importProcedures(ctx, refRepo, os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+}
+
+func importLabTests(ctx context.Context, refRepo *postgres.ReferenceRepository, args []string) error {
+	if len(args) != 1 {
+		usage()
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tests, err := importer.ParseLabTestsCSV(f)
+	if err != nil {
+		return err
+	}
+
+	if err := refRepo.UpsertLabTests(ctx, tests); err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d lab tests", len(tests))
+	return nil
+}
+
+func importICD10(ctx context.Context, refRepo *postgres.ReferenceRepository, args []string) error {
+	fs := flag.NewFlagSet("icd10", flag.ExitOnError)
+	format := fs.String("format", "", "source format: csv or claml (default: from file extension)")
+	version := fs.String("version", "", "release version, e.g. ICD-10-AM-v3 (ClaML defaults to the file's Title version)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		usage()
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".xml":
+			*format = "claml"
+		default:
+			*format = "csv"
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var release *domain.ICD10Release
+	switch *format {
+	case "csv":
+		if *version == "" {
+			return fmt.Errorf("-version is required for CSV releases")
+		}
+		release, err = importer.ParseICD10CSV(f, *version)
+	case "claml":
+		release, err = importer.ParseICD10ClaML(f, *version)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	summary, err := refRepo.ImportICD10Release(ctx, release)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Imported ICD-10 release %s: %d codes (%d added, %d changed, %d retired)",
+		summary.Version, len(release.Codes), summary.Added, summary.Changed, summary.Retired)
+	return nil
+}
+
+func importMedications(ctx context.Context, refRepo *postgres.ReferenceRepository, args []string) error {
+	fs := flag.NewFlagSet("medications", flag.ExitOnError)
+	format := fs.String("format", "", "source format: csv or xml (default: from file extension)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		usage()
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var products []domain.MedicationReference
+	switch *format {
+	case "csv":
+		products, err = importer.ParseMedicationsCSV(f)
+	case "xml":
+		products, err = importer.ParseMedicationsXML(f)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	summary, err := refRepo.UpsertMedications(ctx, products)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d medicinal products (%d added, %d updated, %d skipped)",
+		len(products), summary.Added, summary.Updated, summary.Skipped)
+	return nil
+}
+
+func importProcedures(ctx context.Context, refRepo *postgres.ReferenceRepository, args []string) error {
+	if len(args) != 1 {
+		usage()
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	procedures, err := importer.ParseProceduresCSV(f)
+	if err != nil {
+		return err
+	}
+
+	if err := refRepo.UpsertProcedures(ctx, procedures); err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d procedure codes", len(procedures))
+	return nil
+}
@@ -7,6 +7,7 @@ import Dashboard from './pages/Dashboard';
 import ReportsList from './pages/ReportsList';
 import ReportForm from './pages/ReportForm';
 import ReportDetail from './pages/ReportDetail';
+import Verify from './pages/Verify';
 
 function PrivateRoute({ children }) {
   const { user, loading } = useAuth();
@@ -57,6 +58,7 @@ function App() {
               </PublicRoute>
             }
           />
+          <Route path="/verify/:token" element={<Verify />} />
           <Route
             path="/"
             element={
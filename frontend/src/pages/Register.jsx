@@ -1,6 +1,7 @@
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
 import { useNavigate, Link } from 'react-router-dom';
 import { useAuth } from '../contexts/AuthContext';
+import { hospitalsAPI } from '../services/api';
 
 export default function Register() {
   const [formData, setFormData] = useState({
@@ -12,11 +13,18 @@ export default function Register() {
     hospitalId: '',
     specialty: '',
   });
+  const [hospitals, setHospitals] = useState([]);
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
   const { register } = useAuth();
   const navigate = useNavigate();
 
+  useEffect(() => {
+    hospitalsAPI.list()
+      .then((response) => setHospitals(response.data))
+      .catch(() => setError('Failed to load hospitals'));
+  }, []);
+
   const handleChange = (e) => {
     setFormData({ ...formData, [e.target.name]: e.target.value });
   };
@@ -110,17 +118,21 @@ export default function Register() {
 
             <div>
               <label htmlFor="hospitalId" className="block text-sm font-medium text-gray-700">
-                Hospital ID
+                Hospital
               </label>
-              <input
+              <select
                 id="hospitalId"
                 name="hospitalId"
-                type="text"
                 required
-                className="mt-1 appearance-none block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm placeholder-gray-400 focus:outline-none focus:ring-blue-500 focus:border-blue-500 sm:text-sm"
+                className="mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500 sm:text-sm"
                 value={formData.hospitalId}
                 onChange={handleChange}
-              />
+              >
+                <option value="">Select a hospital</option>
+                {hospitals.map((hospital) => (
+                  <option key={hospital.id} value={hospital.id}>{hospital.name}</option>
+                ))}
+              </select>
             </div>
 
             <div>
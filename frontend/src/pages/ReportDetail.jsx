@@ -57,6 +57,18 @@ export default function ReportDetail() {
     }
   };
 
+  const handlePrint = async () => {
+    try {
+      const response = await reportsAPI.getPdf(id);
+      const url = URL.createObjectURL(response.data);
+      window.open(url, '_blank');
+      setTimeout(() => URL.revokeObjectURL(url), 60000);
+    } catch (error) {
+      setError('Failed to render PDF');
+      console.error(error);
+    }
+  };
+
   const getStatusBadge = (status) => {
     const styles = {
       draft: 'bg-yellow-100 text-yellow-800',
@@ -112,6 +124,12 @@ export default function ReportDetail() {
             </div>
           </div>
           <div className="mt-4 flex md:mt-0 md:ml-4 gap-2">
+            <button
+              onClick={handlePrint}
+              className="inline-flex items-center px-4 py-2 border border-gray-300 rounded-md shadow-sm text-sm font-medium text-gray-700 bg-white hover:bg-gray-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+            >
+              PDF
+            </button>
             {report.status === 'draft' && (
               <>
                 <Link
@@ -240,6 +258,140 @@ export default function ReportDetail() {
                 </div>
               )}
 
+              {report.content?.operative_note && (
+                <div>
+                  <dt className="text-sm font-medium text-gray-500 mb-2">Operative Note</dt>
+                  <dd>
+                    <dl className="grid grid-cols-1 gap-x-4 gap-y-3 sm:grid-cols-2 text-sm">
+                      <div>
+                        <dt className="text-gray-500">Pre-operative diagnosis</dt>
+                        <dd className="text-gray-900">
+                          {report.content.operative_note.pre_op_diagnosis?.code} {report.content.operative_note.pre_op_diagnosis?.description}
+                        </dd>
+                      </div>
+                      <div>
+                        <dt className="text-gray-500">Post-operative diagnosis</dt>
+                        <dd className="text-gray-900">
+                          {report.content.operative_note.post_op_diagnosis?.code} {report.content.operative_note.post_op_diagnosis?.description}
+                        </dd>
+                      </div>
+                      <div>
+                        <dt className="text-gray-500">Procedures</dt>
+                        <dd className="text-gray-900">
+                          {(report.content.operative_note.procedure_codes || []).map((p) => `${p.code} ${p.description}`).join('; ')}
+                        </dd>
+                      </div>
+                      <div>
+                        <dt className="text-gray-500">Team</dt>
+                        <dd className="text-gray-900">
+                          {[report.content.operative_note.surgeon, ...(report.content.operative_note.assistants || [])].filter(Boolean).join(', ')}
+                          {report.content.operative_note.anesthesiologist && ` · ATI: ${report.content.operative_note.anesthesiologist}`}
+                        </dd>
+                      </div>
+                      <div>
+                        <dt className="text-gray-500">Anesthesia</dt>
+                        <dd className="text-gray-900 capitalize">{report.content.operative_note.anesthesia_type}</dd>
+                      </div>
+                      <div>
+                        <dt className="text-gray-500">Estimated blood loss</dt>
+                        <dd className="text-gray-900">
+                          {report.content.operative_note.estimated_blood_loss_ml != null && `${report.content.operative_note.estimated_blood_loss_ml} ml`}
+                        </dd>
+                      </div>
+                      <div className="sm:col-span-2">
+                        <dt className="text-gray-500">Incision and findings</dt>
+                        <dd className="text-gray-900 whitespace-pre-wrap">
+                          {[report.content.operative_note.incision, report.content.operative_note.findings].filter(Boolean).join('\n')}
+                        </dd>
+                      </div>
+                      <div className="sm:col-span-2">
+                        <dt className="text-gray-500">Complications</dt>
+                        <dd className="text-gray-900">{report.content.operative_note.complications}</dd>
+                      </div>
+                    </dl>
+                  </dd>
+                </div>
+              )}
+
+              {report.content?.transfer && (
+                <div>
+                  <dt className="text-sm font-medium text-gray-500 mb-2">Transfer</dt>
+                  <dd className="space-y-2 text-sm text-gray-900">
+                    <div>
+                      {report.content.transfer.source?.department} →{' '}
+                      {[report.content.transfer.destination?.hospital, report.content.transfer.destination?.department].filter(Boolean).join(', ')}
+                    </div>
+                    <div className="whitespace-pre-wrap">{report.content.transfer.reason}</div>
+                    <div className="whitespace-pre-wrap text-gray-600">{report.content.transfer.current_status}</div>
+                    {report.content.transfer.pending_investigations?.length > 0 && (
+                      <div>
+                        <span className="text-gray-500">Pending investigations: </span>
+                        {report.content.transfer.pending_investigations.map((i) => i.name).join(', ')}
+                      </div>
+                    )}
+                    {report.content.transfer.handoff_tasks?.length > 0 && (
+                      <ul className="list-disc list-inside">
+                        {report.content.transfer.handoff_tasks.map((task, index) => (
+                          <li key={index}>{task.description}</li>
+                        ))}
+                      </ul>
+                    )}
+                  </dd>
+                </div>
+              )}
+
+              {report.drg && (
+                <div>
+                  <dt className="text-sm font-medium text-gray-500 mb-2">DRG</dt>
+                  {report.drg.ungroupable_reason ? (
+                    <dd className="text-sm text-orange-800">
+                      Not grouped: {report.drg.ungroupable_reason}
+                    </dd>
+                  ) : (
+                    <dd className="text-sm text-gray-900">
+                      <span className="font-mono">{report.drg.code}</span> {report.drg.description}
+                      <div className="text-gray-600">
+                        Relative weight {report.drg.relative_weight} · {report.drg.length_of_stay_days} days · {report.drg.grouper_version}
+                      </div>
+                    </dd>
+                  )}
+                </div>
+              )}
+
+              {report.reconciliation_table && report.reconciliation_table.length > 0 && (
+                <div>
+                  <dt className="text-sm font-medium text-gray-500 mb-2">Medication Reconciliation</dt>
+                  <dd className="overflow-x-auto">
+                    <table className="min-w-full divide-y divide-gray-200 text-sm">
+                      <thead className="bg-gray-50">
+                        <tr>
+                          <th className="px-3 py-2 text-left font-medium text-gray-500">Pre-admission</th>
+                          <th className="px-3 py-2 text-left font-medium text-gray-500">In hospital</th>
+                          <th className="px-3 py-2 text-left font-medium text-gray-500">Discharge</th>
+                          <th className="px-3 py-2 text-left font-medium text-gray-500">Status</th>
+                          <th className="px-3 py-2 text-left font-medium text-gray-500">Reason</th>
+                        </tr>
+                      </thead>
+                      <tbody className="divide-y divide-gray-200">
+                        {report.reconciliation_table.map((row, index) => (
+                          <tr key={index}>
+                            <td className="px-3 py-2 text-gray-900">{row.pre_admission || '—'}</td>
+                            <td className="px-3 py-2 text-gray-900">{row.in_hospital || '—'}</td>
+                            <td className="px-3 py-2 text-gray-900">{row.discharge || '—'}</td>
+                            <td className="px-3 py-2">
+                              <span className={row.confirmed ? 'font-medium text-gray-900' : 'italic text-gray-500'}>
+                                {row.status}
+                              </span>
+                            </td>
+                            <td className="px-3 py-2 text-gray-600">{row.reason}</td>
+                          </tr>
+                        ))}
+                      </tbody>
+                    </table>
+                  </dd>
+                </div>
+              )}
+
               {report.content?.notes && (
                 <div>
                   <dt className="text-sm font-medium text-gray-500 mb-1">Additional Notes</dt>
@@ -0,0 +1,83 @@
+import { useEffect, useState } from 'react';
+import { useParams } from 'react-router-dom';
+import { verificationAPI } from '../services/api';
+
+const REPORT_TYPES = {
+  discharge_summary: 'Discharge summary',
+  transfer_summary: 'Transfer summary',
+  operative_note: 'Operative note',
+};
+
+export default function Verify() {
+  const { token } = useParams();
+  const [verification, setVerification] = useState(null);
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(true);
+
+  useEffect(() => {
+    verificationAPI
+      .verify(token)
+      .then((response) => setVerification(response.data))
+      .catch((err) => {
+        setError(
+          err.response?.status === 404
+            ? 'No signed report matches this verification code. The document may not be genuine.'
+            : 'The report could not be verified. Please try again later.'
+        );
+      })
+      .finally(() => setLoading(false));
+  }, [token]);
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gradient-to-br from-blue-50 to-indigo-100 py-12 px-4">
+      <div className="max-w-lg w-full bg-white p-8 rounded-xl shadow-lg">
+        <h2 className="text-2xl font-bold text-gray-900 mb-6">Report verification</h2>
+
+        {loading && <div className="text-gray-500">Verifying...</div>}
+
+        {error && (
+          <div className="rounded-md bg-red-50 p-4">
+            <div className="text-sm text-red-800">{error}</div>
+          </div>
+        )}
+
+        {verification && (
+          <>
+            <div className="rounded-md bg-green-50 p-4 mb-6">
+              <div className="text-sm text-green-800">
+                This report was signed electronically and is genuine.
+              </div>
+            </div>
+            <dl className="space-y-3 text-sm">
+              <div>
+                <dt className="font-medium text-gray-500">Report</dt>
+                <dd className="text-gray-900">
+                  {REPORT_TYPES[verification.report_type] || verification.report_type} · {verification.report_id}
+                </dd>
+              </div>
+              <div>
+                <dt className="font-medium text-gray-500">Hospital</dt>
+                <dd className="text-gray-900">{verification.hospital}</dd>
+              </div>
+              <div>
+                <dt className="font-medium text-gray-500">Signed</dt>
+                <dd className="text-gray-900">{new Date(verification.signed_at).toLocaleString('ro-RO')}</dd>
+              </div>
+              <div>
+                <dt className="font-medium text-gray-500">Signed by</dt>
+                <dd className="text-gray-900">{verification.signer || '—'}</dd>
+              </div>
+              <div>
+                <dt className="font-medium text-gray-500">Content hash (SHA-256)</dt>
+                <dd className="text-gray-900 font-mono break-all">{verification.content_hash}</dd>
+              </div>
+            </dl>
+            <p className="mt-6 text-xs text-gray-500">
+              Compare the beginning of the content hash with the one printed next to the QR code.
+            </p>
+          </>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -44,16 +44,91 @@ export const reportsAPI = {
   list: (params) => api.get('/reports', { params }),
   get: (id) => api.get(`/reports/${id}`),
   create: (data) => api.post('/reports', data),
+  createFromPrevious: (data) => api.post('/reports/carry-forward', data),
+  importFhir: (data) => api.post('/reports/fhir', data),
   update: (id, data) => api.put(`/reports/${id}`, data),
   delete: (id) => api.delete(`/reports/${id}`),
   finalize: (id) => api.post(`/reports/${id}/finalize`),
   getVersions: (id) => api.get(`/reports/${id}/versions`),
   getVersion: (id, version) => api.get(`/reports/${id}/versions/${version}`),
+  getPdf: (id) => api.get(`/reports/${id}/pdf`, { responseType: 'blob' }),
+  getHtml: (id) => api.get(`/reports/${id}/html`, { responseType: 'text' }),
+  getFhir: (id) => api.get(`/reports/${id}/fhir`),
 };
 
 // Reference Data API
 export const referenceAPI = {
-  searchICD10: (query) => api.get('/reference/icd10', { params: { q: query } }),
+  searchICD10: (query, chapter) => api.get('/reference/icd10', { params: { q: query, chapter } }),
+  getICD10Chapters: () => api.get('/reference/icd10/chapters'),
+  getICD10Children: (code) => api.get(`/reference/icd10/${encodeURIComponent(code)}/children`),
+  getICD10Ancestors: (code) => api.get(`/reference/icd10/${encodeURIComponent(code)}/ancestors`),
+  searchProcedures: (query) => api.get('/reference/procedures', { params: { q: query } }),
+  getProcedure: (code) => api.get(`/reference/procedures/${encodeURIComponent(code)}`),
+};
+
+// Patient Registry API
+export const patientsAPI = {
+  search: (hospitalId, params) => api.get('/patients', { params: { hospital_id: hospitalId, ...params } }),
+  get: (hospitalId, id) => api.get(`/patients/${id}`, { params: { hospital_id: hospitalId } }),
+  create: (data) => api.post('/patients', data),
+  update: (hospitalId, id, data) => api.put(`/patients/${id}`, data, { params: { hospital_id: hospitalId } }),
+  getReports: (hospitalId, id) => api.get(`/patients/${id}/reports`, { params: { hospital_id: hospitalId } }),
+  getTimeline: (hospitalId, cnp) => api.get('/patients/timeline', { params: { hospital_id: hospitalId, cnp } }),
+};
+
+// Encounters API
+export const encountersAPI = {
+  admit: (data) => api.post('/encounters', data),
+  get: (hospitalId, id) => api.get(`/encounters/${id}`, { params: { hospital_id: hospitalId } }),
+  transfer: (hospitalId, id, data) => api.post(`/encounters/${id}/transfer`, data, { params: { hospital_id: hospitalId } }),
+  discharge: (hospitalId, id, data) => api.post(`/encounters/${id}/discharge`, data, { params: { hospital_id: hospitalId } }),
+  getReports: (hospitalId, id) => api.get(`/encounters/${id}/reports`, { params: { hospital_id: hospitalId } }),
+  listByPatient: (hospitalId, patientId) => api.get(`/patients/${patientId}/encounters`, { params: { hospital_id: hospitalId } }),
+};
+
+// Hospital master data API
+export const hospitalsAPI = {
+  list: () => api.get('/hospitals'),
+  get: (id) => api.get(`/hospitals/${id}`),
+  getDepartments: (id) => api.get(`/hospitals/${id}/departments`),
+  getHandoffs: (id, departmentId, params) => api.get(`/hospitals/${id}/departments/${departmentId}/handoffs`, { params }),
+  acknowledgeHandoff: (id, departmentId, handoffId) =>
+    api.post(`/hospitals/${id}/departments/${departmentId}/handoffs/${handoffId}/acknowledge`),
+  logoUrl: (id) => `${API_BASE_URL}/hospitals/${id}/logo`,
+};
+
+// Public verification of printed reports
+export const verificationAPI = {
+  verify: (token) => api.get(`/verify/${encodeURIComponent(token)}`),
+};
+
+export const adminAPI = {
+  scanDuplicates: (hospitalId) => api.post('/admin/patients/duplicates/scan', null, { params: { hospital_id: hospitalId } }),
+  listDuplicates: (hospitalId, params) => api.get('/admin/patients/duplicates', { params: { hospital_id: hospitalId, ...params } }),
+  dismissDuplicate: (hospitalId, id) => api.post(`/admin/patients/duplicates/${id}/dismiss`, null, { params: { hospital_id: hospitalId } }),
+  mergePatients: (data) => api.post('/admin/patients/merges', data),
+  listMerges: (hospitalId, params) => api.get('/admin/patients/merges', { params: { hospital_id: hospitalId, ...params } }),
+  revertMerge: (hospitalId, id) => api.post(`/admin/patients/merges/${id}/revert`, null, { params: { hospital_id: hospitalId } }),
+  createHospital: (data) => api.post('/admin/hospitals', data),
+  updateHospital: (id, data) => api.put(`/admin/hospitals/${id}`, data),
+  deleteHospital: (id) => api.delete(`/admin/hospitals/${id}`),
+  uploadLogo: (id, file) => api.put(`/admin/hospitals/${id}/logo`, file, { headers: { 'Content-Type': file.type } }),
+  deleteLogo: (id) => api.delete(`/admin/hospitals/${id}/logo`),
+  listPrintLayouts: (hospitalId) => api.get(`/admin/hospitals/${hospitalId}/print-layouts`),
+  getPrintLayout: (hospitalId, reportType) => api.get(`/admin/hospitals/${hospitalId}/print-layouts/${reportType}`),
+  savePrintLayout: (hospitalId, reportType, source) => api.put(`/admin/hospitals/${hospitalId}/print-layouts/${reportType}`, { source }),
+  deletePrintLayout: (hospitalId, reportType) => api.delete(`/admin/hospitals/${hospitalId}/print-layouts/${reportType}`),
+  previewPrintLayout: (hospitalId, reportType, data) => api.post(`/admin/hospitals/${hospitalId}/print-layouts/${reportType}/preview`, data, { responseType: 'text' }),
+  createDepartment: (hospitalId, data) => api.post(`/admin/hospitals/${hospitalId}/departments`, data),
+  updateDepartment: (hospitalId, id, data) => api.put(`/admin/hospitals/${hospitalId}/departments/${id}`, data),
+  deleteDepartment: (hospitalId, id) => api.delete(`/admin/hospitals/${hospitalId}/departments/${id}`),
+  listTemplates: (hospitalId, params) => api.get('/admin/templates', { params: { hospital_id: hospitalId, ...params } }),
+  getTemplate: (hospitalId, id) => api.get(`/admin/templates/${id}`, { params: { hospital_id: hospitalId } }),
+  createTemplate: (hospitalId, data) => api.post('/admin/templates', data, { params: { hospital_id: hospitalId } }),
+  updateTemplate: (hospitalId, id, data) => api.put(`/admin/templates/${id}`, data, { params: { hospital_id: hospitalId } }),
+  getTemplateVersions: (hospitalId, id) => api.get(`/admin/templates/${id}/versions`, { params: { hospital_id: hospitalId } }),
+  activateTemplate: (hospitalId, id) => api.post(`/admin/templates/${id}/activate`, null, { params: { hospital_id: hospitalId } }),
+  deactivateTemplate: (hospitalId, id) => api.post(`/admin/templates/${id}/deactivate`, null, { params: { hospital_id: hospitalId } }),
 };
 
 export default api;
@@ -0,0 +1,106 @@
+package domain
+
+import "strings"
+
+// AllergySeverity grades a recorded allergic reaction
+type AllergySeverity string
+
+const (
+	AllergyMild        AllergySeverity = "mild"
+	AllergyModerate    AllergySeverity = "moderate"
+	AllergySevere      AllergySeverity = "severe"
+	AllergyAnaphylaxis AllergySeverity = "anaphylaxis"
+)
+
+// Allergy is a structured allergy or intolerance entry. Either a specific
+// substance ("Amoxicilină") or a drug class ("peniciline") must be given.
+type Allergy struct {
+	Substance string          `json:"substance,omitempty"`
+	DrugClass string          `json:"drug_class,omitempty"`
+	Reaction  string          `json:"reaction,omitempty"`
+	Severity  AllergySeverity `json:"severity,omitempty"`
+}
+
+func (a Allergy) Validate() error {
+	if strings.TrimSpace(a.Substance) == "" && strings.TrimSpace(a.DrugClass) == "" {
+		return ErrEmptyField
+	}
+	switch a.Severity {
+	case "", AllergyMild, AllergyModerate, AllergySevere, AllergyAnaphylaxis:
+		return nil
+	}
+	return ErrInvalidAllergySeverity
+}
+
+// Label returns the allergen as written by the doctor
+func (a Allergy) Label() string {
+	if a.Substance != "" {
+		return a.Substance
+	}
+	return a.DrugClass
+}
+
+// DrugClass groups substances that commonly cross-react, identified by ATC prefixes
+type DrugClass struct {
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	Aliases     []string `json:"-"`
+	ATCPrefixes []string `json:"atc_prefixes"`
+}
+
+// drugClasses lists the classes relevant for allergy cross-reactivity.
+// Aliases are matched diacritics-insensitively against what doctors write.
+var drugClasses = []DrugClass{
+	{Code: "penicillins", Name: "Peniciline", Aliases: []string{"penicilina", "peniciline", "penicilinele", "penicillin", "penicillins"}, ATCPrefixes: []string{"J01C"}},
+	{Code: "cephalosporins", Name: "Cefalosporine", Aliases: []string{"cefalosporina", "cefalosporine", "cephalosporins"}, ATCPrefixes: []string{"J01DB", "J01DC", "J01DD", "J01DE", "J01DI"}},
+	{Code: "carbapenems", Name: "Carbapeneme", Aliases: []string{"carbapeneme", "carbapenems"}, ATCPrefixes: []string{"J01DH"}},
+	{Code: "beta_lactams", Name: "Beta-lactamine", Aliases: []string{"beta-lactamine", "betalactamine", "beta lactamine", "beta-lactams"}, ATCPrefixes: []string{"J01C", "J01D"}},
+	{Code: "sulfonamides", Name: "Sulfamide", Aliases: []string{"sulfamide", "sulfonamide", "sulfonamides", "sulfamidele"}, ATCPrefixes: []string{"J01E"}},
+	{Code: "macrolides", Name: "Macrolide", Aliases: []string{"macrolide", "macrolides"}, ATCPrefixes: []string{"J01FA"}},
+	{Code: "quinolones", Name: "Chinolone", Aliases: []string{"chinolone", "fluorochinolone", "quinolones", "fluoroquinolones"}, ATCPrefixes: []string{"J01M"}},
+	{Code: "tetracyclines", Name: "Tetracicline", Aliases: []string{"tetracicline", "tetracyclines"}, ATCPrefixes: []string{"J01A"}},
+	{Code: "aminoglycosides", Name: "Aminoglicozide", Aliases: []string{"aminoglicozide", "aminoglycosides"}, ATCPrefixes: []string{"J01G"}},
+	{Code: "nsaids", Name: "Antiinflamatoare nesteroidiene", Aliases: []string{"ains", "aine", "antiinflamatoare nesteroidiene", "nsaid", "nsaids"}, ATCPrefixes: []string{"M01A", "N02BA", "B01AC06"}},
+	{Code: "salicylates", Name: "Salicilați", Aliases: []string{"salicilati", "salicylates", "aspirina"}, ATCPrefixes: []string{"N02BA", "B01AC06"}},
+	{Code: "ace_inhibitors", Name: "Inhibitori ai enzimei de conversie", Aliases: []string{"ieca", "inhibitori ai enzimei de conversie", "ace inhibitors"}, ATCPrefixes: []string{"C09A", "C09B"}},
+	{Code: "statins", Name: "Statine", Aliases: []string{"statine", "statins"}, ATCPrefixes: []string{"C10AA"}},
+	{Code: "opioids", Name: "Opioide", Aliases: []string{"opioide", "opiacee", "opioids"}, ATCPrefixes: []string{"N02A"}},
+	{Code: "iodinated_contrast", Name: "Substanțe de contrast iodate", Aliases: []string{"substanta de contrast", "substante de contrast iodate", "contrast iodat", "iod"}, ATCPrefixes: []string{"V08A"}},
+}
+
+// LookupDrugClass finds a class by code or by any of its Romanian/English names
+func LookupDrugClass(name string) (DrugClass, bool) {
+	folded := FoldText(name)
+	if folded == "" {
+		return DrugClass{}, false
+	}
+	for _, class := range drugClasses {
+		if folded == class.Code || folded == FoldText(class.Name) {
+			return class, true
+		}
+		for _, alias := range class.Aliases {
+			if folded == alias {
+				return class, true
+			}
+		}
+	}
+	return DrugClass{}, false
+}
+
+// DrugClassesForATC returns the classes an ATC-coded product belongs to
+func DrugClassesForATC(atcCode string) []DrugClass {
+	atcCode = strings.ToUpper(strings.TrimSpace(atcCode))
+	if atcCode == "" {
+		return nil
+	}
+	var classes []DrugClass
+	for _, class := range drugClasses {
+		for _, prefix := range class.ATCPrefixes {
+			if strings.HasPrefix(atcCode, prefix) {
+				classes = append(classes, class)
+				break
+			}
+		}
+	}
+	return classes
+}
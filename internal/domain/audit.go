@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit event types
+const (
+	AuditInteractionOverride = "interaction_override"
+	AuditPatientMerged       = "patient_merged"
+	AuditPatientUnmerged     = "patient_unmerged"
+)
+
+// AuditEntry is an append-only record of a security- or safety-relevant event.
+// Patient-level events carry a PatientID and may have no report.
+type AuditEntry struct {
+	ID        uuid.UUID              `json:"id"`
+	ReportID  uuid.UUID              `json:"report_id"`
+	PatientID *uuid.UUID             `json:"patient_id,omitempty"`
+	EventType string                 `json:"event_type"`
+	EventData map[string]interface{} `json:"event_data"`
+	UserID    uuid.UUID              `json:"user_id"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// NewAuditEntry creates an audit entry stamped with the current time
+func NewAuditEntry(reportID uuid.UUID, eventType string, data map[string]interface{}, userID uuid.UUID) *AuditEntry {
+	return &AuditEntry{
+		ID:        uuid.New(),
+		ReportID:  reportID,
+		EventType: eventType,
+		EventData: data,
+		UserID:    userID,
+		Timestamp: time.Now(),
+	}
+}
+
+// NewPatientAuditEntry records an event on a patient, optionally tied to one
+// of their reports (uuid.Nil for none)
+func NewPatientAuditEntry(patientID, reportID uuid.UUID, eventType string, data map[string]interface{}, userID uuid.UUID) *AuditEntry {
+	entry := NewAuditEntry(reportID, eventType, data, userID)
+	entry.PatientID = &patientID
+	return entry
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CarryForwardSection is a part of a previous report that can prefill a new
+// one for a readmitted patient
+type CarryForwardSection string
+
+const (
+	CarryPastMedicalHistory CarryForwardSection = "past_medical_history"
+	CarryAllergies          CarryForwardSection = "allergies"
+	CarryChronicMedications CarryForwardSection = "chronic_medications"
+)
+
+// CarryForwardSections lists every section that can be carried forward
+var CarryForwardSections = []CarryForwardSection{
+	CarryPastMedicalHistory,
+	CarryAllergies,
+	CarryChronicMedications,
+}
+
+func (s CarryForwardSection) IsValid() bool {
+	for _, section := range CarryForwardSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// Field is the content field the section fills
+func (s CarryForwardSection) Field() string {
+	switch s {
+	case CarryPastMedicalHistory:
+		return "anamnesis.past_medical_history"
+	case CarryAllergies:
+		return "anamnesis.allergies"
+	case CarryChronicMedications:
+		return "medication_reconciliation.pre_admission"
+	}
+	return string(s)
+}
+
+// AppliesTo reports whether reports of the type store the section's field
+func (s CarryForwardSection) AppliesTo(t ReportType) bool {
+	section, _, _ := strings.Cut(s.Field(), ".")
+	return t.Content().HasSection(section)
+}
+
+// CarriedField records where a prefilled field came from. Edited turns true
+// once the field no longer holds the carried value.
+type CarriedField struct {
+	Section        CarryForwardSection `json:"section"`
+	Field          string              `json:"field"`
+	SourceReportID uuid.UUID           `json:"source_report_id"`
+	SourceSignedAt *time.Time          `json:"source_signed_at,omitempty"`
+	CarriedAt      time.Time           `json:"carried_at"`
+	Fingerprint    string              `json:"fingerprint"`
+	Edited         bool                `json:"edited"`
+}
+
+// CarryForward copies the selected sections of a signed report into the
+// content and records their provenance. Empty sections of the source are
+// skipped. Chronic medications are the ones the patient was discharged with,
+// falling back to the source's own pre-admission list.
+func (c *ReportContent) CarryForward(source *Report, sections []CarryForwardSection) error {
+	if source.Status != StatusSigned {
+		return fmt.Errorf("%w: source report is %s", ErrInvalidCarryForward, source.Status)
+	}
+
+	now := time.Now()
+	for _, section := range sections {
+		if !section.IsValid() {
+			return fmt.Errorf("%w: unknown section %q", ErrInvalidCarryForward, section)
+		}
+
+		switch section {
+		case CarryPastMedicalHistory:
+			if source.Content.Anamnesis.PastMedicalHistory == "" {
+				continue
+			}
+			c.Anamnesis.PastMedicalHistory = source.Content.Anamnesis.PastMedicalHistory
+		case CarryAllergies:
+			if source.Content.Anamnesis.Allergies == "" && len(source.Content.Anamnesis.AllergyList) == 0 {
+				continue
+			}
+			c.Anamnesis.Allergies = source.Content.Anamnesis.Allergies
+			c.Anamnesis.AllergyList = append([]Allergy(nil), source.Content.Anamnesis.AllergyList...)
+		case CarryChronicMedications:
+			meds := source.Content.MedicationReconciliation.Discharge
+			if len(meds) == 0 {
+				meds = source.Content.MedicationReconciliation.PreAdmission
+			}
+			if len(meds) == 0 {
+				continue
+			}
+			c.MedicationReconciliation.PreAdmission = chronicMedications(meds, now)
+		}
+
+		c.CarriedForward = append(c.carriedWithout(section), CarriedField{
+			Section:        section,
+			Field:          section.Field(),
+			SourceReportID: source.ID,
+			SourceSignedAt: source.FinalizedAt,
+			CarriedAt:      now,
+			Fingerprint:    c.fingerprint(section),
+		})
+	}
+	return nil
+}
+
+// KeepProvenance restores the provenance recorded on the stored content, so
+// clients cannot alter it, and flags the carried fields edited since
+func (c *ReportContent) KeepProvenance(stored ReportContent) {
+	c.CarriedForward = nil
+	for _, field := range stored.CarriedForward {
+		field.Edited = field.Edited || c.fingerprint(field.Section) != field.Fingerprint
+		c.CarriedForward = append(c.CarriedForward, field)
+	}
+}
+
+func (c *ReportContent) carriedWithout(section CarryForwardSection) []CarriedField {
+	var fields []CarriedField
+	for _, field := range c.CarriedForward {
+		if field.Section != section {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// fingerprint hashes the current value of a section's fields
+func (c *ReportContent) fingerprint(section CarryForwardSection) string {
+	var value interface{}
+	switch section {
+	case CarryPastMedicalHistory:
+		value = c.Anamnesis.PastMedicalHistory
+	case CarryAllergies:
+		value = []interface{}{c.Anamnesis.Allergies, c.Anamnesis.AllergyList}
+	case CarryChronicMedications:
+		value = c.MedicationReconciliation.PreAdmission
+	}
+
+	data, _ := json.Marshal(value)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// chronicMedications drops the courses of a discharge list that have ended
+func chronicMedications(meds []Medication, at time.Time) []Medication {
+	var chronic []Medication
+	for _, m := range meds {
+		if m.EndDate != nil && m.EndDate.Before(at) {
+			continue
+		}
+		chronic = append(chronic, m)
+	}
+	return chronic
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// Sex represents the administrative sex of a patient
+type Sex string
+
+const (
+	SexMale    Sex = "M"
+	SexFemale  Sex = "F"
+	SexUnknown Sex = ""
+)
+
+// SexFromCNP derives the patient's sex from the first digit of a Romanian CNP.
+// Odd digits are male, even digits are female; foreign residents use 7/8.
+func SexFromCNP(cnp string) Sex {
+	if len(cnp) != 13 {
+		return SexUnknown
+	}
+	switch cnp[0] {
+	case '1', '3', '5', '7':
+		return SexMale
+	case '2', '4', '6', '8':
+		return SexFemale
+	}
+	return SexUnknown
+}
+
+// BirthDateFromCNP derives the birth date encoded in a Romanian CNP.
+// The first digit selects the century, followed by YYMMDD.
+func BirthDateFromCNP(cnp string) (time.Time, bool) {
+	if len(cnp) != 13 {
+		return time.Time{}, false
+	}
+
+	yy, err1 := strconv.Atoi(cnp[1:3])
+	mm, err2 := strconv.Atoi(cnp[3:5])
+	dd, err3 := strconv.Atoi(cnp[5:7])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return time.Time{}, false
+	}
+
+	var century int
+	switch cnp[0] {
+	case '1', '2':
+		century = 1900
+	case '3', '4':
+		century = 1800
+	case '5', '6':
+		century = 2000
+	case '7', '8':
+		// Foreign residents carry no century marker; assume the most recent
+		// century that does not place the birth date in the future.
+		century = 2000
+		if century+yy > time.Now().Year() {
+			century = 1900
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	date := time.Date(century+yy, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+	if int(date.Month()) != mm || date.Day() != dd {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// ValidCNPChecksum verifies the control digit of a Romanian CNP
+func ValidCNPChecksum(cnp string) bool {
+	if len(cnp) != 13 {
+		return false
+	}
+	const weights = "279146358279"
+	sum := 0
+	for i := 0; i < 12; i++ {
+		if cnp[i] < '0' || cnp[i] > '9' {
+			return false
+		}
+		sum += int(cnp[i]-'0') * int(weights[i]-'0')
+	}
+	control := sum % 11
+	if control == 10 {
+		control = 1
+	}
+	return cnp[12] == byte('0'+control)
+}
+
+// AgeAt returns the age in full years at the given moment
+func AgeAt(birthDate, at time.Time) int {
+	age := at.Year() - birthDate.Year()
+	if at.Month() < birthDate.Month() || (at.Month() == birthDate.Month() && at.Day() < birthDate.Day()) {
+		age--
+	}
+	if age < 0 {
+		return 0
+	}
+	return age
+}
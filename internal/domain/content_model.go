@@ -0,0 +1,190 @@
+package domain
+
+// ContentModel describes the content of a report type: the sections its
+// reports store and the rules a complete report satisfies
+type ContentModel struct {
+	Sections []string
+	validate func(c ReportContent) error
+}
+
+// HasSection reports whether reports of the type store the section
+func (m ContentModel) HasSection(name string) bool {
+	for _, section := range m.Sections {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
+
+// clinicalSections make up discharge-oriented reports
+var clinicalSections = []string{
+	"patient_data",
+	"anamnesis",
+	"examination",
+	"lab_results",
+	"diagnosis",
+	"treatment",
+	"recommendations",
+	"medication_reconciliation",
+}
+
+var contentModels = map[ReportType]ContentModel{
+	ReportTypeDischargeSummary: {
+		Sections: clinicalSections,
+		validate: ReportContent.Validate,
+	},
+	ReportTypeTransferSummary: {
+		Sections: []string{
+			"patient_data",
+			"anamnesis",
+			"lab_results",
+			"diagnosis",
+			"treatment",
+			"medication_reconciliation",
+			"transfer",
+		},
+		validate: validateTransferContent,
+	},
+	ReportTypeOperativeNote: {
+		Sections: []string{"patient_data", "operative_note"},
+		validate: validateOperativeContent,
+	},
+}
+
+// contentMetadata is kept on every report type
+var contentMetadata = []string{"carried_forward", "checklist"}
+
+// Content returns the content model of the report type
+func (t ReportType) Content() ContentModel {
+	return contentModels[t]
+}
+
+// NewContent returns empty content with the sections of the report type
+func NewContent(t ReportType) ReportContent {
+	var content ReportContent
+	content.Normalize(t)
+	return content
+}
+
+// ValidateFor checks that the content is complete for the report type
+func (c ReportContent) ValidateFor(t ReportType) error {
+	model, ok := contentModels[t]
+	if !ok {
+		return ErrInvalidReportType
+	}
+	return model.validate(c)
+}
+
+// Normalize drops the sections the report type does not store and adds the
+// type-specific sections it does. Legacy sections named in keep are kept.
+func (c *ReportContent) Normalize(t ReportType, keep ...string) {
+	model := t.Content()
+	if model.HasSection("operative_note") && c.OperativeNote == nil {
+		c.OperativeNote = &OperativeNoteSection{}
+	}
+	if model.HasSection("transfer") && c.Transfer == nil {
+		c.Transfer = &TransferSection{}
+	}
+
+	fields := contentFields(*c)
+	for name := range fields {
+		if !model.HasSection(name) && !isContentMetadata(name) && !contains(keep, name) {
+			delete(fields, name)
+		}
+	}
+
+	var normalized ReportContent
+	if err := remarshal(fields, &normalized); err == nil {
+		*c = normalized
+	}
+}
+
+// LegacySections are the clinical sections the content holds although its
+// report type no longer stores them: operative notes and transfer summaries
+// were written with every clinical section before they had their own content
+// models. Only sections holding data count.
+func (c ReportContent) LegacySections(t ReportType) []string {
+	model := t.Content()
+	fields := contentFields(c)
+	var legacy []string
+	for _, name := range clinicalSections {
+		if !model.HasSection(name) && !isEmptyJSON(fields[name]) {
+			legacy = append(legacy, name)
+		}
+	}
+	return legacy
+}
+
+// Sections lists the sections to print or export, in clinical order: the
+// sections of the report type's model together with its legacy sections
+func (c ReportContent) Sections(t ReportType) []string {
+	model := t.Content()
+	legacy := c.LegacySections(t)
+
+	var sections []string
+	for _, name := range clinicalSections {
+		if model.HasSection(name) || contains(legacy, name) {
+			sections = append(sections, name)
+		}
+	}
+	for _, name := range model.Sections {
+		if !contains(sections, name) {
+			sections = append(sections, name)
+		}
+	}
+	return sections
+}
+
+// KeepLegacySections carries the legacy sections of the stored content into
+// an update, so saving a report written before its type had its own content
+// model never drops clinical text. A legacy section the update leaves empty
+// keeps its stored value. It returns the sections Normalize has to keep.
+func (c *ReportContent) KeepLegacySections(stored ReportContent, t ReportType) []string {
+	legacy := stored.LegacySections(t)
+	if len(legacy) == 0 {
+		return nil
+	}
+
+	fields := contentFields(*c)
+	storedFields := contentFields(stored)
+	for _, name := range legacy {
+		if isEmptyJSON(fields[name]) {
+			fields[name] = storedFields[name]
+		}
+	}
+
+	var kept ReportContent
+	if err := remarshal(fields, &kept); err == nil {
+		*c = kept
+	}
+	return legacy
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isContentMetadata(name string) bool {
+	for _, field := range contentMetadata {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateOperativeContent(c ReportContent) error {
+	if err := c.PatientData.Validate(); err != nil {
+		return err
+	}
+	if c.OperativeNote == nil {
+		return ErrInvalidOperativeNote
+	}
+	return c.OperativeNote.Validate()
+}
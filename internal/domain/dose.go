@@ -0,0 +1,434 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DoseRoute is the route of administration
+type DoseRoute string
+
+const (
+	RouteOral          DoseRoute = "oral"
+	RouteSublingual    DoseRoute = "sublingual"
+	RouteIntravenous   DoseRoute = "intravenous"
+	RouteIntramuscular DoseRoute = "intramuscular"
+	RouteSubcutaneous  DoseRoute = "subcutaneous"
+	RouteInhalation    DoseRoute = "inhalation"
+	RouteTopical       DoseRoute = "topical"
+	RouteRectal        DoseRoute = "rectal"
+	RouteOphthalmic    DoseRoute = "ophthalmic"
+)
+
+// routeAliases maps the abbreviations used in Romanian prescriptions, already folded
+var routeAliases = map[string]DoseRoute{
+	"oral": RouteOral, "po": RouteOral, "p.o.": RouteOral, "per os": RouteOral,
+	"sublingual": RouteSublingual, "sl": RouteSublingual, "s.l.": RouteSublingual,
+	"intravenous": RouteIntravenous, "intravenos": RouteIntravenous, "iv": RouteIntravenous, "i.v.": RouteIntravenous,
+	"intramuscular": RouteIntramuscular, "im": RouteIntramuscular, "i.m.": RouteIntramuscular,
+	"subcutaneous": RouteSubcutaneous, "subcutanat": RouteSubcutaneous, "sc": RouteSubcutaneous, "s.c.": RouteSubcutaneous,
+	"inhalation": RouteInhalation, "inhalator": RouteInhalation, "inhalat": RouteInhalation,
+	"topical": RouteTopical, "topic": RouteTopical, "local": RouteTopical, "cutanat": RouteTopical,
+	"rectal": RouteRectal, "intrarectal": RouteRectal,
+	"ophthalmic": RouteOphthalmic, "oftalmic": RouteOphthalmic, "conjunctival": RouteOphthalmic,
+}
+
+// ParseRoute maps a free-text route ("p.o.", "i.v.", "oral") to the enum
+func ParseRoute(s string) (DoseRoute, bool) {
+	route, ok := routeAliases[FoldText(s)]
+	return route, ok
+}
+
+func (r DoseRoute) IsValid() bool {
+	switch r {
+	case RouteOral, RouteSublingual, RouteIntravenous, RouteIntramuscular, RouteSubcutaneous,
+		RouteInhalation, RouteTopical, RouteRectal, RouteOphthalmic:
+		return true
+	}
+	return false
+}
+
+// routeLabels are the abbreviations printed in Romanian letters
+var routeLabels = map[DoseRoute]string{
+	RouteOral:          "oral",
+	RouteSublingual:    "sublingual",
+	RouteIntravenous:   "i.v.",
+	RouteIntramuscular: "i.m.",
+	RouteSubcutaneous:  "s.c.",
+	RouteInhalation:    "inhalator",
+	RouteTopical:       "topic",
+	RouteRectal:        "intrarectal",
+	RouteOphthalmic:    "oftalmic",
+}
+
+// Label returns the route as printed in letters ("i.v.", "s.c.")
+func (r DoseRoute) Label() string {
+	if label, ok := routeLabels[r]; ok {
+		return label
+	}
+	return string(r)
+}
+
+// DoseUnit is either a mass/volume unit or a unit of presentation (tablet, vial...)
+type DoseUnit string
+
+const (
+	UnitMilligram   DoseUnit = "mg"
+	UnitGram        DoseUnit = "g"
+	UnitMicrogram   DoseUnit = "mcg"
+	UnitMillilitre  DoseUnit = "ml"
+	UnitIU          DoseUnit = "ui"
+	UnitTablet      DoseUnit = "cp"
+	UnitCapsule     DoseUnit = "caps"
+	UnitVial        DoseUnit = "fiola"
+	UnitSachet      DoseUnit = "plic"
+	UnitDrop        DoseUnit = "pic"
+	UnitPuff        DoseUnit = "puf"
+	UnitSuppository DoseUnit = "sup"
+)
+
+var unitAliases = map[string]DoseUnit{
+	"mg": UnitMilligram, "g": UnitGram, "gr": UnitGram, "mcg": UnitMicrogram, "µg": UnitMicrogram, "ug": UnitMicrogram,
+	"ml": UnitMillilitre, "ui": UnitIU, "u": UnitIU,
+	"cp": UnitTablet, "cpr": UnitTablet, "tb": UnitTablet, "tab": UnitTablet, "comprimat": UnitTablet, "comprimate": UnitTablet,
+	"caps": UnitCapsule, "cps": UnitCapsule, "capsula": UnitCapsule, "capsule": UnitCapsule,
+	"f": UnitVial, "fl": UnitVial, "fiola": UnitVial, "fiole": UnitVial,
+	"plic": UnitSachet, "plicuri": UnitSachet,
+	"pic": UnitDrop, "picaturi": UnitDrop, "picatura": UnitDrop,
+	"puf": UnitPuff, "pufuri": UnitPuff,
+	"sup": UnitSuppository, "supozitor": UnitSuppository, "supozitoare": UnitSuppository,
+}
+
+// IsMass reports whether the unit converts directly to milligrams
+func (u DoseUnit) IsMass() bool {
+	return u == UnitMilligram || u == UnitGram || u == UnitMicrogram
+}
+
+// toMilligrams converts amount in a mass unit to mg
+func (u DoseUnit) toMilligrams(amount float64) float64 {
+	switch u {
+	case UnitGram:
+		return amount * 1000
+	case UnitMicrogram:
+		return amount / 1000
+	}
+	return amount
+}
+
+// DosePeriod is the interval the frequency refers to
+type DosePeriod string
+
+const (
+	PeriodDay  DosePeriod = "day"
+	PeriodWeek DosePeriod = "week"
+)
+
+func (p DosePeriod) days() float64 {
+	if p == PeriodWeek {
+		return 7
+	}
+	return 1
+}
+
+// Dose is the structured form of a prescription sig.
+// "1 cp x 2/zi, 7 zile" is {Amount: 1, Unit: cp, Frequency: 2, Period: day, DurationDays: 7}.
+type Dose struct {
+	Amount       float64    `json:"amount"`
+	Unit         DoseUnit   `json:"unit"`
+	Frequency    int        `json:"frequency,omitempty"`
+	Period       DosePeriod `json:"period,omitempty"`
+	Route        DoseRoute  `json:"route,omitempty"`
+	PRN          bool       `json:"prn,omitempty"`
+	DurationDays int        `json:"duration_days,omitempty"`
+}
+
+func (d Dose) Validate() error {
+	if d.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidDose)
+	}
+	if _, ok := unitAliases[string(d.Unit)]; !ok {
+		return fmt.Errorf("%w: unknown unit %q", ErrInvalidDose, d.Unit)
+	}
+	if d.Frequency < 0 || d.DurationDays < 0 {
+		return fmt.Errorf("%w: frequency and duration cannot be negative", ErrInvalidDose)
+	}
+	if d.Frequency == 0 && !d.PRN {
+		return fmt.Errorf("%w: frequency is required unless taken as needed", ErrInvalidDose)
+	}
+	if d.Period != "" && d.Period != PeriodDay && d.Period != PeriodWeek {
+		return fmt.Errorf("%w: unknown period %q", ErrInvalidDose, d.Period)
+	}
+	if d.Route != "" && !d.Route.IsValid() {
+		return fmt.Errorf("%w: unknown route %q", ErrInvalidDose, d.Route)
+	}
+	return nil
+}
+
+// DailyAmount is the amount per day in the dose unit. As-needed doses count
+// their maximum frequency.
+func (d Dose) DailyAmount() float64 {
+	if d.Frequency == 0 {
+		return 0
+	}
+	period := d.Period
+	if period == "" {
+		period = PeriodDay
+	}
+	return d.Amount * float64(d.Frequency) / period.days()
+}
+
+// String renders the dose the same way in every letter: "1 cp x 2/zi, oral, 7 zile"
+func (d Dose) String() string {
+	var b strings.Builder
+	b.WriteString(formatAmount(d.Amount))
+	b.WriteString(" ")
+	b.WriteString(string(d.Unit))
+	if d.Frequency > 0 {
+		period := "zi"
+		if d.Period == PeriodWeek {
+			period = "săptămână"
+		}
+		fmt.Fprintf(&b, " x %d/%s", d.Frequency, period)
+	}
+	if d.PRN {
+		b.WriteString(", la nevoie")
+	}
+	if d.Route != "" {
+		b.WriteString(", ")
+		b.WriteString(d.Route.Label())
+	}
+	if d.DurationDays > 0 {
+		fmt.Fprintf(&b, ", %d zile", d.DurationDays)
+	}
+	return b.String()
+}
+
+func formatAmount(v float64) string {
+	return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", ",", 1)
+}
+
+var (
+	sigNumber     = `(\d+(?:\.\d+)?|\d+/\d+)`
+	sigUnit       = `(mg|mcg|µg|ug|gr|g|ml|ui|u|cpr|cp|tb|tab|comprimate|comprimat|caps|cps|capsula|capsule|fiole|fiola|fl|f|plicuri|plic|picaturi|picatura|pic|pufuri|puf|supozitoare|supozitor|sup)\b`
+	reDecimal     = regexp.MustCompile(`(\d),(\d)`)
+	reAmount      = regexp.MustCompile(`(?:^|[^\d/.])` + sigNumber + `\s*` + sigUnit)
+	reTimesAmount = regexp.MustCompile(`(\d+)\s*x\s*` + sigNumber + `\s*` + sigUnit)
+	reSchedule    = regexp.MustCompile(`(?:^|\s)(\d+(?:\.\d+)?|1/2|1/4)(?:\s*-\s*(\d+(?:\.\d+)?|1/2|1/4)){2,3}(?:\s|$|,)`)
+	reTimesPer    = regexp.MustCompile(`x\s*(\d+)\s*(?:/|pe)\s*(zi|saptamana|sapt)`)
+	reOriPer      = regexp.MustCompile(`(?:de\s+)?(\d+)\s*(?:ori|x)\s*(?:/|pe)\s*(zi|saptamana|sapt)`)
+	rePerPeriod   = regexp.MustCompile(`/\s*(zi|saptamana|sapt)\b`)
+	reMaxPer      = regexp.MustCompile(`max(?:im)?\.?\s*(\d+)\s*(?:ori\s*)?(?:/|pe)\s*(zi|saptamana|sapt)`)
+	reEveryHours  = regexp.MustCompile(`la\s+(\d+)\s*(?:ore|h)\b`)
+	reDuration    = regexp.MustCompile(`(?:timp de|pentru|x)?\s*(\d+)\s*(zile|saptamani|saptamana|luni|luna)\b`)
+	rePRN         = regexp.MustCompile(`\b(la nevoie|prn|p\.r\.n\.|sos|la durere|la febra)\b`)
+	reRouteToken  = regexp.MustCompile(`(per os|p\.o\.|i\.v\.|i\.m\.|s\.c\.|s\.l\.|\b(?:po|iv|im|sc|sl|oral|intravenos|intramuscular|subcutanat|sublingual|inhalator|inhalat|topic|local|cutanat|rectal|intrarectal|oftalmic)\b)`)
+)
+
+// ParseSig converts common Romanian sig text into a Dose, for example
+// "1 cp x 2/zi", "500 mg la 8 ore i.v.", "1-0-1", "2 x 1 plic/zi, 5 zile"
+// or "1 cp la nevoie, max 3/zi".
+func ParseSig(text string) (*Dose, error) {
+	s := reDecimal.ReplaceAllString(FoldText(text), "$1.$2")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidSig)
+	}
+	dose := &Dose{}
+
+	// Duration first so "7 zile" is not taken for a frequency
+	if m := reDuration.FindStringSubmatchIndex(s); m != nil {
+		n, _ := strconv.Atoi(s[m[2]:m[3]])
+		switch s[m[4]:m[5]] {
+		case "saptamani", "saptamana":
+			n *= 7
+		case "luni", "luna":
+			n *= 30
+		}
+		dose.DurationDays = n
+		s = s[:m[0]] + " " + s[m[1]:]
+	}
+
+	if rePRN.MatchString(s) {
+		dose.PRN = true
+	}
+	if m := reRouteToken.FindString(s); m != "" {
+		dose.Route, _ = ParseRoute(m)
+	}
+
+	switch {
+	case reTimesAmount.MatchString(s):
+		// "2 x 500 mg/zi"
+		m := reTimesAmount.FindStringSubmatch(s)
+		dose.Frequency, _ = strconv.Atoi(m[1])
+		dose.Amount = parseSigNumber(m[2])
+		dose.Unit = unitAliases[m[3]]
+		dose.Period = sigPeriod(s)
+	case reSchedule.MatchString(s):
+		// "1-0-1" morning-noon-evening(-night)
+		m := reSchedule.FindString(s)
+		var total float64
+		for _, part := range strings.Split(strings.Trim(m, " ,"), "-") {
+			if v := parseSigNumber(strings.TrimSpace(part)); v > 0 {
+				total += v
+				dose.Frequency++
+			}
+		}
+		if dose.Frequency == 0 {
+			return nil, fmt.Errorf("%w: empty schedule %q", ErrInvalidSig, text)
+		}
+		dose.Amount = total / float64(dose.Frequency)
+		dose.Period = PeriodDay
+		if a := reAmount.FindStringSubmatch(s); a != nil {
+			dose.Unit = unitAliases[a[2]]
+		} else {
+			dose.Unit = UnitTablet
+		}
+	default:
+		a := reAmount.FindStringSubmatch(s)
+		if a == nil {
+			return nil, fmt.Errorf("%w: no amount in %q", ErrInvalidSig, text)
+		}
+		dose.Amount = parseSigNumber(a[1])
+		dose.Unit = unitAliases[a[2]]
+
+		switch {
+		case reMaxPer.MatchString(s):
+			// "la nevoie, max 3/zi": the ceiling drives the daily dose check
+			m := reMaxPer.FindStringSubmatch(s)
+			dose.Frequency, _ = strconv.Atoi(m[1])
+			dose.Period = periodFromToken(m[2])
+		case reTimesPer.MatchString(s):
+			m := reTimesPer.FindStringSubmatch(s)
+			dose.Frequency, _ = strconv.Atoi(m[1])
+			dose.Period = periodFromToken(m[2])
+		case reOriPer.MatchString(s):
+			m := reOriPer.FindStringSubmatch(s)
+			dose.Frequency, _ = strconv.Atoi(m[1])
+			dose.Period = periodFromToken(m[2])
+		case reEveryHours.MatchString(s):
+			hours, _ := strconv.Atoi(reEveryHours.FindStringSubmatch(s)[1])
+			if hours == 0 || 24%hours != 0 {
+				return nil, fmt.Errorf("%w: interval of %d hours", ErrInvalidSig, hours)
+			}
+			dose.Frequency = 24 / hours
+			dose.Period = PeriodDay
+		case rePerPeriod.MatchString(s), strings.Contains(s, "zilnic"):
+			dose.Frequency = 1
+			dose.Period = sigPeriod(s)
+		case strings.Contains(s, "dimineata") && strings.Contains(s, "seara"):
+			dose.Frequency = 2
+			dose.Period = PeriodDay
+		case strings.Contains(s, "dimineata"), strings.Contains(s, "seara"), strings.Contains(s, "la culcare"):
+			dose.Frequency = 1
+			dose.Period = PeriodDay
+		}
+	}
+
+	if dose.Route == "" {
+		dose.Route = routeForUnit(dose.Unit)
+	}
+
+	if err := dose.Validate(); err != nil {
+		return nil, err
+	}
+	return dose, nil
+}
+
+func parseSigNumber(s string) float64 {
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, _ := strconv.ParseFloat(num, 64)
+		d, _ := strconv.ParseFloat(den, 64)
+		if d == 0 {
+			return 0
+		}
+		return n / d
+	}
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func sigPeriod(s string) DosePeriod {
+	if m := rePerPeriod.FindStringSubmatch(s); m != nil {
+		return periodFromToken(m[1])
+	}
+	return PeriodDay
+}
+
+func periodFromToken(token string) DosePeriod {
+	if strings.HasPrefix(token, "sapt") {
+		return PeriodWeek
+	}
+	return PeriodDay
+}
+
+// routeForUnit infers the route when the sig names an unambiguous presentation
+func routeForUnit(u DoseUnit) DoseRoute {
+	switch u {
+	case UnitTablet, UnitCapsule, UnitSachet:
+		return RouteOral
+	case UnitSuppository:
+		return RouteRectal
+	case UnitPuff:
+		return RouteInhalation
+	}
+	return ""
+}
+
+// Strength is a product's content per unit of presentation, parsed from the
+// catalog dosage: "500mg" per tablet, "250mg/5ml" per 5 ml. For combination
+// products ("1g/200mg") only the first substance is kept.
+type Strength struct {
+	Amount    float64
+	Unit      DoseUnit
+	PerVolume float64 // ml, zero when the strength is per unit of presentation
+}
+
+var reStrength = regexp.MustCompile(`^\s*` + sigNumber + `\s*(mg|mcg|µg|ug|g)\b(?:\s*/\s*(\d+(?:\.\d+)?)?\s*(ml|mg|g|mcg)\b)?`)
+
+// ParseStrength reads a catalog dosage string
+func ParseStrength(s string) (Strength, bool) {
+	m := reStrength.FindStringSubmatch(reDecimal.ReplaceAllString(FoldText(s), "$1.$2"))
+	if m == nil {
+		return Strength{}, false
+	}
+	st := Strength{Amount: parseSigNumber(m[1]), Unit: unitAliases[m[2]]}
+	if m[4] == "ml" {
+		st.PerVolume = 1
+		if m[3] != "" {
+			st.PerVolume, _ = strconv.ParseFloat(m[3], 64)
+		}
+	}
+	return st, st.Amount > 0
+}
+
+// DailyMilligrams converts the dose to mg/day using the product strength when
+// the dose is expressed in tablets, vials or millilitres. ok is false when the
+// conversion is not possible (IU, drops, unknown strength).
+func (d Dose) DailyMilligrams(strength *Strength) (float64, bool) {
+	daily := d.DailyAmount()
+	if daily == 0 {
+		return 0, false
+	}
+	if d.Unit.IsMass() {
+		return d.Unit.toMilligrams(daily), true
+	}
+	if strength == nil {
+		return 0, false
+	}
+
+	perUnit := strength.Unit.toMilligrams(strength.Amount)
+	switch d.Unit {
+	case UnitMillilitre:
+		if strength.PerVolume == 0 {
+			return 0, false
+		}
+		return daily * perUnit / strength.PerVolume, true
+	case UnitTablet, UnitCapsule, UnitVial, UnitSachet, UnitSuppository, UnitPuff:
+		if strength.PerVolume != 0 {
+			return 0, false
+		}
+		return daily * perUnit, true
+	}
+	return 0, false
+}
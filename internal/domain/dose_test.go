@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSig(t *testing.T) {
+	tests := []struct {
+		text string
+		want Dose
+	}{
+		// Frequencies
+		{"1 cp x 2/zi", Dose{Amount: 1, Unit: UnitTablet, Frequency: 2, Period: PeriodDay, Route: RouteOral}},
+		{"1 cp de 3 ori pe zi", Dose{Amount: 1, Unit: UnitTablet, Frequency: 3, Period: PeriodDay, Route: RouteOral}},
+		{"2 x 500 mg/zi", Dose{Amount: 500, Unit: UnitMilligram, Frequency: 2, Period: PeriodDay}},
+		{"1-0-1", Dose{Amount: 1, Unit: UnitTablet, Frequency: 2, Period: PeriodDay, Route: RouteOral}},
+		{"1/2-0-1/2 cp", Dose{Amount: 0.5, Unit: UnitTablet, Frequency: 2, Period: PeriodDay, Route: RouteOral}},
+		{"500 mg la 8 ore i.v.", Dose{Amount: 500, Unit: UnitMilligram, Frequency: 3, Period: PeriodDay, Route: RouteIntravenous}},
+		{"1 cp zilnic", Dose{Amount: 1, Unit: UnitTablet, Frequency: 1, Period: PeriodDay, Route: RouteOral}},
+		{"1 cp dimineața și seara", Dose{Amount: 1, Unit: UnitTablet, Frequency: 2, Period: PeriodDay, Route: RouteOral}},
+		{"1 cp la culcare", Dose{Amount: 1, Unit: UnitTablet, Frequency: 1, Period: PeriodDay, Route: RouteOral}},
+		{"70 mg x 1/săptămână", Dose{Amount: 70, Unit: UnitMilligram, Frequency: 1, Period: PeriodWeek}},
+
+		// Durations
+		{"1 plic x 2/zi, 5 zile", Dose{Amount: 1, Unit: UnitSachet, Frequency: 2, Period: PeriodDay, Route: RouteOral, DurationDays: 5}},
+		{"1 cp x 1/zi timp de 2 săptămâni", Dose{Amount: 1, Unit: UnitTablet, Frequency: 1, Period: PeriodDay, Route: RouteOral, DurationDays: 14}},
+
+		// As needed, with the ceiling as frequency
+		{"1 cp la nevoie, max 3/zi", Dose{Amount: 1, Unit: UnitTablet, Frequency: 3, Period: PeriodDay, Route: RouteOral, PRN: true}},
+		{"1 sup la febră", Dose{Amount: 1, Unit: UnitSuppository, Route: RouteRectal, PRN: true}},
+
+		// Ranges take the upper bound
+		{"1-2 cp x 3/zi", Dose{Amount: 2, Unit: UnitTablet, Frequency: 3, Period: PeriodDay, Route: RouteOral}},
+
+		// Decimal commas
+		{"0,5 mg x 2/zi", Dose{Amount: 0.5, Unit: UnitMilligram, Frequency: 2, Period: PeriodDay}},
+		{"2,5 ml x 3/zi", Dose{Amount: 2.5, Unit: UnitMillilitre, Frequency: 3, Period: PeriodDay}},
+
+		// Routes written in Romanian, with or without diacritics
+		{"1 fiolă x 2/zi intravenos", Dose{Amount: 1, Unit: UnitVial, Frequency: 2, Period: PeriodDay, Route: RouteIntravenous}},
+		{"40 mg x 1/zi s.c.", Dose{Amount: 40, Unit: UnitMilligram, Frequency: 1, Period: PeriodDay, Route: RouteSubcutaneous}},
+		{"2 pufuri x 2/zi", Dose{Amount: 2, Unit: UnitPuff, Frequency: 2, Period: PeriodDay, Route: RouteInhalation}},
+		{"1 cp x 1/zi sublingual", Dose{Amount: 1, Unit: UnitTablet, Frequency: 1, Period: PeriodDay, Route: RouteSublingual}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseSig(tt.text)
+			if err != nil {
+				t.Fatalf("ParseSig(%q): %v", tt.text, err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseSig(%q) = %+v, want %+v", tt.text, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSigRejectsUnparseableText(t *testing.T) {
+	tests := []struct {
+		text string
+		want error
+	}{
+		{"", ErrInvalidSig},
+		{"   ", ErrInvalidSig},
+		{"conform schemei", ErrInvalidSig},
+		{"după masă", ErrInvalidSig},
+		{"500 mg la 7 ore", ErrInvalidSig},
+		{"0-0-0", ErrInvalidSig},
+		// An amount without a frequency is not a complete dose
+		{"1 cp", ErrInvalidDose},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseSig(tt.text)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ParseSig(%q) = %+v, %v; want %v", tt.text, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		text string
+		want DoseRoute
+	}{
+		{"p.o.", RouteOral},
+		{"per os", RouteOral},
+		{"I.V.", RouteIntravenous},
+		{"intravenos", RouteIntravenous},
+		{"i.m.", RouteIntramuscular},
+		{"subcutanat", RouteSubcutaneous},
+		{"inhalator", RouteInhalation},
+		{"cutanat", RouteTopical},
+		{"intrarectal", RouteRectal},
+		{"oftalmic", RouteOphthalmic},
+	}
+
+	for _, tt := range tests {
+		if got, ok := ParseRoute(tt.text); !ok || got != tt.want {
+			t.Errorf("ParseRoute(%q) = %q, %v; want %q", tt.text, got, ok, tt.want)
+		}
+	}
+	if got, ok := ParseRoute("intratecal"); ok {
+		t.Errorf("ParseRoute(intratecal) = %q, want no route", got)
+	}
+}
+
+func TestDoseString(t *testing.T) {
+	tests := []struct {
+		dose Dose
+		want string
+	}{
+		{Dose{Amount: 1, Unit: UnitTablet, Frequency: 2, Period: PeriodDay, Route: RouteOral, DurationDays: 7}, "1 cp x 2/zi, oral, 7 zile"},
+		{Dose{Amount: 1, Unit: UnitVial, Frequency: 2, Period: PeriodDay, Route: RouteIntravenous}, "1 fiola x 2/zi, i.v."},
+		{Dose{Amount: 40, Unit: UnitMilligram, Frequency: 1, Route: RouteSubcutaneous}, "40 mg x 1/zi, s.c."},
+		{Dose{Amount: 2, Unit: UnitMillilitre, Frequency: 1, Route: RouteIntramuscular}, "2 ml x 1/zi, i.m."},
+		{Dose{Amount: 2, Unit: UnitPuff, Frequency: 2, Route: RouteInhalation}, "2 puf x 2/zi, inhalator"},
+		{Dose{Amount: 1, Unit: UnitSuppository, PRN: true, Route: RouteRectal}, "1 sup, la nevoie, intrarectal"},
+		{Dose{Amount: 1, Unit: UnitDrop, Frequency: 3, Route: RouteOphthalmic}, "1 pic x 3/zi, oftalmic"},
+		{Dose{Amount: 0.5, Unit: UnitTablet, Frequency: 1, Period: PeriodWeek, Route: RouteSublingual}, "0,5 cp x 1/săptămână, sublingual"},
+		{Dose{Amount: 1, Unit: UnitGram, Frequency: 1, Route: RouteTopical}, "1 g x 1/zi, topic"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dose.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.dose, got, tt.want)
+		}
+	}
+}
+
+func TestDoseStringParsesBack(t *testing.T) {
+	for _, route := range []DoseRoute{RouteOral, RouteSublingual, RouteIntravenous, RouteIntramuscular,
+		RouteSubcutaneous, RouteInhalation, RouteTopical, RouteRectal, RouteOphthalmic} {
+		dose := Dose{Amount: 1, Unit: UnitMilligram, Frequency: 2, Period: PeriodDay, Route: route}
+		got, err := ParseSig(dose.String())
+		if err != nil {
+			t.Errorf("ParseSig(%q): %v", dose.String(), err)
+			continue
+		}
+		if *got != dose {
+			t.Errorf("ParseSig(%q) = %+v, want %+v", dose.String(), *got, dose)
+		}
+	}
+}
+
+func TestParseStrength(t *testing.T) {
+	tests := []struct {
+		text string
+		want Strength
+		ok   bool
+	}{
+		{"500mg", Strength{Amount: 500, Unit: UnitMilligram}, true},
+		{"500 mg", Strength{Amount: 500, Unit: UnitMilligram}, true},
+		{"1g", Strength{Amount: 1, Unit: UnitGram}, true},
+		{"0,5 mg", Strength{Amount: 0.5, Unit: UnitMilligram}, true},
+		{"100mcg", Strength{Amount: 100, Unit: UnitMicrogram}, true},
+		{"250mg/5ml", Strength{Amount: 250, Unit: UnitMilligram, PerVolume: 5}, true},
+		{"40 mg/ml", Strength{Amount: 40, Unit: UnitMilligram, PerVolume: 1}, true},
+		{"2,5mg/2,5ml", Strength{Amount: 2.5, Unit: UnitMilligram, PerVolume: 2.5}, true},
+		// Combination products keep the first substance
+		{"875mg/125mg", Strength{Amount: 875, Unit: UnitMilligram}, true},
+		{"1g/200mg", Strength{Amount: 1, Unit: UnitGram}, true},
+		{"", Strength{}, false},
+		{"1000 UI", Strength{}, false},
+		{"soluție", Strength{}, false},
+		{"0 mg", Strength{Amount: 0, Unit: UnitMilligram}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseStrength(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseStrength(%q) = %+v, %v; want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDailyMilligrams(t *testing.T) {
+	tablet500 := &Strength{Amount: 500, Unit: UnitMilligram}
+	syrup := &Strength{Amount: 250, Unit: UnitMilligram, PerVolume: 5}
+
+	tests := []struct {
+		name     string
+		dose     Dose
+		strength *Strength
+		want     float64
+		ok       bool
+	}{
+		{"milligrams", Dose{Amount: 500, Unit: UnitMilligram, Frequency: 3}, nil, 1500, true},
+		{"grams", Dose{Amount: 1, Unit: UnitGram, Frequency: 4}, nil, 4000, true},
+		{"micrograms", Dose{Amount: 100, Unit: UnitMicrogram, Frequency: 2}, nil, 0.2, true},
+		{"weekly", Dose{Amount: 70, Unit: UnitMilligram, Frequency: 1, Period: PeriodWeek}, nil, 10, true},
+		{"tablets", Dose{Amount: 2, Unit: UnitTablet, Frequency: 3}, tablet500, 3000, true},
+		{"gram strength", Dose{Amount: 1, Unit: UnitTablet, Frequency: 2}, &Strength{Amount: 1, Unit: UnitGram}, 2000, true},
+		{"syrup", Dose{Amount: 10, Unit: UnitMillilitre, Frequency: 2}, syrup, 1000, true},
+		{"as needed with a ceiling", Dose{Amount: 1, Unit: UnitTablet, Frequency: 3, PRN: true}, tablet500, 1500, true},
+		{"as needed without a ceiling", Dose{Amount: 1, Unit: UnitTablet, PRN: true}, tablet500, 0, false},
+		{"tablets without strength", Dose{Amount: 1, Unit: UnitTablet, Frequency: 2}, nil, 0, false},
+		{"millilitres of a tablet", Dose{Amount: 5, Unit: UnitMillilitre, Frequency: 2}, tablet500, 0, false},
+		{"tablets of a solution", Dose{Amount: 1, Unit: UnitTablet, Frequency: 2}, syrup, 0, false},
+		{"international units", Dose{Amount: 10, Unit: UnitIU, Frequency: 3}, tablet500, 0, false},
+		{"drops", Dose{Amount: 20, Unit: UnitDrop, Frequency: 3}, tablet500, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.dose.DailyMilligrams(tt.strength)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("DailyMilligrams = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DRGCase is the grouper input taken from a discharge summary
+type DRGCase struct {
+	PrincipalDiagnosis string
+	SecondaryDiagnoses []string
+	Procedures         []string
+	AgeYears           int
+	Sex                Sex
+	// LengthOfStayDays counts nights; a same-day stay is 0
+	LengthOfStayDays int
+}
+
+// NewDRGCase builds the grouper input from discharge summary content. The age
+// at admission comes from the birth date, or from the CNP when it is missing.
+func NewDRGCase(content ReportContent) (DRGCase, error) {
+	patient := content.PatientData
+
+	birthDate := patient.BirthDate
+	if birthDate.IsZero() {
+		birthDate, _ = BirthDateFromCNP(patient.CNP)
+	}
+	if birthDate.IsZero() {
+		return DRGCase{}, fmt.Errorf("%w: patient age is unknown", ErrDRGUngroupable)
+	}
+	if patient.AdmissionDate.IsZero() || patient.DischargeDate.Before(patient.AdmissionDate) {
+		return DRGCase{}, fmt.Errorf("%w: admission and discharge dates are required", ErrDRGUngroupable)
+	}
+	if content.Diagnosis.PrimaryDiagnosis.Code == "" {
+		return DRGCase{}, fmt.Errorf("%w: principal diagnosis is required", ErrDRGUngroupable)
+	}
+
+	c := DRGCase{
+		PrincipalDiagnosis: content.Diagnosis.PrimaryDiagnosis.Code,
+		AgeYears:           AgeAt(birthDate, patient.AdmissionDate),
+		Sex:                SexFromCNP(patient.CNP),
+		LengthOfStayDays:   stayDays(patient.AdmissionDate, patient.DischargeDate),
+	}
+	for _, d := range content.Diagnosis.SecondaryDiagnoses {
+		c.SecondaryDiagnoses = append(c.SecondaryDiagnoses, d.Code)
+	}
+	for _, p := range content.Treatment.Procedures {
+		if p.Code != "" {
+			c.Procedures = append(c.Procedures, p.Code)
+		}
+	}
+	return c, nil
+}
+
+func stayDays(admitted, discharged time.Time) int {
+	from := time.Date(admitted.Year(), admitted.Month(), admitted.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(discharged.Year(), discharged.Month(), discharged.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// DRGAssignment is the diagnosis-related group of a signed discharge summary.
+// A stay that cannot be grouped has no code and the reason instead.
+type DRGAssignment struct {
+	Code              string    `json:"code"`
+	Description       string    `json:"description"`
+	RelativeWeight    float64   `json:"relative_weight"`
+	GrouperVersion    string    `json:"grouper_version"`
+	LengthOfStayDays  int       `json:"length_of_stay_days"`
+	GroupedAt         time.Time `json:"grouped_at"`
+	UngroupableReason string    `json:"ungroupable_reason,omitempty"`
+}
+
+// UngroupableDRG records that the grouper could not assign a DRG, so that
+// the stay can be coded by hand for billing
+func UngroupableDRG(version string, reason error) *DRGAssignment {
+	return &DRGAssignment{
+		GrouperVersion:    version,
+		GroupedAt:         time.Now(),
+		UngroupableReason: reason.Error(),
+	}
+}
+
+// IsUngroupable reports whether no DRG could be assigned
+func (a *DRGAssignment) IsUngroupable() bool {
+	return a.UngroupableReason != ""
+}
+
+// DRGGrouper assigns a diagnosis-related group to a hospital stay
+type DRGGrouper interface {
+	Group(c DRGCase) (*DRGAssignment, error)
+	Version() string
+}
+
+// DRG is an entry of the grouper's DRG list with its relative weight
+type DRG struct {
+	Code           string  `json:"code"`
+	Description    string  `json:"description"`
+	RelativeWeight float64 `json:"relative_weight"`
+}
+
+// DRGRule maps stays to a DRG. Diagnosis and procedure lists match by code
+// prefix and any entry matches; an empty list or a nil bound matches every
+// stay. A rule with secondary diagnoses requires at least one of them.
+type DRGRule struct {
+	DRGCode            string
+	PrincipalDiagnoses []string
+	SecondaryDiagnoses []string
+	Procedures         []string
+	Sex                Sex
+	MinAge             *int
+	MaxAge             *int
+	MinLengthOfStay    *int
+	MaxLengthOfStay    *int
+}
+
+// Matches reports whether the stay satisfies every criterion of the rule
+func (r DRGRule) Matches(c DRGCase) bool {
+	if len(r.PrincipalDiagnoses) > 0 && !matchesAnyPrefix(r.PrincipalDiagnoses, c.PrincipalDiagnosis) {
+		return false
+	}
+	if len(r.SecondaryDiagnoses) > 0 && !matchesAnyCode(r.SecondaryDiagnoses, c.SecondaryDiagnoses) {
+		return false
+	}
+	if len(r.Procedures) > 0 && !matchesAnyCode(r.Procedures, c.Procedures) {
+		return false
+	}
+	if r.Sex != SexUnknown && r.Sex != c.Sex {
+		return false
+	}
+	return inBounds(c.AgeYears, r.MinAge, r.MaxAge) && inBounds(c.LengthOfStayDays, r.MinLengthOfStay, r.MaxLengthOfStay)
+}
+
+func matchesAnyCode(prefixes, codes []string) bool {
+	for _, code := range codes {
+		if matchesAnyPrefix(prefixes, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyPrefix(prefixes []string, code string) bool {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(code, strings.ToUpper(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func inBounds(v int, min, max *int) bool {
+	return (min == nil || v >= *min) && (max == nil || v <= *max)
+}
+
+// TableGrouper is a DRGGrouper driven by a DRG list and an ordered rule
+// table; the first matching rule assigns the DRG
+type TableGrouper struct {
+	version string
+	drgs    map[string]DRG
+	rules   []DRGRule
+}
+
+// NewTableGrouper checks that every rule refers to a listed DRG
+func NewTableGrouper(version string, drgs []DRG, rules []DRGRule) (*TableGrouper, error) {
+	g := &TableGrouper{version: version, drgs: make(map[string]DRG), rules: rules}
+	for _, drg := range drgs {
+		g.drgs[drg.Code] = drg
+	}
+	for i, rule := range rules {
+		if _, ok := g.drgs[rule.DRGCode]; !ok {
+			return nil, fmt.Errorf("rule %d: unknown DRG %s", i+1, rule.DRGCode)
+		}
+	}
+	return g, nil
+}
+
+// Version identifies the grouper definitions
+func (g *TableGrouper) Version() string {
+	return g.version
+}
+
+func (g *TableGrouper) Group(c DRGCase) (*DRGAssignment, error) {
+	for _, rule := range g.rules {
+		if !rule.Matches(c) {
+			continue
+		}
+		drg := g.drgs[rule.DRGCode]
+		return &DRGAssignment{
+			Code:             drg.Code,
+			Description:      drg.Description,
+			RelativeWeight:   drg.RelativeWeight,
+			GrouperVersion:   g.version,
+			LengthOfStayDays: c.LengthOfStayDays,
+			GroupedAt:        time.Now(),
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: no rule matches principal diagnosis %s", ErrDRGUngroupable, c.PrincipalDiagnosis)
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestDRGRuleMatches(t *testing.T) {
+	heartFailure := DRGCase{PrincipalDiagnosis: "I50.0", AgeYears: 70, Sex: SexMale, LengthOfStayDays: 5}
+
+	tests := []struct {
+		name string
+		rule DRGRule
+		c    DRGCase
+		want bool
+	}{
+		{"empty rule matches every stay", DRGRule{}, heartFailure, true},
+		{"principal diagnosis by prefix", DRGRule{PrincipalDiagnoses: []string{"K80", "I50"}}, heartFailure, true},
+		{"prefixes ignore case", DRGRule{PrincipalDiagnoses: []string{"i50"}}, heartFailure, true},
+		{"other principal diagnosis", DRGRule{PrincipalDiagnoses: []string{"I5", "K80"}}, DRGCase{PrincipalDiagnosis: "K35.8"}, false},
+		{"secondary diagnosis required", DRGRule{SecondaryDiagnoses: []string{"A41", "N17"}}, heartFailure, false},
+		{"any secondary diagnosis", DRGRule{SecondaryDiagnoses: []string{"A41", "N17"}},
+			DRGCase{PrincipalDiagnosis: "I50.0", SecondaryDiagnoses: []string{"I10", "N17.9"}}, true},
+		{"procedure required", DRGRule{Procedures: []string{"30445"}}, heartFailure, false},
+		{"procedure by prefix", DRGRule{Procedures: []string{"30445"}}, DRGCase{Procedures: []string{"30445-00"}}, true},
+		{"sex", DRGRule{Sex: SexFemale}, heartFailure, false},
+
+		// Bounds are inclusive
+		{"below min age", DRGRule{MinAge: intPtr(18)}, DRGCase{AgeYears: 17}, false},
+		{"at min age", DRGRule{MinAge: intPtr(18)}, DRGCase{AgeYears: 18}, true},
+		{"at max age", DRGRule{MaxAge: intPtr(17)}, DRGCase{AgeYears: 17}, true},
+		{"above max age", DRGRule{MaxAge: intPtr(17)}, DRGCase{AgeYears: 18}, false},
+		{"same-day stay", DRGRule{MaxLengthOfStay: intPtr(0)}, DRGCase{LengthOfStayDays: 0}, true},
+		{"overnight stay", DRGRule{MaxLengthOfStay: intPtr(0)}, DRGCase{LengthOfStayDays: 1}, false},
+		{"below min length of stay", DRGRule{MinLengthOfStay: intPtr(3)}, DRGCase{LengthOfStayDays: 2}, false},
+		{"at min length of stay", DRGRule{MinLengthOfStay: intPtr(3), MaxLengthOfStay: intPtr(3)}, DRGCase{LengthOfStayDays: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.c); got != tt.want {
+				t.Errorf("Matches(%+v) = %v, want %v", tt.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func testGrouper(t *testing.T, catchAll bool) *TableGrouper {
+	t.Helper()
+	drgs := []DRG{
+		{Code: "H08A", Description: "Colecistectomie laparoscopică cu CC", RelativeWeight: 1.98},
+		{Code: "H08B", Description: "Colecistectomie laparoscopică fără CC", RelativeWeight: 1.12},
+		{Code: "L61Z", Description: "Hemodializă", RelativeWeight: 0.12},
+		{Code: "P67Z", Description: "Nou-născut", RelativeWeight: 0.5},
+		{Code: "960Z", Description: "Negrupabil", RelativeWeight: 0},
+	}
+	rules := []DRGRule{
+		{DRGCode: "H08A", PrincipalDiagnoses: []string{"K80"}, SecondaryDiagnoses: []string{"A41", "N17"}, Procedures: []string{"30445"}},
+		{DRGCode: "H08B", PrincipalDiagnoses: []string{"K80"}, Procedures: []string{"30445"}},
+		{DRGCode: "L61Z", PrincipalDiagnoses: []string{"N18"}, MaxLengthOfStay: intPtr(0)},
+		{DRGCode: "P67Z", MaxAge: intPtr(0)},
+	}
+	if catchAll {
+		rules = append(rules, DRGRule{DRGCode: "960Z"})
+	}
+
+	g, err := NewTableGrouper("test", drgs, rules)
+	if err != nil {
+		t.Fatalf("NewTableGrouper: %v", err)
+	}
+	return g
+}
+
+func TestTableGrouperGroup(t *testing.T) {
+	cholecystectomy := DRGCase{PrincipalDiagnosis: "K80.2", Procedures: []string{"30445-00"}, AgeYears: 52, LengthOfStayDays: 3}
+	withSepsis := cholecystectomy
+	withSepsis.SecondaryDiagnoses = []string{"A41.9"}
+
+	tests := []struct {
+		name string
+		c    DRGCase
+		want string
+	}{
+		{"first matching rule wins", withSepsis, "H08A"},
+		{"next rule without the complication", cholecystectomy, "H08B"},
+		{"same-day dialysis", DRGCase{PrincipalDiagnosis: "N18.5", AgeYears: 60, LengthOfStayDays: 0}, "L61Z"},
+		{"dialysis stay falls through", DRGCase{PrincipalDiagnosis: "N18.5", AgeYears: 60, LengthOfStayDays: 4}, "960Z"},
+		{"newborn", DRGCase{PrincipalDiagnosis: "P07.3", AgeYears: 0, LengthOfStayDays: 10}, "P67Z"},
+		{"catch-all", DRGCase{PrincipalDiagnosis: "I50.0", AgeYears: 70, LengthOfStayDays: 5}, "960Z"},
+	}
+
+	g := testGrouper(t, true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.Group(tt.c)
+			if err != nil {
+				t.Fatalf("Group: %v", err)
+			}
+			if got.Code != tt.want {
+				t.Errorf("Group(%+v) = %s, want %s", tt.c, got.Code, tt.want)
+			}
+			if got.GrouperVersion != "test" || got.LengthOfStayDays != tt.c.LengthOfStayDays || got.IsUngroupable() {
+				t.Errorf("Group(%+v) = %+v", tt.c, got)
+			}
+		})
+	}
+}
+
+func TestTableGrouperWithoutCatchAll(t *testing.T) {
+	g := testGrouper(t, false)
+	_, err := g.Group(DRGCase{PrincipalDiagnosis: "I50.0", AgeYears: 70, LengthOfStayDays: 5})
+	if !errors.Is(err, ErrDRGUngroupable) {
+		t.Errorf("Group of a stay no rule matches: %v, want %v", err, ErrDRGUngroupable)
+	}
+}
+
+func TestNewTableGrouperRejectsUnknownDRG(t *testing.T) {
+	_, err := NewTableGrouper("test", []DRG{{Code: "960Z"}}, []DRGRule{{DRGCode: "960Z"}, {DRGCode: "F62B"}})
+	if err == nil {
+		t.Error("NewTableGrouper accepted a rule for an unlisted DRG")
+	}
+}
+
+func TestNewDRGCase(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2026, time.March, d, hour, 0, 0, 0, time.UTC)
+	}
+	content := func(birthDate time.Time, cnp string, admitted, discharged time.Time) ReportContent {
+		var c ReportContent
+		c.PatientData = PatientDataSection{CNP: cnp, BirthDate: birthDate, AdmissionDate: admitted, DischargeDate: discharged}
+		c.Diagnosis.PrimaryDiagnosis = ICD10Code{Code: "K80.2"}
+		c.Diagnosis.SecondaryDiagnoses = []ICD10Code{{Code: "I10"}}
+		c.Treatment.Procedures = []Procedure{{Code: "30445-00", Name: "Colecistectomie"}, {Name: "Drenaj"}}
+		return c
+	}
+
+	t.Run("stay", func(t *testing.T) {
+		got, err := NewDRGCase(content(time.Time{}, "2740307123456", day(2, 22), day(5, 9)))
+		if err != nil {
+			t.Fatalf("NewDRGCase: %v", err)
+		}
+		// Age at admission from the CNP, one birthday before it
+		if got.AgeYears != 51 || got.Sex != SexFemale {
+			t.Errorf("age %d, sex %q; want 51, F", got.AgeYears, got.Sex)
+		}
+		if got.LengthOfStayDays != 3 {
+			t.Errorf("LengthOfStayDays = %d, want 3 nights", got.LengthOfStayDays)
+		}
+		if got.PrincipalDiagnosis != "K80.2" || len(got.SecondaryDiagnoses) != 1 || len(got.Procedures) != 1 {
+			t.Errorf("codes = %+v; uncoded procedures must be left out", got)
+		}
+	})
+
+	t.Run("birth date before CNP", func(t *testing.T) {
+		got, err := NewDRGCase(content(time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC), "2740307123456", day(2, 8), day(2, 18)))
+		if err != nil {
+			t.Fatalf("NewDRGCase: %v", err)
+		}
+		if got.AgeYears != 6 || got.LengthOfStayDays != 0 {
+			t.Errorf("age %d, stay %d; want 6 and a same-day stay", got.AgeYears, got.LengthOfStayDays)
+		}
+	})
+
+	ungroupable := []struct {
+		name    string
+		content ReportContent
+	}{
+		{"unknown age", content(time.Time{}, "", day(2, 8), day(5, 8))},
+		{"no admission date", content(time.Time{}, "2740307123456", time.Time{}, day(5, 8))},
+		{"discharge before admission", content(time.Time{}, "2740307123456", day(5, 8), day(2, 8))},
+		{"no principal diagnosis", func() ReportContent {
+			c := content(time.Time{}, "2740307123456", day(2, 8), day(5, 8))
+			c.Diagnosis.PrimaryDiagnosis = ICD10Code{}
+			return c
+		}()},
+	}
+	for _, tt := range ungroupable {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDRGCase(tt.content); !errors.Is(err, ErrDRGUngroupable) {
+				t.Errorf("NewDRGCase: %v, want %v", err, ErrDRGUngroupable)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EncounterStatus tracks an admission episode
+type EncounterStatus string
+
+const (
+	EncounterActive     EncounterStatus = "active"
+	EncounterDischarged EncounterStatus = "discharged"
+)
+
+// BedLocation is where the patient lies during a stay. DepartmentID is set
+// when the hospital has defined its departments.
+type BedLocation struct {
+	DepartmentID *uuid.UUID `json:"department_id,omitempty"`
+	Department   string     `json:"department"`
+	Ward         string     `json:"ward,omitempty"`
+	Bed          string     `json:"bed,omitempty"`
+}
+
+func (l BedLocation) Validate() error {
+	if strings.TrimSpace(l.Department) == "" {
+		return fmt.Errorf("%w: department", ErrEmptyField)
+	}
+	return nil
+}
+
+// Same reports whether both locations are the same department, ward and bed
+func (l BedLocation) Same(other BedLocation) bool {
+	return FoldText(l.Department) == FoldText(other.Department) && l.Ward == other.Ward && l.Bed == other.Bed
+}
+
+func (l BedLocation) String() string {
+	parts := []string{l.Department}
+	if l.Ward != "" {
+		parts = append(parts, "salon "+l.Ward)
+	}
+	if l.Bed != "" {
+		parts = append(parts, "pat "+l.Bed)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// EncounterStay is the period spent in one department. The last stay of an
+// active encounter is open.
+type EncounterStay struct {
+	ID uuid.UUID `json:"id"`
+	BedLocation
+	From time.Time  `json:"from"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// Encounter is an admission episode of a patient, from admission to
+// discharge, possibly spanning several departments. Every report belongs to
+// one encounter; an encounter has at most one discharge summary.
+type Encounter struct {
+	ID              uuid.UUID       `json:"id"`
+	HospitalID      uuid.UUID       `json:"hospital_id"`
+	PatientID       uuid.UUID       `json:"patient_id"`
+	AdmissionNumber string          `json:"admission_number,omitempty"`
+	Status          EncounterStatus `json:"status"`
+	AdmittedAt      time.Time       `json:"admitted_at"`
+	DischargedAt    *time.Time      `json:"discharged_at,omitempty"`
+	Stays           []EncounterStay `json:"stays"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// NewEncounter admits a patient to a department
+func NewEncounter(patient *Patient, admissionNumber string, admittedAt time.Time, location BedLocation) (*Encounter, error) {
+	if err := location.Validate(); err != nil {
+		return nil, err
+	}
+	if admittedAt.IsZero() {
+		admittedAt = time.Now()
+	}
+
+	now := time.Now()
+	return &Encounter{
+		ID:              uuid.New(),
+		HospitalID:      patient.HospitalID,
+		PatientID:       patient.ID,
+		AdmissionNumber: strings.TrimSpace(admissionNumber),
+		Status:          EncounterActive,
+		AdmittedAt:      admittedAt,
+		Stays: []EncounterStay{{
+			ID:          uuid.New(),
+			BedLocation: location,
+			From:        admittedAt,
+		}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// CurrentStay is the latest stay: the open one while admitted, the last one
+// after discharge
+func (e *Encounter) CurrentStay() *EncounterStay {
+	if len(e.Stays) == 0 {
+		return nil
+	}
+	return &e.Stays[len(e.Stays)-1]
+}
+
+// Location is the department, ward and bed of the current stay
+func (e *Encounter) Location() BedLocation {
+	if stay := e.CurrentStay(); stay != nil {
+		return stay.BedLocation
+	}
+	return BedLocation{}
+}
+
+// Transfer closes the current stay and opens one at the new location. A bed
+// change within the same department is also a transfer.
+func (e *Encounter) Transfer(location BedLocation, at time.Time) error {
+	if e.Status != EncounterActive {
+		return fmt.Errorf("%w: encounter is %s", ErrEncounterClosed, e.Status)
+	}
+	if err := location.Validate(); err != nil {
+		return err
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	current := e.CurrentStay()
+	if current != nil {
+		if at.Before(current.From) {
+			return fmt.Errorf("%w: transfer precedes the current stay", ErrInvalidDate)
+		}
+		if current.BedLocation.Same(location) {
+			return fmt.Errorf("%w: patient is already at %s", ErrInvalidTransfer, location)
+		}
+		current.To = &at
+	}
+
+	e.Stays = append(e.Stays, EncounterStay{
+		ID:          uuid.New(),
+		BedLocation: location,
+		From:        at,
+	})
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// Discharge ends the encounter and its current stay
+func (e *Encounter) Discharge(at time.Time) error {
+	if e.Status != EncounterActive {
+		return fmt.Errorf("%w: encounter is %s", ErrEncounterClosed, e.Status)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if at.Before(e.AdmittedAt) {
+		return fmt.Errorf("%w: discharge precedes admission", ErrInvalidDate)
+	}
+
+	if current := e.CurrentStay(); current != nil && current.To == nil {
+		current.To = &at
+	}
+	e.Status = EncounterDischarged
+	e.DischargedAt = &at
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetEncounter links the report to the encounter and fills the admission
+// fields of the patient data section from it
+func (r *Report) SetEncounter(encounter *Encounter) {
+	r.EncounterID = encounter.ID
+
+	location := encounter.Location()
+	r.Content.PatientData.Department = location.Department
+	r.Content.PatientData.Ward = location.Ward
+	r.Content.PatientData.Bed = location.Bed
+	r.Content.PatientData.AdmissionDate = encounter.AdmittedAt
+	if encounter.DischargedAt != nil {
+		r.Content.PatientData.DischargeDate = *encounter.DischargedAt
+	}
+
+	if r.ReportType.Content().HasSection("transfer") {
+		if r.Content.Transfer == nil {
+			r.Content.Transfer = &TransferSection{}
+		}
+		r.Content.Transfer.Source = TransferUnit{
+			DepartmentID: location.DepartmentID,
+			Department:   location.Department,
+		}
+	}
+}
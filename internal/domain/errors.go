@@ -10,11 +10,75 @@ var (
 	ErrInvalidStatusTransition     = errors.New("invalid status transition")
 	ErrIncompleteReport            = errors.New("report is incomplete")
 	ErrInvalidCNP                  = errors.New("invalid CNP format")
+	ErrContraindicatedInteraction  = errors.New("contraindicated drug interaction requires an override reason")
+	ErrDuplicateDischargeSummary   = errors.New("encounter already has a discharge summary")
+	ErrNoPreviousReport            = errors.New("patient has no signed report")
+	ErrInvalidCarryForward         = errors.New("invalid carry forward")
+	ErrInvalidReportType           = errors.New("invalid report type")
+	ErrInvalidSpecialty            = errors.New("invalid specialty")
 	
 	// Validation errors
 	ErrEmptyField                  = errors.New("required field is empty")
 	ErrInvalidDate                 = errors.New("invalid date")
 	ErrInvalidDiagnosis            = errors.New("invalid diagnosis code")
+	ErrInvalidAllergySeverity      = errors.New("invalid allergy severity")
+	ErrInvalidDose                 = errors.New("invalid dose")
+	ErrInvalidSig                  = errors.New("unrecognized dosage instructions")
+	ErrReconciliationIncomplete    = errors.New("medication reconciliation is incomplete")
+	ErrUnknownDiagnosisCode        = errors.New("unknown ICD-10 code")
+	ErrRetiredDiagnosisCode        = errors.New("retired ICD-10 code")
+	ErrUnknownProcedureCode        = errors.New("unknown procedure code")
+	ErrRetiredProcedureCode        = errors.New("retired procedure code")
+	ErrInvalidOperativeNote        = errors.New("invalid operative note")
+	ErrInvalidTransferSummary      = errors.New("invalid transfer summary")
+	ErrDRGUngroupable              = errors.New("discharge cannot be grouped to a DRG")
+	
+	// Patient errors
+	ErrPatientNotFound             = errors.New("patient not found")
+	ErrPatientAlreadyExists        = errors.New("patient with this CNP already exists")
+	ErrDuplicateNotFound           = errors.New("duplicate candidate not found")
+	ErrMergeNotFound               = errors.New("patient merge not found")
+	ErrInvalidMerge                = errors.New("patients cannot be merged")
+	ErrMergeAlreadyReverted        = errors.New("patient merge already reverted")
+	
+	// Encounter errors
+	ErrEncounterNotFound           = errors.New("encounter not found")
+	ErrEncounterClosed             = errors.New("encounter is closed")
+	ErrInvalidTransfer             = errors.New("invalid transfer")
+	ErrHandoffNotFound             = errors.New("handoff not found")
+	ErrHandoffAcknowledged         = errors.New("handoff already acknowledged")
+	ErrAdmissionNumberExists       = errors.New("admission number already used")
+	ErrAdmissionConflict           = errors.New("admission does not match the active encounter")
+	
+	// Template errors
+	ErrTemplateNotFound            = errors.New("report template not found")
+	ErrInvalidTemplate             = errors.New("invalid report template")
+	ErrTemplateIncomplete          = errors.New("report does not satisfy its template")
+	
+	// Hospital errors
+	ErrHospitalNotFound            = errors.New("hospital not found")
+	ErrHospitalInUse               = errors.New("hospital has patients, reports or users")
+	ErrHospitalCUIExists           = errors.New("hospital with this CUI already exists")
+	ErrInvalidCUI                  = errors.New("invalid CUI")
+	ErrInvalidSIRUTA               = errors.New("invalid SIRUTA code")
+	ErrInvalidAddress              = errors.New("invalid address")
+	ErrInvalidLogo                 = errors.New("invalid logo")
+	ErrLogoNotFound                = errors.New("hospital has no logo")
+	ErrInvalidPrintLayout          = errors.New("invalid print layout")
+	ErrPrintLayoutNotFound         = errors.New("print layout not found")
+	ErrVerificationNotFound        = errors.New("no signed report for this verification code")
+	ErrInvalidFHIRBundle           = errors.New("invalid FHIR bundle")
+	ErrDepartmentNotFound          = errors.New("department not found")
+	ErrDepartmentCodeExists        = errors.New("department code already used")
+	ErrDepartmentInUse             = errors.New("department is in use")
+	ErrInvalidDepartment           = errors.New("invalid department")
+	
+	// Authentication errors
+	ErrInvalidToken                = errors.New("invalid or expired token")
+	
+	// Reference data errors
+	ErrReferenceNotFound           = errors.New("reference entry not found")
+	ErrUnknownLabTest              = errors.New("unknown lab test code")
 	
 	// Repository errors
 	ErrDatabaseConnection          = errors.New("database connection error")
@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Logo limits for hospital letterheads
+const (
+	MaxLogoSize = 512 * 1024
+)
+
+// LogoContentTypes are the image formats accepted as letterhead logos
+var LogoContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+}
+
+// Address is a postal address in Romania
+type Address struct {
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	County     string `json:"county,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+}
+
+func (a Address) String() string {
+	parts := []string{}
+	for _, part := range []string{a.Street, a.City} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if a.County != "" {
+		parts = append(parts, "jud. "+a.County)
+	}
+	if a.PostalCode != "" {
+		parts = append(parts, a.PostalCode)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Hospital is a healthcare provider using the application. Reports, patients
+// and users belong to one hospital; its identification and logo are printed
+// on report letterheads.
+type Hospital struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CUI       string    `json:"cui,omitempty"`    // fiscal code (cod unic de înregistrare)
+	SIRUTA    string    `json:"siruta,omitempty"` // SIRUTA code of the locality
+	Address   Address   `json:"address"`
+	Phone     string    `json:"phone,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	HasLogo   bool      `json:"has_logo"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HospitalInput carries the editable fields of a hospital; nil fields are
+// left unchanged on update
+type HospitalInput struct {
+	Name    *string  `json:"name,omitempty"`
+	CUI     *string  `json:"cui,omitempty"`
+	SIRUTA  *string  `json:"siruta,omitempty"`
+	Address *Address `json:"address,omitempty"`
+	Phone   *string  `json:"phone,omitempty"`
+	Email   *string  `json:"email,omitempty"`
+}
+
+// NewHospital creates a hospital from the given fields
+func NewHospital(input HospitalInput) *Hospital {
+	now := time.Now()
+	hospital := &Hospital{
+		ID:        uuid.New(),
+		CreatedAt: now,
+	}
+	hospital.Apply(input)
+	hospital.UpdatedAt = now
+	return hospital
+}
+
+// Apply changes the hospital's master data
+func (h *Hospital) Apply(input HospitalInput) {
+	if input.Name != nil {
+		h.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.CUI != nil {
+		h.CUI = normalizeCUI(*input.CUI)
+	}
+	if input.SIRUTA != nil {
+		h.SIRUTA = strings.TrimSpace(*input.SIRUTA)
+	}
+	if input.Address != nil {
+		h.Address = Address{
+			Street:     strings.TrimSpace(input.Address.Street),
+			City:       strings.TrimSpace(input.Address.City),
+			County:     strings.TrimSpace(input.Address.County),
+			PostalCode: strings.TrimSpace(input.Address.PostalCode),
+		}
+	}
+	if input.Phone != nil {
+		h.Phone = strings.TrimSpace(*input.Phone)
+	}
+	if input.Email != nil {
+		h.Email = strings.TrimSpace(*input.Email)
+	}
+	h.UpdatedAt = time.Now()
+}
+
+// Validate checks the hospital's name and identification codes
+func (h *Hospital) Validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("%w: name", ErrEmptyField)
+	}
+	if h.CUI != "" && !ValidCUI(h.CUI) {
+		return fmt.Errorf("%w: %s", ErrInvalidCUI, h.CUI)
+	}
+	if h.SIRUTA != "" && !validSIRUTA(h.SIRUTA) {
+		return fmt.Errorf("%w: %s", ErrInvalidSIRUTA, h.SIRUTA)
+	}
+	if h.Address.PostalCode != "" && (len(h.Address.PostalCode) != 6 || !isDigits(h.Address.PostalCode)) {
+		return fmt.Errorf("%w: postal code must have 6 digits", ErrInvalidAddress)
+	}
+	return nil
+}
+
+// ValidCUI checks the control digit of a Romanian fiscal code (CUI/CIF). The
+// code has 2 to 10 digits, the last one being the control digit.
+func ValidCUI(cui string) bool {
+	cui = normalizeCUI(cui)
+	if len(cui) < 2 || len(cui) > 10 || !isDigits(cui) {
+		return false
+	}
+
+	const key = "753217532"
+	body := strings.Repeat("0", 10-len(cui)) + cui[:len(cui)-1]
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * int(key[i]-'0')
+	}
+	control := sum * 10 % 11
+	if control == 10 {
+		control = 0
+	}
+
+	return int(cui[len(cui)-1]-'0') == control
+}
+
+// normalizeCUI drops the RO prefix of VAT payers and any spacing
+func normalizeCUI(cui string) string {
+	cui = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(cui), " ", ""))
+	return strings.TrimPrefix(cui, "RO")
+}
+
+// validSIRUTA accepts the numeric SIRUTA codes of localities, up to 6 digits
+func validSIRUTA(code string) bool {
+	return len(code) <= 6 && isDigits(code)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Department is a ward of a hospital, such as Cardiologie or ATI. Encounter
+// stays reference a department once the hospital has defined them.
+type Department struct {
+	ID         uuid.UUID `json:"id"`
+	HospitalID uuid.UUID `json:"hospital_id"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	Specialty  Specialty `json:"specialty,omitempty"` // specialty whose reports default to this department
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// DepartmentInput carries the editable fields of a department; nil fields are
+// left unchanged on update
+type DepartmentInput struct {
+	Code      *string    `json:"code,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+	Specialty *Specialty `json:"specialty,omitempty"`
+}
+
+// NewDepartment creates a department of the hospital
+func NewDepartment(hospitalID uuid.UUID, input DepartmentInput) *Department {
+	now := time.Now()
+	department := &Department{
+		ID:         uuid.New(),
+		HospitalID: hospitalID,
+		CreatedAt:  now,
+	}
+	department.Apply(input)
+	department.UpdatedAt = now
+	return department
+}
+
+// Apply changes the department's master data. Codes are upper-cased.
+func (d *Department) Apply(input DepartmentInput) {
+	if input.Code != nil {
+		d.Code = strings.ToUpper(strings.TrimSpace(*input.Code))
+	}
+	if input.Name != nil {
+		d.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Specialty != nil {
+		d.Specialty = *input.Specialty
+	}
+	d.UpdatedAt = time.Now()
+}
+
+func (d *Department) Validate() error {
+	if d.Code == "" {
+		return fmt.Errorf("%w: code", ErrEmptyField)
+	}
+	if d.Name == "" {
+		return fmt.Errorf("%w: name", ErrEmptyField)
+	}
+	if d.Specialty != "" && !d.Specialty.IsValid() {
+		return fmt.Errorf("%w: unknown specialty %s", ErrInvalidDepartment, d.Specialty)
+	}
+	return nil
+}
+
+// Matches reports whether a free-text department names this department, by
+// code or by name ignoring case and diacritics
+func (d *Department) Matches(name string) bool {
+	folded := FoldText(name)
+	return folded != "" && (folded == FoldText(d.Code) || folded == FoldText(d.Name))
+}
+
+// ResolveDepartment links a bed location to one of the hospital's departments,
+// by ID or by name or code, and uses the department's name. Without a
+// department, the one of the given specialty is used. Hospitals without
+// departments keep free-text locations.
+func ResolveDepartment(departments []*Department, location BedLocation, specialty Specialty) (BedLocation, error) {
+	if len(departments) == 0 {
+		if location.Department == "" && specialty != "" {
+			location.Department = specialty.DepartmentName()
+		}
+		return location, nil
+	}
+
+	var match *Department
+	for _, d := range departments {
+		switch {
+		case location.DepartmentID != nil:
+			if d.ID == *location.DepartmentID {
+				match = d
+			}
+		case location.Department != "":
+			if d.Matches(location.Department) {
+				match = d
+			}
+		case specialty != "":
+			if d.Specialty == specialty && match == nil {
+				match = d
+			}
+		}
+	}
+
+	if match == nil {
+		name := location.Department
+		if name == "" {
+			name = specialty.DepartmentName()
+		}
+		return location, fmt.Errorf("%w: %s", ErrDepartmentNotFound, name)
+	}
+
+	id := match.ID
+	location.DepartmentID = &id
+	location.Department = match.Name
+	return location, nil
+}
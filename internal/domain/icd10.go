@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ICD10Level identifies a level of the ICD-10 hierarchy
+type ICD10Level string
+
+const (
+	ICD10LevelChapter     ICD10Level = "chapter"
+	ICD10LevelBlock       ICD10Level = "block"
+	ICD10LevelCategory    ICD10Level = "category"
+	ICD10LevelSubcategory ICD10Level = "subcategory"
+)
+
+// ICD10Node is an entry of the ICD-10 tree: a chapter (e.g. "X"),
+// a block (e.g. "J09-J18"), a category ("J18") or a subcategory ("J18.1")
+type ICD10Node struct {
+	Code        string     `json:"code"`
+	Title       string     `json:"title"`
+	Level       ICD10Level `json:"level"`
+	ParentCode  string     `json:"parent_code,omitempty"`
+	HasChildren bool       `json:"has_children"`
+}
+
+// ICD10BlockStatistic counts reports whose primary diagnosis falls in a block
+type ICD10BlockStatistic struct {
+	BlockCode   string `json:"block_code"`
+	BlockTitle  string `json:"block_title"`
+	ChapterCode string `json:"chapter_code"`
+	ReportCount int    `json:"report_count"`
+}
+
+// ICD10Chapter is a chapter of an ICD-10 release (e.g. "X", J00–J99)
+type ICD10Chapter struct {
+	Code       string `json:"code"`
+	Title      string `json:"title"`
+	RangeStart string `json:"range_start"`
+	RangeEnd   string `json:"range_end"`
+}
+
+// ICD10Block is a block of categories within a chapter (e.g. "J09-J18")
+type ICD10Block struct {
+	Code        string `json:"code"`
+	ChapterCode string `json:"chapter_code"`
+	Title       string `json:"title"`
+	RangeStart  string `json:"range_start"`
+	RangeEnd    string `json:"range_end"`
+}
+
+// ICD10Release is the full content of an official code list release
+type ICD10Release struct {
+	Version      string
+	SourceFormat string
+	Chapters     []ICD10Chapter
+	Blocks       []ICD10Block
+	Codes        []ICD10Reference
+}
+
+// ICD10ImportSummary reports what changed when a release was imported
+type ICD10ImportSummary struct {
+	Version    string    `json:"version"`
+	ImportedAt time.Time `json:"imported_at"`
+	Added      int       `json:"added"`
+	Changed    int       `json:"changed"`
+	Retired    int       `json:"retired"`
+}
+
+// ICD10ParentCode derives the parent of a code from its notation:
+// "E11.9" → "E11", "S72.00" → "S72.0". Categories have no parent code.
+func ICD10ParentCode(code string) string {
+	dot := strings.Index(code, ".")
+	if dot < 0 {
+		return ""
+	}
+	parent := strings.TrimSuffix(code[:len(code)-1], ".")
+	if len(parent) < dot {
+		return ""
+	}
+	return parent
+}
+
+// ICD10LevelOf derives the hierarchy level of a category or subcategory code
+func ICD10LevelOf(code string) ICD10Level {
+	if strings.Contains(code, ".") {
+		return ICD10LevelSubcategory
+	}
+	return ICD10LevelCategory
+}
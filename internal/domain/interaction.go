@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// InteractionSeverity grades a drug–drug interaction
+type InteractionSeverity string
+
+const (
+	InteractionMinor           InteractionSeverity = "minor"
+	InteractionModerate        InteractionSeverity = "moderate"
+	InteractionMajor           InteractionSeverity = "major"
+	InteractionContraindicated InteractionSeverity = "contraindicated"
+)
+
+var interactionSeverityRank = map[InteractionSeverity]int{
+	InteractionMinor:           1,
+	InteractionModerate:        2,
+	InteractionMajor:           3,
+	InteractionContraindicated: 4,
+}
+
+// IsValid reports whether s is a known severity
+func (s InteractionSeverity) IsValid() bool {
+	_, ok := interactionSeverityRank[s]
+	return ok
+}
+
+// DrugInteraction is a knowledge base entry for a pair of active substances
+type DrugInteraction struct {
+	SubstanceA  string              `json:"substance_a"`
+	SubstanceB  string              `json:"substance_b"`
+	Severity    InteractionSeverity `json:"severity"`
+	Description string              `json:"description"`
+}
+
+// InteractionKnowledgeBase indexes interactions by unordered substance pair.
+// Substance names are compared diacritics- and case-insensitively.
+type InteractionKnowledgeBase struct {
+	pairs      map[[2]string]DrugInteraction
+	substances []string
+}
+
+func NewInteractionKnowledgeBase(interactions []DrugInteraction) *InteractionKnowledgeBase {
+	kb := &InteractionKnowledgeBase{pairs: make(map[[2]string]DrugInteraction)}
+	known := make(map[string]bool)
+
+	for _, in := range interactions {
+		key := interactionKey(in.SubstanceA, in.SubstanceB)
+		if existing, ok := kb.pairs[key]; ok && interactionSeverityRank[existing.Severity] >= interactionSeverityRank[in.Severity] {
+			continue
+		}
+		kb.pairs[key] = in
+		known[key[0]] = true
+		known[key[1]] = true
+	}
+
+	for s := range known {
+		kb.substances = append(kb.substances, s)
+	}
+	sort.Strings(kb.substances)
+
+	return kb
+}
+
+func interactionKey(a, b string) [2]string {
+	a, b = FoldText(a), FoldText(b)
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// Lookup returns the interaction between two substances, if any
+func (kb *InteractionKnowledgeBase) Lookup(a, b string) (DrugInteraction, bool) {
+	if kb == nil {
+		return DrugInteraction{}, false
+	}
+	in, ok := kb.pairs[interactionKey(a, b)]
+	return in, ok
+}
+
+// SubstancesIn returns the known substances mentioned in free text
+func (kb *InteractionKnowledgeBase) SubstancesIn(text string) []string {
+	if kb == nil {
+		return nil
+	}
+	// Match at word starts so inflected forms ("enalaprilului") still count
+	folded := " " + FoldText(text)
+	var found []string
+	for _, s := range kb.substances {
+		if strings.Contains(folded, " "+s) {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+// Size returns the number of substance pairs in the knowledge base
+func (kb *InteractionKnowledgeBase) Size() int {
+	if kb == nil {
+		return 0
+	}
+	return len(kb.pairs)
+}
@@ -0,0 +1,49 @@
+package domain
+
+// LabTestReference is a LOINC-coded entry of the laboratory test catalog
+type LabTestReference struct {
+	LOINCCode       string              `json:"loinc_code"`
+	NameRO          string              `json:"name_ro"`
+	DefaultUnit     string              `json:"default_unit"`
+	ReferenceRanges []LabReferenceRange `json:"reference_ranges"`
+}
+
+// LabReferenceRange is a default normal range for a population.
+// An empty Sex or a nil age bound means the range applies to everyone.
+type LabReferenceRange struct {
+	Sex         Sex      `json:"sex,omitempty"`
+	AgeMinYears *int     `json:"age_min_years,omitempty"`
+	AgeMaxYears *int     `json:"age_max_years,omitempty"`
+	Low         *float64 `json:"low,omitempty"`
+	High        *float64 `json:"high,omitempty"`
+}
+
+// AppliesTo reports whether the range covers a patient of the given sex and age
+func (r LabReferenceRange) AppliesTo(sex Sex, ageYears int) bool {
+	if r.Sex != SexUnknown && r.Sex != sex {
+		return false
+	}
+	if r.AgeMinYears != nil && ageYears < *r.AgeMinYears {
+		return false
+	}
+	if r.AgeMaxYears != nil && ageYears > *r.AgeMaxYears {
+		return false
+	}
+	return true
+}
+
+// RangeFor picks the most specific reference range for a patient.
+// Sex-specific ranges win over ranges that apply to both sexes.
+func (t LabTestReference) RangeFor(sex Sex, ageYears int) *LabReferenceRange {
+	var best *LabReferenceRange
+	for i := range t.ReferenceRanges {
+		r := &t.ReferenceRanges[i]
+		if !r.AppliesTo(sex, ageYears) {
+			continue
+		}
+		if best == nil || (best.Sex == SexUnknown && r.Sex != SexUnknown) {
+			best = r
+		}
+	}
+	return best
+}
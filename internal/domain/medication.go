@@ -0,0 +1,22 @@
+package domain
+
+// MedicationSearch filters the medication catalog. Query matches trade names
+// and substances, ATCCode matches by prefix (e.g. "C09A") and ActiveSubstance
+// matches substance names by substring.
+type MedicationSearch struct {
+	Query           string
+	ATCCode         string
+	ActiveSubstance string
+}
+
+// IsEmpty reports whether no filter was given
+func (s MedicationSearch) IsEmpty() bool {
+	return s.Query == "" && s.ATCCode == "" && s.ActiveSubstance == ""
+}
+
+// MedicationImportSummary reports the outcome of a nomenclature import
+type MedicationImportSummary struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
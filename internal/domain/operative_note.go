@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnesthesiaType is the anesthesia technique used for an operation
+type AnesthesiaType string
+
+const (
+	AnesthesiaGeneral  AnesthesiaType = "general"
+	AnesthesiaSpinal   AnesthesiaType = "spinal"
+	AnesthesiaEpidural AnesthesiaType = "epidural"
+	AnesthesiaRegional AnesthesiaType = "regional"
+	AnesthesiaSedation AnesthesiaType = "sedation"
+	AnesthesiaLocal    AnesthesiaType = "local"
+)
+
+func (t AnesthesiaType) IsValid() bool {
+	switch t {
+	case AnesthesiaGeneral, AnesthesiaSpinal, AnesthesiaEpidural, AnesthesiaRegional, AnesthesiaSedation, AnesthesiaLocal:
+		return true
+	}
+	return false
+}
+
+// anesthesiaLabels are the anesthesia types as printed on the protocol
+var anesthesiaLabels = map[AnesthesiaType]string{
+	AnesthesiaGeneral:  "generală",
+	AnesthesiaSpinal:   "rahianestezie",
+	AnesthesiaEpidural: "peridurală",
+	AnesthesiaRegional: "regională",
+	AnesthesiaSedation: "sedare",
+	AnesthesiaLocal:    "locală",
+}
+
+// Label returns the anesthesia type in Romanian ("generală", "rahianestezie")
+func (t AnesthesiaType) Label() string {
+	if label, ok := anesthesiaLabels[t]; ok {
+		return label
+	}
+	return string(t)
+}
+
+// OperativeNoteSection is the protocol of an operation (protocol operator)
+type OperativeNoteSection struct {
+	PreOpDiagnosis   ICD10Code       `json:"pre_op_diagnosis"`
+	PostOpDiagnosis  ICD10Code       `json:"post_op_diagnosis"`
+	ProcedureCodes   []ProcedureCode `json:"procedure_codes"`
+	Surgeon          string          `json:"surgeon"`
+	Assistants       []string        `json:"assistants"`
+	Anesthesiologist string          `json:"anesthesiologist"`
+	AnesthesiaType   AnesthesiaType  `json:"anesthesia_type"`
+	Incision         string          `json:"incision"`
+	Findings         string          `json:"findings"`
+	Specimens        []Specimen      `json:"specimens"`
+	// EstimatedBloodLossML is nil until recorded, so that 0 ml can be stated
+	EstimatedBloodLossML *int      `json:"estimated_blood_loss_ml,omitempty"`
+	Implants             []Implant `json:"implants"`
+	// Complications must be stated explicitly, e.g. "Fără complicații"
+	Complications string `json:"complications"`
+}
+
+// ProcedureCode is a coded procedure performed during the operation
+type ProcedureCode struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// Specimen is tissue or fluid sent for examination
+type Specimen struct {
+	Description string `json:"description"`
+	SentTo      string `json:"sent_to"`
+}
+
+// Implant is a device left in the patient; the lot number keeps it traceable
+type Implant struct {
+	Name         string `json:"name"`
+	Manufacturer string `json:"manufacturer"`
+	LotNumber    string `json:"lot_number"`
+	SerialNumber string `json:"serial_number,omitempty"`
+}
+
+func (s OperativeNoteSection) Validate() error {
+	if s.PreOpDiagnosis.Code == "" || s.PostOpDiagnosis.Code == "" {
+		return fmt.Errorf("%w: pre-operative and post-operative diagnoses are required", ErrInvalidOperativeNote)
+	}
+	if len(s.ProcedureCodes) == 0 {
+		return fmt.Errorf("%w: at least one procedure code is required", ErrInvalidOperativeNote)
+	}
+	for _, p := range s.ProcedureCodes {
+		if strings.TrimSpace(p.Code) == "" {
+			return fmt.Errorf("%w: procedure code is empty", ErrInvalidOperativeNote)
+		}
+	}
+	if strings.TrimSpace(s.Surgeon) == "" {
+		return fmt.Errorf("%w: surgeon is required", ErrInvalidOperativeNote)
+	}
+	if !s.AnesthesiaType.IsValid() {
+		return fmt.Errorf("%w: unknown anesthesia type %q", ErrInvalidOperativeNote, s.AnesthesiaType)
+	}
+	if s.AnesthesiaType != AnesthesiaLocal && strings.TrimSpace(s.Anesthesiologist) == "" {
+		return fmt.Errorf("%w: anesthesiologist is required for %s anesthesia", ErrInvalidOperativeNote, s.AnesthesiaType)
+	}
+	if strings.TrimSpace(s.Findings) == "" {
+		return fmt.Errorf("%w: operative findings are required", ErrInvalidOperativeNote)
+	}
+	if s.EstimatedBloodLossML == nil || *s.EstimatedBloodLossML < 0 {
+		return fmt.Errorf("%w: estimated blood loss is required", ErrInvalidOperativeNote)
+	}
+	for _, specimen := range s.Specimens {
+		if strings.TrimSpace(specimen.Description) == "" {
+			return fmt.Errorf("%w: specimen description is empty", ErrInvalidOperativeNote)
+		}
+	}
+	for _, implant := range s.Implants {
+		if strings.TrimSpace(implant.Name) == "" || strings.TrimSpace(implant.LotNumber) == "" {
+			return fmt.Errorf("%w: implants need a name and a lot number", ErrInvalidOperativeNote)
+		}
+	}
+	if strings.TrimSpace(s.Complications) == "" {
+		return fmt.Errorf("%w: complications must be stated, even if none", ErrInvalidOperativeNote)
+	}
+	return nil
+}
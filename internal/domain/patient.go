@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Patient is a person registered with a hospital. A CNP identifies at most
+// one patient per hospital.
+type Patient struct {
+	ID         uuid.UUID  `json:"id"`
+	HospitalID uuid.UUID  `json:"hospital_id"`
+	CNP        string     `json:"cnp"`
+	FirstName  string     `json:"first_name"`
+	LastName   string     `json:"last_name"`
+	BirthDate  *time.Time `json:"birth_date,omitempty"`
+	Sex        Sex        `json:"sex,omitempty"`
+	Phone      string     `json:"phone,omitempty"`
+	Address    string     `json:"address,omitempty"`
+	// MergedIntoID is set once the record was merged into another patient;
+	// its CNP keeps resolving to the surviving record
+	MergedIntoID *uuid.UUID `json:"merged_into_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// NewPatient registers a patient, deriving sex and birth date from the CNP
+func NewPatient(hospitalID uuid.UUID, cnp, firstName, lastName string) *Patient {
+	now := time.Now()
+	p := &Patient{
+		ID:         uuid.New(),
+		HospitalID: hospitalID,
+		CNP:        strings.TrimSpace(cnp),
+		FirstName:  strings.TrimSpace(firstName),
+		LastName:   strings.TrimSpace(lastName),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	p.deriveFromCNP()
+	return p
+}
+
+func (p *Patient) deriveFromCNP() {
+	p.Sex = SexFromCNP(p.CNP)
+	if birthDate, ok := BirthDateFromCNP(p.CNP); ok {
+		p.BirthDate = &birthDate
+	}
+}
+
+func (p *Patient) Validate() error {
+	if len(p.CNP) != 13 {
+		return ErrInvalidCNP
+	}
+	if p.FirstName == "" || p.LastName == "" {
+		return ErrEmptyField
+	}
+	return nil
+}
+
+// FullName is the name as printed on letters: "POPESCU Ion"
+func (p *Patient) FullName() string {
+	return strings.TrimSpace(strings.ToUpper(p.LastName) + " " + p.FirstName)
+}
+
+// PatientUpdate carries demographic changes; nil fields are left unchanged
+type PatientUpdate struct {
+	CNP       *string `json:"cnp,omitempty"`
+	FirstName *string `json:"first_name,omitempty"`
+	LastName  *string `json:"last_name,omitempty"`
+	Phone     *string `json:"phone,omitempty"`
+	Address   *string `json:"address,omitempty"`
+}
+
+// Apply changes the patient's demographics. A corrected CNP also refreshes
+// the derived sex and birth date.
+func (p *Patient) Apply(u PatientUpdate) {
+	if u.CNP != nil {
+		p.CNP = strings.TrimSpace(*u.CNP)
+		p.BirthDate = nil
+		p.deriveFromCNP()
+	}
+	if u.FirstName != nil {
+		p.FirstName = strings.TrimSpace(*u.FirstName)
+	}
+	if u.LastName != nil {
+		p.LastName = strings.TrimSpace(*u.LastName)
+	}
+	if u.Phone != nil {
+		p.Phone = strings.TrimSpace(*u.Phone)
+	}
+	if u.Address != nil {
+		p.Address = strings.TrimSpace(*u.Address)
+	}
+	p.UpdatedAt = time.Now()
+}
+
+// PatientSearch filters the registry by exact or partial CNP and by name.
+// Name matching ignores case and diacritics.
+type PatientSearch struct {
+	CNP  string
+	Name string
+}
+
+func (s PatientSearch) IsEmpty() bool {
+	return strings.TrimSpace(s.CNP) == "" && strings.TrimSpace(s.Name) == ""
+}
+
+// PatientSearchName is the folded "last first" form stored for name search
+func PatientSearchName(firstName, lastName string) string {
+	return FoldText(lastName + " " + firstName)
+}
@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DuplicateReason explains why two registry entries look like the same person
+type DuplicateReason string
+
+const (
+	// Same name and birth date, CNPs one typo apart
+	DuplicateNearCNP DuplicateReason = "near_cnp"
+	// Same CNP apart from the control digit, names spelled differently
+	DuplicateNameSpelling DuplicateReason = "name_spelling"
+)
+
+// DuplicateStatus tracks a candidate through the review queue
+type DuplicateStatus string
+
+const (
+	DuplicatePending   DuplicateStatus = "pending"
+	DuplicateMerged    DuplicateStatus = "merged"
+	DuplicateDismissed DuplicateStatus = "dismissed"
+)
+
+func (s DuplicateStatus) IsValid() bool {
+	switch s {
+	case DuplicatePending, DuplicateMerged, DuplicateDismissed:
+		return true
+	}
+	return false
+}
+
+// DuplicateCandidate is a probable duplicate pair awaiting review. The pair is
+// stored in a canonical order so that a rescan finds the same candidate.
+type DuplicateCandidate struct {
+	ID                  uuid.UUID       `json:"id"`
+	HospitalID          uuid.UUID       `json:"hospital_id"`
+	PatientAID          uuid.UUID       `json:"patient_a_id"`
+	PatientBID          uuid.UUID       `json:"patient_b_id"`
+	Reason              DuplicateReason `json:"reason"`
+	Detail              string          `json:"detail"`
+	SuggestedSurvivorID uuid.UUID       `json:"suggested_survivor_id"`
+	Status              DuplicateStatus `json:"status"`
+	DetectedAt          time.Time       `json:"detected_at"`
+	ReviewedBy          *uuid.UUID      `json:"reviewed_by,omitempty"`
+	ReviewedAt          *time.Time      `json:"reviewed_at,omitempty"`
+}
+
+// Involves reports whether the candidate pairs exactly these two patients
+func (d *DuplicateCandidate) Involves(a, b uuid.UUID) bool {
+	return (d.PatientAID == a && d.PatientBID == b) || (d.PatientAID == b && d.PatientBID == a)
+}
+
+// FindDuplicates compares the active patients of one hospital and returns
+// the probable duplicate pairs. Names are compared diacritics-insensitively.
+func FindDuplicates(patients []*Patient) []*DuplicateCandidate {
+	byNameAndBirth := make(map[string][]*Patient)
+	byCNPBody := make(map[string][]*Patient)
+	for _, p := range patients {
+		if p.MergedIntoID != nil || len(p.CNP) != 13 {
+			continue
+		}
+		if p.BirthDate != nil {
+			key := PatientSearchName(p.FirstName, p.LastName) + "|" + p.BirthDate.Format("2006-01-02")
+			byNameAndBirth[key] = append(byNameAndBirth[key], p)
+		}
+		byCNPBody[p.CNP[:12]] = append(byCNPBody[p.CNP[:12]], p)
+	}
+
+	seen := make(map[[2]uuid.UUID]bool)
+	var candidates []*DuplicateCandidate
+	add := func(a, b *Patient, reason DuplicateReason, detail string) {
+		if a.ID.String() > b.ID.String() {
+			a, b = b, a
+		}
+		key := [2]uuid.UUID{a.ID, b.ID}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		candidates = append(candidates, &DuplicateCandidate{
+			ID:                  uuid.New(),
+			HospitalID:          a.HospitalID,
+			PatientAID:          a.ID,
+			PatientBID:          b.ID,
+			Reason:              reason,
+			Detail:              detail,
+			SuggestedSurvivorID: suggestSurvivor(a, b).ID,
+			Status:              DuplicatePending,
+			DetectedAt:          time.Now(),
+		})
+	}
+
+	for _, group := range byCNPBody {
+		forEachPair(group, func(a, b *Patient) {
+			if a.CNP != b.CNP && similarNames(a, b) {
+				add(a, b, DuplicateNameSpelling, fmt.Sprintf("CNP %s / %s; %s / %s", a.CNP, b.CNP, a.FullName(), b.FullName()))
+			}
+		})
+	}
+	for _, group := range byNameAndBirth {
+		forEachPair(group, func(a, b *Patient) {
+			if oneTypoApart(a.CNP, b.CNP) {
+				add(a, b, DuplicateNearCNP, fmt.Sprintf("CNP %s / %s", a.CNP, b.CNP))
+			}
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Detail < candidates[j].Detail
+	})
+	return candidates
+}
+
+func forEachPair(group []*Patient, fn func(a, b *Patient)) {
+	for i := 0; i < len(group); i++ {
+		for j := i + 1; j < len(group); j++ {
+			fn(group[i], group[j])
+		}
+	}
+}
+
+// suggestSurvivor prefers the record whose CNP passes the checksum, then the
+// older registration
+func suggestSurvivor(a, b *Patient) *Patient {
+	validA, validB := ValidCNPChecksum(a.CNP), ValidCNPChecksum(b.CNP)
+	if validA != validB {
+		if validA {
+			return a
+		}
+		return b
+	}
+	if b.CreatedAt.Before(a.CreatedAt) {
+		return b
+	}
+	return a
+}
+
+// oneTypoApart reports whether two CNPs differ by a single substituted digit
+// or a single swap of adjacent digits
+func oneTypoApart(a, b string) bool {
+	if len(a) != len(b) || a == b {
+		return false
+	}
+	var diff []int
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			diff = append(diff, i)
+			if len(diff) > 2 {
+				return false
+			}
+		}
+	}
+	if len(diff) == 1 {
+		return true
+	}
+	i, j := diff[0], diff[1]
+	return j == i+1 && a[i] == b[j] && a[j] == b[i]
+}
+
+// similarNames tolerates diacritics, case and a few misspelled letters
+func similarNames(a, b *Patient) bool {
+	nameA := []rune(PatientSearchName(a.FirstName, a.LastName))
+	nameB := []rune(PatientSearchName(b.FirstName, b.LastName))
+	return editDistance(nameA, nameB) <= 3
+}
+
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// PatientMerge records that the reports of MergedID were re-pointed to
+// SurvivorID. ReportIDs lists the moved reports so the merge can be reverted.
+type PatientMerge struct {
+	ID          uuid.UUID   `json:"id"`
+	HospitalID  uuid.UUID   `json:"hospital_id"`
+	SurvivorID  uuid.UUID   `json:"survivor_id"`
+	MergedID    uuid.UUID   `json:"merged_id"`
+	CandidateID *uuid.UUID  `json:"candidate_id,omitempty"`
+	ReportIDs   []uuid.UUID `json:"report_ids"`
+	MergedBy    uuid.UUID   `json:"merged_by"`
+	MergedAt    time.Time   `json:"merged_at"`
+	RevertedBy  *uuid.UUID  `json:"reverted_by,omitempty"`
+	RevertedAt  *time.Time  `json:"reverted_at,omitempty"`
+}
+
+func NewPatientMerge(hospitalID, survivorID, mergedID uuid.UUID, candidateID *uuid.UUID, userID uuid.UUID) (*PatientMerge, error) {
+	if survivorID == mergedID {
+		return nil, fmt.Errorf("%w: a patient cannot be merged into itself", ErrInvalidMerge)
+	}
+	return &PatientMerge{
+		ID:          uuid.New(),
+		HospitalID:  hospitalID,
+		SurvivorID:  survivorID,
+		MergedID:    mergedID,
+		CandidateID: candidateID,
+		ReportIDs:   []uuid.UUID{},
+		MergedBy:    userID,
+		MergedAt:    time.Now(),
+	}, nil
+}
+
+func (m *PatientMerge) IsReverted() bool {
+	return m.RevertedAt != nil
+}
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// withControl completes the first 12 digits of a CNP with its control digit
+func withControl(body string) string {
+	const weights = "279146358279"
+	sum := 0
+	for i := 0; i < 12; i++ {
+		sum += int(body[i]-'0') * int(weights[i]-'0')
+	}
+	control := sum % 11
+	if control == 10 {
+		control = 1
+	}
+	return body + string(rune('0'+control))
+}
+
+// typo replaces the digit at i
+func typo(cnp string, i int, digit byte) string {
+	b := []byte(cnp)
+	b[i] = digit
+	return string(b)
+}
+
+// swap exchanges the digits at i and j
+func swap(cnp string, i, j int) string {
+	b := []byte(cnp)
+	b[i], b[j] = b[j], b[i]
+	return string(b)
+}
+
+var (
+	// Born on 7 March 1974; digits 7 to 11 are the county and serial number
+	validCNP = withControl("174030712345")
+	// wrongControl is validCNP with another control digit
+	wrongControl = typo(validCNP, 12, '0'+(validCNP[12]-'0'+1)%10)
+)
+
+func registered(cnp, firstName, lastName string, daysAgo int) *Patient {
+	p := NewPatient(uuid.New(), cnp, firstName, lastName)
+	p.CreatedAt = time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -daysAgo)
+	return p
+}
+
+func TestOneTypoApart(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"same CNP", validCNP, validCNP, false},
+		{"substituted digit", validCNP, typo(validCNP, 9, '8'), true},
+		{"substituted control digit", validCNP, wrongControl, true},
+		{"adjacent digits swapped", validCNP, swap(validCNP, 9, 10), true},
+		{"first digits swapped", validCNP, swap(validCNP, 0, 1), true},
+		{"distant digits swapped", validCNP, swap(validCNP, 8, 11), false},
+		{"two substituted digits", validCNP, typo(typo(validCNP, 8, '9'), 10, '9'), false},
+		{"adjacent digits changed, not swapped", validCNP, typo(typo(validCNP, 9, '9'), 10, '9'), false},
+		{"three differences", validCNP, typo(typo(typo(validCNP, 7, '9'), 9, '9'), 11, '9'), false},
+		{"different lengths", validCNP, validCNP[:12], false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := oneTypoApart(tt.a, tt.b); got != tt.want {
+				t.Errorf("oneTypoApart(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := oneTypoApart(tt.b, tt.a); got != tt.want {
+				t.Errorf("oneTypoApart(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestSurvivor(t *testing.T) {
+	invalidCNP := typo(validCNP, 9, '8')
+	if ValidCNPChecksum(invalidCNP) {
+		t.Fatalf("%s passes the checksum", invalidCNP)
+	}
+
+	tests := []struct {
+		name string
+		a, b *Patient
+		want int // 0 for a, 1 for b
+	}{
+		{"valid checksum wins over an older record", registered(invalidCNP, "Ion", "Popescu", 300), registered(validCNP, "Ion", "Popescu", 1), 1},
+		{"valid checksum wins over a newer record", registered(validCNP, "Ion", "Popescu", 1), registered(invalidCNP, "Ion", "Popescu", 300), 0},
+		{"both valid: older record", registered(validCNP, "Ion", "Popescu", 1), registered(withControl("174030712346"), "Ion", "Popescu", 30), 1},
+		{"both invalid: older record", registered(invalidCNP, "Ion", "Popescu", 30), registered(typo(validCNP, 10, '8'), "Ion", "Popescu", 1), 0},
+		{"same registration: first record", registered(validCNP, "Ion", "Popescu", 5), registered(withControl("174030712346"), "Ion", "Popescu", 5), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []*Patient{tt.a, tt.b}[tt.want]
+			if got := suggestSurvivor(tt.a, tt.b); got != want {
+				t.Errorf("suggestSurvivor = %s (%s), want %s (%s)", got.CNP, got.CreatedAt, want.CNP, want.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		patients   []*Patient
+		wantReason DuplicateReason // empty when no pair is expected
+		survivor   int
+	}{
+		{"substituted CNP digit", []*Patient{
+			registered(typo(validCNP, 9, '8'), "Ion", "Popescu", 300),
+			registered(validCNP, "Ion", "Popescu", 1),
+		}, DuplicateNearCNP, 1},
+		{"transposed CNP digits", []*Patient{
+			registered(validCNP, "Ion", "Popescu", 1),
+			registered(swap(validCNP, 9, 10), "Ion", "Popescu", 300),
+		}, DuplicateNearCNP, 0},
+		{"names differing in diacritics and case", []*Patient{
+			registered(validCNP, "Ștefan", "Țuțea", 10),
+			registered(typo(validCNP, 11, '9'), "STEFAN", "Tutea", 20),
+		}, DuplicateNearCNP, 0},
+		{"misspelled name, CNP differing in the control digit", []*Patient{
+			registered(wrongControl, "Mihai", "Popesku", 300),
+			registered(validCNP, "Mihai", "Popescu", 1),
+		}, DuplicateNameSpelling, 1},
+		{"same CNP body, different person", []*Patient{
+			registered(validCNP, "Mihai", "Popescu", 1),
+			registered(wrongControl, "Gheorghe", "Dumitrescu", 2),
+		}, "", 0},
+		{"same name, CNPs two typos apart", []*Patient{
+			registered(validCNP, "Ion", "Popescu", 1),
+			registered(typo(typo(validCNP, 8, '9'), 10, '9'), "Ion", "Popescu", 2),
+		}, "", 0},
+		{"same CNP", []*Patient{
+			registered(validCNP, "Ion", "Popescu", 1),
+			registered(validCNP, "Ion", "Popescu", 2),
+		}, "", 0},
+		{"near CNP, other name", []*Patient{
+			registered(validCNP, "Ion", "Popescu", 1),
+			registered(typo(validCNP, 9, '8'), "Ana", "Popescu", 2),
+		}, "", 0},
+		{"merged record", []*Patient{
+			registered(validCNP, "Ion", "Popescu", 1),
+			func() *Patient {
+				p := registered(typo(validCNP, 9, '8'), "Ion", "Popescu", 2)
+				p.MergedIntoID = &uuid.UUID{1}
+				return p
+			}(),
+		}, "", 0},
+		{"malformed CNP", []*Patient{
+			registered(validCNP[:12], "Ion", "Popescu", 1),
+			registered(validCNP, "Ion", "Popescu", 2),
+		}, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := FindDuplicates(tt.patients)
+			if tt.wantReason == "" {
+				if len(candidates) != 0 {
+					t.Errorf("FindDuplicates = %+v, want no pair", candidates[0])
+				}
+				return
+			}
+			if len(candidates) != 1 {
+				t.Fatalf("got %d candidates, want 1", len(candidates))
+			}
+
+			c := candidates[0]
+			a, b := tt.patients[0], tt.patients[1]
+			if c.Reason != tt.wantReason || !c.Involves(a.ID, b.ID) || c.Status != DuplicatePending {
+				t.Errorf("candidate = %+v, want a pending %s pair", c, tt.wantReason)
+			}
+			if c.PatientAID.String() > c.PatientBID.String() {
+				t.Errorf("pair %s, %s is not in canonical order", c.PatientAID, c.PatientBID)
+			}
+			if want := tt.patients[tt.survivor].ID; c.SuggestedSurvivorID != want {
+				t.Errorf("SuggestedSurvivorID = %s, want %s", c.SuggestedSurvivorID, want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesReportsEachPairOnce(t *testing.T) {
+	// Same name and birth date, CNPs differing in the control digit: both
+	// rules match
+	patients := []*Patient{
+		registered(validCNP, "Ion", "Popescu", 1),
+		registered(wrongControl, "Ion", "Popescu", 2),
+		registered(withControl("285120140017"), "Maria", "Ionescu", 3),
+	}
+
+	candidates := FindDuplicates(patients)
+	if len(candidates) != 1 {
+		t.Fatalf("got %d candidates, want 1", len(candidates))
+	}
+	if candidates[0].Reason != DuplicateNameSpelling {
+		t.Errorf("Reason = %s, want %s", candidates[0].Reason, DuplicateNameSpelling)
+	}
+}
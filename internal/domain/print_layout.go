@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxPrintLayoutSize limits the source of a print layout
+const MaxPrintLayoutSize = 256 * 1024
+
+// PrintLayout is a hospital's html/template source for printing reports of
+// one type. Without a stored layout the built-in layout is used.
+type PrintLayout struct {
+	HospitalID uuid.UUID  `json:"hospital_id"`
+	ReportType ReportType `json:"report_type"`
+	Source     string     `json:"source"`
+	// Default is set on the built-in layout returned when none is stored
+	Default   bool       `json:"default"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty"`
+}
+
+func (l *PrintLayout) Validate() error {
+	if !l.ReportType.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidReportType, l.ReportType)
+	}
+	if strings.TrimSpace(l.Source) == "" {
+		return fmt.Errorf("%w: source is empty", ErrInvalidPrintLayout)
+	}
+	if len(l.Source) > MaxPrintLayoutSize {
+		return fmt.Errorf("%w: larger than %d KB", ErrInvalidPrintLayout, MaxPrintLayoutSize/1024)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ProcedureReference is an entry of the procedure classification (ACHI codes
+// such as 30445-00, used for DRG coding)
+type ProcedureReference struct {
+	Code    string `json:"code"`
+	Block   string `json:"block,omitempty"`
+	TitleRO string `json:"title_ro"`
+	Active  bool   `json:"active"`
+}
+
+var procedureCodePattern = regexp.MustCompile(`^\d{5}-\d{2}$`)
+
+// NormalizeProcedureCode trims the code and adds the dash missing from codes
+// written as seven digits. It reports false when the code is not in the ACHI
+// format.
+func NormalizeProcedureCode(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) == 7 && !strings.Contains(code, "-") {
+		code = code[:5] + "-" + code[5:]
+	}
+	return code, procedureCodePattern.MatchString(code)
+}
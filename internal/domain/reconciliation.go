@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReconciliationStatus records what happened to a medication between admission and discharge
+type ReconciliationStatus string
+
+const (
+	ReconciliationContinued ReconciliationStatus = "continued"
+	ReconciliationChanged   ReconciliationStatus = "changed"
+	ReconciliationStopped   ReconciliationStatus = "stopped"
+	ReconciliationNew       ReconciliationStatus = "new"
+)
+
+func (s ReconciliationStatus) IsValid() bool {
+	switch s {
+	case ReconciliationContinued, ReconciliationChanged, ReconciliationStopped, ReconciliationNew:
+		return true
+	}
+	return false
+}
+
+// Label is the wording used in the discharge letter
+func (s ReconciliationStatus) Label() string {
+	switch s {
+	case ReconciliationContinued:
+		return "Continuat"
+	case ReconciliationChanged:
+		return "Modificat"
+	case ReconciliationStopped:
+		return "Oprit"
+	case ReconciliationNew:
+		return "Nou"
+	}
+	return string(s)
+}
+
+// MedicationReconciliationSection holds the medication lists compared in the
+// discharge letter. The in-hospital list is Treatment.Medications.
+type MedicationReconciliationSection struct {
+	PreAdmission []Medication             `json:"pre_admission"`
+	Discharge    []Medication             `json:"discharge"`
+	Decisions    []ReconciliationDecision `json:"decisions,omitempty"`
+}
+
+// ReconciliationDecision is the doctor's explicit status for one medication,
+// overriding the status proposed from the lists
+type ReconciliationDecision struct {
+	Medication string               `json:"medication"`
+	Status     ReconciliationStatus `json:"status"`
+	Reason     string               `json:"reason,omitempty"`
+}
+
+// ReconciliationRow is one line of the rendered reconciliation table
+type ReconciliationRow struct {
+	Medication   string               `json:"medication"`
+	PreAdmission string               `json:"pre_admission,omitempty"`
+	InHospital   string               `json:"in_hospital,omitempty"`
+	Discharge    string               `json:"discharge,omitempty"`
+	Status       ReconciliationStatus `json:"status"`
+	Reason       string               `json:"reason,omitempty"`
+	// Confirmed is false while the status is only proposed from the lists
+	Confirmed bool `json:"confirmed"`
+	// chronic rows come from the pre-admission list and need a reason when changed or stopped
+	chronic bool
+}
+
+func (s MedicationReconciliationSection) IsEmpty() bool {
+	return len(s.PreAdmission) == 0 && len(s.Discharge) == 0 && len(s.Decisions) == 0
+}
+
+// MedicationKey identifies a medication across lists by its first word,
+// so "Enalapril 10mg" and "Enalapril 20 mg" are the same medication
+func MedicationKey(name string) string {
+	fields := strings.Fields(FoldText(name))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// ReconciliationTable joins the pre-admission, in-hospital and discharge lists.
+// Rows follow the pre-admission order, then medications new at discharge,
+// then medications given only in hospital.
+func (c ReportContent) ReconciliationTable() []ReconciliationRow {
+	rec := c.MedicationReconciliation
+	inHospital := c.Treatment.Medications
+	if rec.IsEmpty() {
+		return nil
+	}
+
+	decisions := make(map[string]ReconciliationDecision, len(rec.Decisions))
+	for _, d := range rec.Decisions {
+		decisions[MedicationKey(d.Medication)] = d
+	}
+
+	var rows []ReconciliationRow
+	seen := make(map[string]bool)
+
+	for _, pre := range rec.PreAdmission {
+		key := MedicationKey(pre.Name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		row := ReconciliationRow{Medication: pre.Name, PreAdmission: describeMedication(pre), chronic: true}
+		if m := findMedication(inHospital, key); m != nil {
+			row.InHospital = describeMedication(*m)
+		}
+		if m := findMedication(rec.Discharge, key); m != nil {
+			row.Discharge = describeMedication(*m)
+			row.Status = ReconciliationContinued
+			if !sameMedicationRegimen(pre, *m) {
+				row.Status = ReconciliationChanged
+			}
+		} else {
+			row.Status = ReconciliationStopped
+		}
+		rows = append(rows, applyDecision(row, decisions[key]))
+	}
+
+	for _, dis := range rec.Discharge {
+		key := MedicationKey(dis.Name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		row := ReconciliationRow{Medication: dis.Name, Discharge: describeMedication(dis), Status: ReconciliationNew}
+		if m := findMedication(inHospital, key); m != nil {
+			row.InHospital = describeMedication(*m)
+		}
+		rows = append(rows, applyDecision(row, decisions[key]))
+	}
+
+	for _, m := range inHospital {
+		key := MedicationKey(m.Name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		row := ReconciliationRow{Medication: m.Name, InHospital: describeMedication(m), Status: ReconciliationStopped}
+		rows = append(rows, applyDecision(row, decisions[key]))
+	}
+
+	return rows
+}
+
+// ValidateReconciliation requires a reason for every chronic medication that
+// was changed or stopped, and a known status on every decision
+func (c ReportContent) ValidateReconciliation() error {
+	for _, d := range c.MedicationReconciliation.Decisions {
+		if !d.Status.IsValid() {
+			return fmt.Errorf("%w: %s has unknown status %q", ErrReconciliationIncomplete, d.Medication, d.Status)
+		}
+	}
+
+	for _, row := range c.ReconciliationTable() {
+		if !row.chronic || strings.TrimSpace(row.Reason) != "" {
+			continue
+		}
+		if row.Status == ReconciliationChanged || row.Status == ReconciliationStopped {
+			return fmt.Errorf("%w: reason required for %s (%s)", ErrReconciliationIncomplete, row.Medication, row.Status)
+		}
+	}
+	return nil
+}
+
+func applyDecision(row ReconciliationRow, d ReconciliationDecision) ReconciliationRow {
+	if d.Status == "" {
+		return row
+	}
+	row.Status = d.Status
+	row.Reason = d.Reason
+	row.Confirmed = true
+	return row
+}
+
+func findMedication(meds []Medication, key string) *Medication {
+	for i := range meds {
+		if MedicationKey(meds[i].Name) == key {
+			return &meds[i]
+		}
+	}
+	return nil
+}
+
+// sameMedicationRegimen compares product and dose, ignoring duration
+func sameMedicationRegimen(a, b Medication) bool {
+	if FoldText(a.Name) != FoldText(b.Name) {
+		return false
+	}
+	if a.Dose != nil && b.Dose != nil {
+		da, db := *a.Dose, *b.Dose
+		da.DurationDays, db.DurationDays = 0, 0
+		return da == db
+	}
+	return FoldText(a.Dosage+" "+a.Frequency) == FoldText(b.Dosage+" "+b.Frequency)
+}
+
+// describeMedication renders a medication for the table, preferring the structured dose
+func describeMedication(m Medication) string {
+	parts := []string{m.Name}
+	if m.Dose != nil {
+		parts = append(parts, m.Dose.String())
+	} else if sig := strings.TrimSpace(m.Dosage + " " + m.Frequency); sig != "" {
+		parts = append(parts, sig)
+	}
+	return strings.Join(parts, " — ")
+}
@@ -10,6 +10,8 @@ import (
 type Report struct {
 	ID               uuid.UUID     `json:"id"`
 	HospitalID       uuid.UUID     `json:"hospital_id"`
+	PatientID        uuid.UUID     `json:"patient_id"`
+	EncounterID      uuid.UUID     `json:"encounter_id"`
 	PatientCNP       string        `json:"patient_cnp"`
 	PatientFirstName string        `json:"patient_first_name"`
 	PatientLastName  string        `json:"patient_last_name"`
@@ -21,24 +23,48 @@ type Report struct {
 	CreatedAt        time.Time     `json:"created_at"`
 	LastModified     time.Time     `json:"last_modified"`
 	FinalizedAt      *time.Time    `json:"finalized_at,omitempty"`
+	// TemplateID and TemplateVersion identify the template the report was created from
+	TemplateID      *uuid.UUID `json:"template_id,omitempty"`
+	TemplateVersion int        `json:"template_version,omitempty"`
+	// DRG is assigned when a discharge summary is signed
+	DRG *DRGAssignment `json:"drg,omitempty"`
+	// Signature is recorded when the report is signed
+	Signature *Signature `json:"signature,omitempty"`
 }
 
-// NewReport creates a new report in draft status
-func NewReport(hospitalID uuid.UUID, patientCNP, firstName, lastName string, specialty Specialty, reportType ReportType, doctorID uuid.UUID) *Report {
+// NewReport creates a new report in draft status for a registered patient
+// within one of their encounters. The patient's identity and the admission
+// details are copied onto the report and its patient data section.
+func NewReport(patient *Patient, encounter *Encounter, specialty Specialty, reportType ReportType, doctorID uuid.UUID) *Report {
 	now := time.Now()
-	return &Report{
-		ID:               uuid.New(),
-		HospitalID:       hospitalID,
-		PatientCNP:       patientCNP,
-		PatientFirstName: firstName,
-		PatientLastName:  lastName,
-		Specialty:        specialty,
-		ReportType:       reportType,
-		Status:           StatusDraft,
-		Content:          ReportContent{},
-		CreatedBy:        doctorID,
-		CreatedAt:        now,
-		LastModified:     now,
+	report := &Report{
+		ID:           uuid.New(),
+		HospitalID:   patient.HospitalID,
+		Specialty:    specialty,
+		ReportType:   reportType,
+		Status:       StatusDraft,
+		Content:      NewContent(reportType),
+		CreatedBy:    doctorID,
+		CreatedAt:    now,
+		LastModified: now,
+	}
+	report.SetPatient(patient)
+	report.SetEncounter(encounter)
+	return report
+}
+
+// SetPatient copies the registry identity onto the report
+func (r *Report) SetPatient(patient *Patient) {
+	r.PatientID = patient.ID
+	r.PatientCNP = patient.CNP
+	r.PatientFirstName = patient.FirstName
+	r.PatientLastName = patient.LastName
+
+	r.Content.PatientData.CNP = patient.CNP
+	r.Content.PatientData.FirstName = patient.FirstName
+	r.Content.PatientData.LastName = patient.LastName
+	if patient.BirthDate != nil {
+		r.Content.PatientData.BirthDate = *patient.BirthDate
 	}
 }
 
@@ -53,6 +79,32 @@ const (
 	SpecialtySurgery          Specialty = "surgery"
 )
 
+func (s Specialty) IsValid() bool {
+	switch s {
+	case SpecialtyInternalMedicine, SpecialtyCardiology, SpecialtyNeurology, SpecialtyPediatrics, SpecialtySurgery:
+		return true
+	}
+	return false
+}
+
+// DepartmentName is the department a specialty's reports are written in when
+// the admission does not name one
+func (s Specialty) DepartmentName() string {
+	switch s {
+	case SpecialtyInternalMedicine:
+		return "Medicină internă"
+	case SpecialtyCardiology:
+		return "Cardiologie"
+	case SpecialtyNeurology:
+		return "Neurologie"
+	case SpecialtyPediatrics:
+		return "Pediatrie"
+	case SpecialtySurgery:
+		return "Chirurgie generală"
+	}
+	return string(s)
+}
+
 // ReportType represents type of medical report
 type ReportType string
 
@@ -62,6 +114,27 @@ const (
 	ReportTypeOperativeNote    ReportType = "operative_note"
 )
 
+func (t ReportType) IsValid() bool {
+	switch t {
+	case ReportTypeDischargeSummary, ReportTypeTransferSummary, ReportTypeOperativeNote:
+		return true
+	}
+	return false
+}
+
+// Title is the document title printed on the report
+func (t ReportType) Title() string {
+	switch t {
+	case ReportTypeDischargeSummary:
+		return "Scrisoare medicală"
+	case ReportTypeTransferSummary:
+		return "Bilet de transfer"
+	case ReportTypeOperativeNote:
+		return "Protocol operator"
+	}
+	return string(t)
+}
+
 // Status represents report workflow status
 type Status string
 
@@ -125,6 +198,14 @@ type ICD10Reference struct {
 	Code          string `json:"code"`
 	DescriptionRO string `json:"description_ro"`
 	Category      string `json:"category"`
+	// Billable is false for category-level codes that have subdivisions (e.g. E11)
+	Billable    bool       `json:"billable"`
+	Level       ICD10Level `json:"level"`
+	ParentCode  string     `json:"parent_code,omitempty"`
+	BlockCode   string     `json:"block_code,omitempty"`
+	ChapterCode string     `json:"chapter_code,omitempty"`
+	// Active is false for codes retired by a newer release
+	Active bool `json:"active"`
 }
 
 type MedicationReference struct {
@@ -134,4 +215,11 @@ type MedicationReference struct {
 	Form            string    `json:"form"`
 	Dosage          string    `json:"dosage"`
 	Manufacturer    string    `json:"manufacturer"`
+	// Nomenclature fields; Form is the pharmaceutical form and Dosage the strength
+	AuthorizationCode  string `json:"authorization_code,omitempty"`
+	ATCCode            string `json:"atc_code,omitempty"`
+	Package            string `json:"package,omitempty"`
+	PrescriptionStatus string `json:"prescription_status,omitempty"`
+	// MaxDailyDoseMg is the maximum daily dose of the (first) active substance
+	MaxDailyDoseMg *float64 `json:"max_daily_dose_mg,omitempty"`
 }
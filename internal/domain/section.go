@@ -43,17 +43,24 @@ func (s PatientDataSection) Validate() error {
 
 // AnamnesisSection contains medical history
 type AnamnesisSection struct {
-	ChiefComplaint         string `json:"chief_complaint"`
-	HistoryOfPresentIllness string `json:"history_of_present_illness"`
-	PastMedicalHistory     string `json:"past_medical_history"`
-	Allergies              string `json:"allergies"`
-	SocialHistory          string `json:"social_history"`
+	ChiefComplaint          string    `json:"chief_complaint"`
+	HistoryOfPresentIllness string    `json:"history_of_present_illness"`
+	PastMedicalHistory      string    `json:"past_medical_history"`
+	// Allergies holds free-text notes; AllergyList holds the structured entries used by safety checks
+	Allergies               string    `json:"allergies"`
+	AllergyList             []Allergy `json:"allergy_list,omitempty"`
+	SocialHistory           string    `json:"social_history"`
 }
 
 func (s AnamnesisSection) Validate() error {
 	if s.ChiefComplaint == "" {
 		return ErrEmptyField
 	}
+	for _, a := range s.AllergyList {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -88,6 +95,26 @@ type LabTest struct {
 	Result string `json:"result"`
 	Unit   string `json:"unit"`
 	Date   time.Time `json:"date"`
+
+	// Structured fields, filled when the test is linked to the lab catalog
+	LOINCCode     string   `json:"loinc_code,omitempty"`
+	Value         *float64 `json:"value,omitempty"`
+	ReferenceLow  *float64 `json:"reference_low,omitempty"`
+	ReferenceHigh *float64 `json:"reference_high,omitempty"`
+}
+
+// IsAbnormal reports whether a numeric result falls outside its reference range
+func (t LabTest) IsAbnormal() bool {
+	if t.Value == nil {
+		return false
+	}
+	if t.ReferenceLow != nil && *t.Value < *t.ReferenceLow {
+		return true
+	}
+	if t.ReferenceHigh != nil && *t.Value > *t.ReferenceHigh {
+		return true
+	}
+	return false
 }
 
 type Imaging struct {
@@ -132,15 +159,27 @@ type Medication struct {
 	Route     string     `json:"route"`
 	StartDate time.Time  `json:"start_date"`
 	EndDate   *time.Time `json:"end_date,omitempty"`
+	// Dose is the structured sig; when absent it is parsed from Dosage and Frequency
+	Dose      *Dose      `json:"dose,omitempty"`
 }
 
 type Procedure struct {
+	// Code is the ACHI procedure code; coded procedures must exist in the catalog
+	Code        string    `json:"code,omitempty"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	PerformedAt time.Time `json:"performed_at"`
 }
 
 func (s TreatmentSection) Validate() error {
+	for _, m := range s.Medications {
+		if m.Dose == nil {
+			continue
+		}
+		if err := m.Dose.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -157,17 +196,28 @@ func (s RecommendationsSection) Validate() error {
 	return nil
 }
 
-// ReportContent holds all sections
+// ReportContent holds all sections. Which sections a report stores and
+// how they are validated depends on its report type, see ContentModel.
 type ReportContent struct {
-	PatientData     PatientDataSection     `json:"patient_data"`
-	Anamnesis       AnamnesisSection       `json:"anamnesis"`
-	Examination     ExaminationSection     `json:"examination"`
-	LabResults      LabResultsSection      `json:"lab_results"`
-	Diagnosis       DiagnosisSection       `json:"diagnosis"`
-	Treatment       TreatmentSection       `json:"treatment"`
-	Recommendations RecommendationsSection `json:"recommendations"`
-}
-
+	PatientData              PatientDataSection              `json:"patient_data"`
+	Anamnesis                AnamnesisSection                `json:"anamnesis"`
+	Examination              ExaminationSection              `json:"examination"`
+	LabResults               LabResultsSection               `json:"lab_results"`
+	Diagnosis                DiagnosisSection                `json:"diagnosis"`
+	Treatment                TreatmentSection                `json:"treatment"`
+	Recommendations          RecommendationsSection          `json:"recommendations"`
+	MedicationReconciliation MedicationReconciliationSection `json:"medication_reconciliation"`
+	// OperativeNote is stored on operative notes only
+	OperativeNote            *OperativeNoteSection           `json:"operative_note,omitempty"`
+	// Transfer is stored on transfer summaries only
+	Transfer                 *TransferSection                `json:"transfer,omitempty"`
+	// CarriedForward is the provenance of fields prefilled from a previous report
+	CarriedForward           []CarriedField                  `json:"carried_forward,omitempty"`
+	// Checklist comes from the report's template
+	Checklist                []ChecklistItem                 `json:"checklist,omitempty"`
+}
+
+// Validate checks the sections of discharge-oriented reports
 func (c ReportContent) Validate() error {
 	if err := c.PatientData.Validate(); err != nil {
 		return err
@@ -180,7 +230,3 @@ func (c ReportContent) Validate() error {
 	}
 	return nil
 }
-
-func (c ReportContent) IsComplete() bool {
-	return c.Validate() == nil
-}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Signature records who signed a report and a hash of the content they
+// signed. Its verification token is printed on the report as a QR code, so
+// that whoever receives the paper letter can check it is genuine.
+type Signature struct {
+	SignedBy    uuid.UUID `json:"signed_by"`
+	SignedAt    time.Time `json:"signed_at"`
+	ContentHash string    `json:"content_hash"`
+	Token       string    `json:"verification_token"`
+}
+
+// verificationTokenBytes gives 16 base32 characters, short enough to type
+// from paper and too many to guess
+const verificationTokenBytes = 10
+
+// NewSignature signs the content on behalf of the user
+func NewSignature(content ReportContent, signedBy uuid.UUID, signedAt time.Time) (*Signature, error) {
+	hash, err := HashContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	random := make([]byte, verificationTokenBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	return &Signature{
+		SignedBy:    signedBy,
+		SignedAt:    signedAt,
+		ContentHash: hash,
+		Token:       base32.StdEncoding.EncodeToString(random),
+	}, nil
+}
+
+// HashContent is the hex SHA-256 of the report content as stored
+func HashContent(content ReportContent) (string, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// NormalizeVerificationToken accepts a token typed by hand: lower case,
+// with spaces or dashes between groups
+func NormalizeVerificationToken(token string) string {
+	token = strings.ToUpper(token)
+	return strings.NewReplacer(" ", "", "-", "").Replace(token)
+}
+
+// ReportVerification is what anyone holding a printed report may learn
+// from its verification token: which report was signed, when and by whom.
+// It carries no clinical data.
+type ReportVerification struct {
+	ReportID    uuid.UUID  `json:"report_id"`
+	ReportType  ReportType `json:"report_type"`
+	Hospital    string     `json:"hospital"`
+	SignedAt    time.Time  `json:"signed_at"`
+	Signer      string     `json:"signer"`
+	ContentHash string     `json:"content_hash"`
+}
@@ -0,0 +1,304 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChecklistItem is a check the doctor confirms before the report leaves
+// draft, such as "Pacient informat despre tratament"
+type ChecklistItem struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Required bool   `json:"required"`
+	Checked  bool   `json:"checked"`
+}
+
+// ReportTemplate prefills new reports of a report type, optionally narrowed
+// to a specialty and a department. Every change creates a new version; reports
+// record the version they were created from.
+type ReportTemplate struct {
+	ID               uuid.UUID       `json:"id"`
+	HospitalID       uuid.UUID       `json:"hospital_id"`
+	Name             string          `json:"name"`
+	Specialty        Specialty       `json:"specialty,omitempty"`
+	ReportType       ReportType      `json:"report_type"`
+	DepartmentID     *uuid.UUID      `json:"department_id,omitempty"`
+	Active           bool            `json:"active"`
+	Version          int             `json:"version"`
+	Defaults         ReportContent   `json:"defaults"`
+	RequiredSections []string        `json:"required_sections"`
+	Checklist        []ChecklistItem `json:"checklist"`
+	VersionCreatedBy uuid.UUID       `json:"version_created_by"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// TemplateInput is the full definition of a template version
+type TemplateInput struct {
+	Name             string          `json:"name"`
+	Specialty        Specialty       `json:"specialty,omitempty"`
+	ReportType       ReportType      `json:"report_type"`
+	DepartmentID     *uuid.UUID      `json:"department_id,omitempty"`
+	Defaults         ReportContent   `json:"defaults"`
+	RequiredSections []string        `json:"required_sections"`
+	Checklist        []ChecklistItem `json:"checklist"`
+}
+
+// NewReportTemplate creates version 1 of a template
+func NewReportTemplate(hospitalID uuid.UUID, input TemplateInput, userID uuid.UUID) *ReportTemplate {
+	now := time.Now()
+	t := &ReportTemplate{
+		ID:         uuid.New(),
+		HospitalID: hospitalID,
+		Active:     true,
+		CreatedAt:  now,
+	}
+	t.Revise(input, userID)
+	return t
+}
+
+// Revise replaces the template's definition with a new version
+func (t *ReportTemplate) Revise(input TemplateInput, userID uuid.UUID) {
+	t.Name = strings.TrimSpace(input.Name)
+	t.Specialty = input.Specialty
+	t.ReportType = input.ReportType
+	t.DepartmentID = input.DepartmentID
+	t.Defaults = input.Defaults
+	t.RequiredSections = append([]string{}, input.RequiredSections...)
+	t.Checklist = make([]ChecklistItem, len(input.Checklist))
+	for i, item := range input.Checklist {
+		item.Key = strings.TrimSpace(item.Key)
+		item.Label = strings.TrimSpace(item.Label)
+		item.Checked = false
+		t.Checklist[i] = item
+	}
+	t.Version++
+	t.VersionCreatedBy = userID
+	t.UpdatedAt = time.Now()
+}
+
+func (t *ReportTemplate) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("%w: name", ErrEmptyField)
+	}
+	if !t.ReportType.IsValid() {
+		return fmt.Errorf("%w: unknown report type %q", ErrInvalidTemplate, t.ReportType)
+	}
+	if t.Specialty != "" && !t.Specialty.IsValid() {
+		return fmt.Errorf("%w: unknown specialty %q", ErrInvalidTemplate, t.Specialty)
+	}
+
+	model := t.ReportType.Content()
+	for _, name := range t.RequiredSections {
+		if name == "patient_data" || !model.HasSection(name) {
+			return fmt.Errorf("%w: %s reports have no section %q", ErrInvalidTemplate, t.ReportType, name)
+		}
+	}
+
+	keys := make(map[string]bool, len(t.Checklist))
+	for _, item := range t.Checklist {
+		if item.Key == "" || item.Label == "" {
+			return fmt.Errorf("%w: checklist items need a key and a label", ErrInvalidTemplate)
+		}
+		if keys[item.Key] {
+			return fmt.Errorf("%w: duplicate checklist key %q", ErrInvalidTemplate, item.Key)
+		}
+		keys[item.Key] = true
+	}
+	return nil
+}
+
+// MatchTemplate picks the active template for a new report. Templates of
+// another department or specialty never match; among the rest, a matching
+// department counts more than a matching specialty.
+func MatchTemplate(templates []*ReportTemplate, specialty Specialty, reportType ReportType, departmentID *uuid.UUID) *ReportTemplate {
+	var best *ReportTemplate
+	bestScore := -1
+	for _, t := range templates {
+		if !t.Active || t.ReportType != reportType {
+			continue
+		}
+
+		score := 0
+		if t.DepartmentID != nil {
+			if departmentID == nil || *t.DepartmentID != *departmentID {
+				continue
+			}
+			score += 2
+		}
+		if t.Specialty != "" {
+			if t.Specialty != specialty {
+				continue
+			}
+			score++
+		}
+
+		if score > bestScore {
+			best, bestScore = t, score
+		}
+	}
+	return best
+}
+
+// ApplyTemplate fills the empty fields of the report with the template's
+// defaults and copies its checklist. The patient data comes from the
+// registry and the encounter, never from the template.
+func (r *Report) ApplyTemplate(t *ReportTemplate) error {
+	defaults := t.Defaults
+	defaults.PatientData = PatientDataSection{}
+	defaults.CarriedForward = nil
+	defaults.Checklist = nil
+
+	merged, err := fillEmpty(r.Content, defaults)
+	if err != nil {
+		return err
+	}
+	r.Content = merged
+	r.Content.Normalize(r.ReportType)
+	r.Content.Checklist = append([]ChecklistItem(nil), t.Checklist...)
+
+	id := t.ID
+	r.TemplateID = &id
+	r.TemplateVersion = t.Version
+	return nil
+}
+
+// KeepChecklist restores the checklist items stored on the report, keeping
+// only whether each one is checked from the submitted content
+func (c *ReportContent) KeepChecklist(stored ReportContent) {
+	checked := make(map[string]bool, len(c.Checklist))
+	for _, item := range c.Checklist {
+		checked[item.Key] = item.Checked
+	}
+
+	c.Checklist = nil
+	for _, item := range stored.Checklist {
+		item.Checked = checked[item.Key]
+		c.Checklist = append(c.Checklist, item)
+	}
+}
+
+// CheckTemplate verifies that the template's required sections are filled and
+// its required checklist items are checked
+func (t *ReportTemplate) CheckTemplate(content ReportContent) error {
+	filled, err := filledSections(content)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, name := range t.RequiredSections {
+		if !filled[name] {
+			missing = append(missing, "section "+name)
+		}
+	}
+
+	checked := make(map[string]bool, len(content.Checklist))
+	for _, item := range content.Checklist {
+		checked[item.Key] = item.Checked
+	}
+	for _, item := range t.Checklist {
+		if item.Required && !checked[item.Key] {
+			missing = append(missing, "checklist item "+item.Label)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrTemplateIncomplete, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func filledSections(content ReportContent) (map[string]bool, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	filled := make(map[string]bool, len(fields))
+	for name, value := range fields {
+		filled[name] = !isEmptyJSON(value)
+	}
+	return filled, nil
+}
+
+func contentFields(content ReportContent) map[string]interface{} {
+	data, _ := json.Marshal(content)
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+// fillEmpty copies into content every value of defaults whose field is empty
+// in content, recursing into objects
+func fillEmpty(content, defaults ReportContent) (ReportContent, error) {
+	dst := contentFields(content)
+	mergeEmpty(dst, contentFields(defaults))
+
+	var merged ReportContent
+	if err := remarshal(dst, &merged); err != nil {
+		return content, err
+	}
+	return merged, nil
+}
+
+// remarshal decodes the JSON encoding of src into dst
+func remarshal(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func mergeEmpty(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if isEmptyJSON(value) {
+			continue
+		}
+		current, ok := dst[key]
+		if !ok || isEmptyJSON(current) {
+			dst[key] = value
+			continue
+		}
+		if currentMap, ok := current.(map[string]interface{}); ok {
+			if valueMap, ok := value.(map[string]interface{}); ok {
+				mergeEmpty(currentMap, valueMap)
+			}
+		}
+	}
+}
+
+// isEmptyJSON reports whether a decoded JSON value holds nothing but zero
+// values. Zero timestamps count as empty.
+func isEmptyJSON(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == "" || v == "0001-01-01T00:00:00Z"
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		for _, field := range v {
+			if !isEmptyJSON(field) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
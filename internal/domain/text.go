@@ -0,0 +1,14 @@
+package domain
+
+import "strings"
+
+var diacriticsFolder = strings.NewReplacer(
+	"ă", "a", "â", "a", "î", "i", "ș", "s", "ş", "s", "ț", "t", "ţ", "t",
+	"Ă", "a", "Â", "a", "Î", "i", "Ș", "s", "Ş", "s", "Ț", "t", "Ţ", "t",
+)
+
+// FoldText lower-cases s, strips Romanian diacritics (including the legacy
+// cedilla forms) and collapses whitespace, for diacritics-insensitive matching
+func FoldText(s string) string {
+	return strings.Join(strings.Fields(diacriticsFolder.Replace(strings.ToLower(s))), " ")
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimelineEventType classifies an entry of the patient timeline
+type TimelineEventType string
+
+const (
+	TimelineAdmission         TimelineEventType = "admission"
+	TimelineDischarge         TimelineEventType = "discharge"
+	TimelineDiagnosis         TimelineEventType = "diagnosis"
+	TimelineProcedure         TimelineEventType = "procedure"
+	TimelineMedicationStarted TimelineEventType = "medication_started"
+	TimelineMedicationChanged TimelineEventType = "medication_changed"
+	TimelineMedicationStopped TimelineEventType = "medication_stopped"
+	TimelineLabAbnormal       TimelineEventType = "lab_abnormal"
+)
+
+// TimelineEvent is one dated fact taken from a signed report
+type TimelineEvent struct {
+	Date       time.Time         `json:"date"`
+	Type       TimelineEventType `json:"type"`
+	Title      string            `json:"title"`
+	Detail     string            `json:"detail,omitempty"`
+	Code       string            `json:"code,omitempty"`
+	ReportID   uuid.UUID         `json:"report_id"`
+	ReportType ReportType        `json:"report_type"`
+	Department string            `json:"department,omitempty"`
+}
+
+// BuildTimeline aggregates the content of signed reports into a
+// chronological list of events. Other reports are ignored since only signed
+// content is part of the medical record.
+func BuildTimeline(reports []*Report) []TimelineEvent {
+	events := []TimelineEvent{}
+	for _, report := range reports {
+		if report.Status != StatusSigned {
+			continue
+		}
+		events = append(events, reportTimeline(report)...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+	return events
+}
+
+func reportTimeline(report *Report) []TimelineEvent {
+	content := report.Content
+	patient := content.PatientData
+
+	admitted := patient.AdmissionDate
+	if admitted.IsZero() {
+		admitted = report.CreatedAt
+	}
+	discharged := patient.DischargeDate
+	if discharged.IsZero() {
+		discharged = admitted
+		if report.FinalizedAt != nil {
+			discharged = *report.FinalizedAt
+		}
+	}
+
+	var events []TimelineEvent
+	add := func(date time.Time, eventType TimelineEventType, title, detail, code string) {
+		events = append(events, TimelineEvent{
+			Date:       date,
+			Type:       eventType,
+			Title:      title,
+			Detail:     detail,
+			Code:       code,
+			ReportID:   report.ID,
+			ReportType: report.ReportType,
+			Department: patient.Department,
+		})
+	}
+	orDate := func(date, fallback time.Time) time.Time {
+		if date.IsZero() {
+			return fallback
+		}
+		return date
+	}
+
+	if report.ReportType == ReportTypeDischargeSummary {
+		add(admitted, TimelineAdmission, "Internare", content.Anamnesis.ChiefComplaint, "")
+		add(discharged, TimelineDischarge, "Externare", content.Recommendations.DischargePlan, "")
+	}
+
+	if d := content.Diagnosis.PrimaryDiagnosis; d.Code != "" {
+		add(discharged, TimelineDiagnosis, d.Description, "Diagnostic principal", d.Code)
+	}
+	for _, d := range content.Diagnosis.SecondaryDiagnoses {
+		if d.Code != "" {
+			add(discharged, TimelineDiagnosis, d.Description, "Diagnostic secundar", d.Code)
+		}
+	}
+
+	for _, p := range content.Treatment.Procedures {
+		add(orDate(p.PerformedAt, admitted), TimelineProcedure, p.Name, p.Description, "")
+	}
+
+	for _, m := range content.Treatment.Medications {
+		add(orDate(m.StartDate, admitted), TimelineMedicationStarted, m.Name, describeSig(m), "")
+		if m.EndDate != nil && !m.EndDate.IsZero() {
+			add(*m.EndDate, TimelineMedicationStopped, m.Name, "", "")
+		}
+	}
+
+	// Chronic medication decisions take effect at discharge
+	for _, row := range content.ReconciliationTable() {
+		switch row.Status {
+		case ReconciliationNew:
+			if row.InHospital == "" {
+				add(discharged, TimelineMedicationStarted, row.Medication, joinDetail(row.Discharge, row.Reason), "")
+			}
+		case ReconciliationChanged:
+			add(discharged, TimelineMedicationChanged, row.Medication, joinDetail(row.PreAdmission+" → "+row.Discharge, row.Reason), "")
+		case ReconciliationStopped:
+			if row.PreAdmission != "" {
+				add(discharged, TimelineMedicationStopped, row.Medication, row.Reason, "")
+			}
+		}
+	}
+
+	for _, t := range content.LabResults.LaboratoryTests {
+		if !t.IsAbnormal() {
+			continue
+		}
+		add(orDate(t.Date, admitted), TimelineLabAbnormal, t.Name, describeLabResult(t), t.LOINCCode)
+	}
+
+	return events
+}
+
+func describeSig(m Medication) string {
+	if m.Dose != nil {
+		return m.Dose.String()
+	}
+	return strings.TrimSpace(m.Dosage + " " + m.Frequency)
+}
+
+func describeLabResult(t LabTest) string {
+	detail := strings.TrimSpace(fmt.Sprintf("%g %s", *t.Value, t.Unit))
+	switch {
+	case t.ReferenceLow != nil && t.ReferenceHigh != nil:
+		detail += fmt.Sprintf(" (ref. %g–%g)", *t.ReferenceLow, *t.ReferenceHigh)
+	case t.ReferenceLow != nil:
+		detail += fmt.Sprintf(" (ref. ≥ %g)", *t.ReferenceLow)
+	case t.ReferenceHigh != nil:
+		detail += fmt.Sprintf(" (ref. ≤ %g)", *t.ReferenceHigh)
+	}
+	return detail
+}
+
+func joinDetail(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "; ")
+}
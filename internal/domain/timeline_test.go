@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// event is the part of a timeline event a test compares
+type event struct {
+	Date   time.Time
+	Type   TimelineEventType
+	Title  string
+	Detail string
+	Code   string
+}
+
+func events(timeline []TimelineEvent) []event {
+	got := make([]event, len(timeline))
+	for i, e := range timeline {
+		got[i] = event{e.Date, e.Type, e.Title, e.Detail, e.Code}
+	}
+	return got
+}
+
+func compareEvents(t *testing.T, got []TimelineEvent, want []event) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d:\n%+v", len(got), len(want), events(got))
+	}
+	for i, e := range events(got) {
+		if !e.Date.Equal(want[i].Date) || e.Type != want[i].Type || e.Title != want[i].Title ||
+			e.Detail != want[i].Detail || e.Code != want[i].Code {
+			t.Errorf("event %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+}
+
+func march(day, hour int) time.Time {
+	return time.Date(2026, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+func signedReport(reportType ReportType, content ReportContent) *Report {
+	finalized := march(9, 14)
+	return &Report{
+		ID:          uuid.New(),
+		ReportType:  reportType,
+		Status:      StatusSigned,
+		Content:     content,
+		CreatedAt:   march(2, 12),
+		FinalizedAt: &finalized,
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	admitted, discharged := march(2, 10), march(9, 12)
+	furosemidEnd := march(6, 8)
+
+	var c ReportContent
+	c.PatientData = PatientDataSection{Department: "Cardiologie", AdmissionDate: admitted, DischargeDate: discharged}
+	c.Anamnesis.ChiefComplaint = "Dispnee"
+	c.Recommendations.DischargePlan = "Control peste 30 de zile"
+	c.Diagnosis.PrimaryDiagnosis = ICD10Code{Code: "I50.0", Description: "Insuficiență cardiacă congestivă"}
+	c.Diagnosis.SecondaryDiagnoses = []ICD10Code{{Code: "I10", Description: "Hipertensiune arterială esențială"}, {Description: "necodificat"}}
+	c.Treatment.Procedures = []Procedure{
+		{Name: "Coronarografie", Description: "Abord radial", PerformedAt: march(4, 9)},
+		{Name: "Ecocardiografie"},
+	}
+	c.Treatment.Medications = []Medication{
+		{Name: "Furosemid", Dosage: "40mg", Frequency: "1/zi", StartDate: march(3, 8), EndDate: &furosemidEnd},
+		{Name: "Bisoprolol", Dosage: "5mg", Frequency: "1/zi"},
+	}
+	c.MedicationReconciliation = MedicationReconciliationSection{
+		PreAdmission: []Medication{
+			{Name: "Enalapril", Dosage: "10mg", Frequency: "1/zi"},
+			{Name: "Aspirină", Dosage: "75mg", Frequency: "1/zi"},
+		},
+		Discharge: []Medication{
+			{Name: "Enalapril", Dosage: "20mg", Frequency: "1/zi"},
+			{Name: "Spironolactonă", Dosage: "25mg", Frequency: "1/zi"},
+			{Name: "Bisoprolol", Dosage: "5mg", Frequency: "1/zi"},
+		},
+		Decisions: []ReconciliationDecision{
+			{Medication: "Enalapril", Status: ReconciliationChanged, Reason: "TA necontrolată"},
+			{Medication: "Aspirina", Status: ReconciliationStopped, Reason: "Risc hemoragic"},
+		},
+	}
+	c.LabResults.LaboratoryTests = []LabTest{
+		{Name: "NT-proBNP", LOINCCode: "33762-6", Unit: "pg/mL", Date: march(2, 11), Value: floatPtr(2400), ReferenceHigh: floatPtr(125)},
+		{Name: "Potasiu", Unit: "mmol/L", Date: march(2, 11), Value: floatPtr(4.2), ReferenceLow: floatPtr(3.5), ReferenceHigh: floatPtr(5.1)},
+		{Name: "Hemoglobină", Result: "normală"},
+		{Name: "Glicemie", Unit: "mg/dL", Value: floatPtr(180), ReferenceLow: floatPtr(70), ReferenceHigh: floatPtr(110)},
+	}
+
+	report := signedReport(ReportTypeDischargeSummary, c)
+	timeline := BuildTimeline([]*Report{report})
+
+	compareEvents(t, timeline, []event{
+		// Undated entries fall back to the admission
+		{admitted, TimelineAdmission, "Internare", "Dispnee", ""},
+		{admitted, TimelineProcedure, "Ecocardiografie", "", ""},
+		{admitted, TimelineMedicationStarted, "Bisoprolol", "5mg 1/zi", ""},
+		{admitted, TimelineLabAbnormal, "Glicemie", "180 mg/dL (ref. 70–110)", ""},
+		{march(2, 11), TimelineLabAbnormal, "NT-proBNP", "2400 pg/mL (ref. ≤ 125)", "33762-6"},
+		{march(3, 8), TimelineMedicationStarted, "Furosemid", "40mg 1/zi", ""},
+		{march(4, 9), TimelineProcedure, "Coronarografie", "Abord radial", ""},
+		{furosemidEnd, TimelineMedicationStopped, "Furosemid", "", ""},
+		// Diagnoses and chronic medication decisions take effect at discharge
+		{discharged, TimelineDischarge, "Externare", "Control peste 30 de zile", ""},
+		{discharged, TimelineDiagnosis, "Insuficiență cardiacă congestivă", "Diagnostic principal", "I50.0"},
+		{discharged, TimelineDiagnosis, "Hipertensiune arterială esențială", "Diagnostic secundar", "I10"},
+		{discharged, TimelineMedicationChanged, "Enalapril", "Enalapril — 10mg 1/zi → Enalapril — 20mg 1/zi; TA necontrolată", ""},
+		{discharged, TimelineMedicationStopped, "Aspirină", "Risc hemoragic", ""},
+		{discharged, TimelineMedicationStarted, "Spironolactonă", "Spironolactonă — 25mg 1/zi", ""},
+	})
+
+	for _, e := range timeline {
+		if e.ReportID != report.ID || e.ReportType != ReportTypeDischargeSummary || e.Department != "Cardiologie" {
+			t.Errorf("event %+v does not point to its report", e)
+		}
+	}
+}
+
+func TestBuildTimelineDates(t *testing.T) {
+	withDiagnosis := func(code string) ReportContent {
+		var c ReportContent
+		c.Diagnosis.PrimaryDiagnosis = ICD10Code{Code: code, Description: code}
+		return c
+	}
+	dated := func(c ReportContent, admitted, discharged time.Time) ReportContent {
+		c.PatientData.AdmissionDate, c.PatientData.DischargeDate = admitted, discharged
+		return c
+	}
+
+	draft := signedReport(ReportTypeDischargeSummary, withDiagnosis("J18.9"))
+	draft.Status = StatusDraft
+	draft.FinalizedAt = nil
+	unfinalized := signedReport(ReportTypeDischargeSummary, ReportContent{})
+	unfinalized.FinalizedAt = nil
+
+	tests := []struct {
+		name    string
+		reports []*Report
+		want    []event
+	}{
+		{"no reports", nil, []event{}},
+		{"unsigned reports are ignored", []*Report{draft}, []event{}},
+		{"stay dates from creation and signing", []*Report{signedReport(ReportTypeDischargeSummary, ReportContent{})}, []event{
+			{march(2, 12), TimelineAdmission, "Internare", "", ""},
+			{march(9, 14), TimelineDischarge, "Externare", "", ""},
+		}},
+		{"same-day stay without signing date", []*Report{unfinalized}, []event{
+			{march(2, 12), TimelineAdmission, "Internare", "", ""},
+			{march(2, 12), TimelineDischarge, "Externare", "", ""},
+		}},
+		{"operative note has no admission", []*Report{signedReport(ReportTypeOperativeNote, withDiagnosis("K80.2"))}, []event{
+			{march(9, 14), TimelineDiagnosis, "K80.2", "Diagnostic principal", "K80.2"},
+		}},
+		{"reports are merged in date order", []*Report{
+			signedReport(ReportTypeTransferSummary, dated(withDiagnosis("I21.9"), march(20, 8), march(22, 8))),
+			signedReport(ReportTypeTransferSummary, dated(withDiagnosis("I50.0"), march(1, 8), march(5, 8))),
+		}, []event{
+			{march(5, 8), TimelineDiagnosis, "I50.0", "Diagnostic principal", "I50.0"},
+			{march(22, 8), TimelineDiagnosis, "I21.9", "Diagnostic principal", "I21.9"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline := BuildTimeline(tt.reports)
+			// An empty timeline is a list, not null
+			if timeline == nil {
+				t.Fatal("BuildTimeline = nil, want an empty list")
+			}
+			compareEvents(t, timeline, tt.want)
+		})
+	}
+}
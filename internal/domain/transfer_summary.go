@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferSection describes where a patient is transferred and what the
+// receiving unit has to follow up on
+type TransferSection struct {
+	// Source is the department the patient leaves, taken from the encounter
+	Source                TransferUnit           `json:"source"`
+	Destination           TransferUnit           `json:"destination"`
+	Reason                string                 `json:"reason"`
+	CurrentStatus         string                 `json:"current_status"`
+	PendingInvestigations []PendingInvestigation `json:"pending_investigations"`
+	HandoffTasks          []HandoffTask          `json:"handoff_tasks"`
+}
+
+// TransferUnit is a department of this hospital, of another hospital on the
+// platform, or of an external hospital known only by name. Without a hospital
+// the unit belongs to the report's hospital.
+type TransferUnit struct {
+	HospitalID   *uuid.UUID `json:"hospital_id,omitempty"`
+	Hospital     string     `json:"hospital,omitempty"`
+	DepartmentID *uuid.UUID `json:"department_id,omitempty"`
+	Department   string     `json:"department"`
+}
+
+// PendingInvestigation is a test ordered before the transfer whose result is
+// not back yet
+type PendingInvestigation struct {
+	Name      string     `json:"name"`
+	OrderedAt *time.Time `json:"ordered_at,omitempty"`
+	Notes     string     `json:"notes,omitempty"`
+}
+
+// HandoffTask is something the receiving unit has to do
+type HandoffTask struct {
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+}
+
+func (s TransferSection) Validate() error {
+	if strings.TrimSpace(s.Destination.Department) == "" && s.Destination.DepartmentID == nil {
+		return fmt.Errorf("%w: destination department is required", ErrInvalidTransferSummary)
+	}
+	if s.Destination.HospitalID == nil && s.Destination.Hospital == "" && sameUnit(s.Source, s.Destination) {
+		return fmt.Errorf("%w: destination is the source department", ErrInvalidTransferSummary)
+	}
+	if strings.TrimSpace(s.Reason) == "" {
+		return fmt.Errorf("%w: reason for transfer is required", ErrInvalidTransferSummary)
+	}
+	if strings.TrimSpace(s.CurrentStatus) == "" {
+		return fmt.Errorf("%w: current status is required", ErrInvalidTransferSummary)
+	}
+	for _, investigation := range s.PendingInvestigations {
+		if strings.TrimSpace(investigation.Name) == "" {
+			return fmt.Errorf("%w: pending investigation name is empty", ErrInvalidTransferSummary)
+		}
+	}
+	for _, task := range s.HandoffTasks {
+		if strings.TrimSpace(task.Description) == "" {
+			return fmt.Errorf("%w: handoff task description is empty", ErrInvalidTransferSummary)
+		}
+	}
+	return nil
+}
+
+func sameUnit(a, b TransferUnit) bool {
+	if a.DepartmentID != nil && b.DepartmentID != nil {
+		return *a.DepartmentID == *b.DepartmentID
+	}
+	return FoldText(a.Department) == FoldText(b.Department)
+}
+
+func validateTransferContent(c ReportContent) error {
+	if err := c.PatientData.Validate(); err != nil {
+		return err
+	}
+	for _, a := range c.Anamnesis.AllergyList {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.Diagnosis.Validate(); err != nil {
+		return err
+	}
+	if c.Transfer == nil {
+		return ErrInvalidTransferSummary
+	}
+	return c.Transfer.Validate()
+}
+
+// HandoffStatus tracks a transfer in the receiving department's queue
+type HandoffStatus string
+
+const (
+	HandoffPending      HandoffStatus = "pending"
+	HandoffAcknowledged HandoffStatus = "acknowledged"
+)
+
+func (s HandoffStatus) IsValid() bool {
+	return s == HandoffPending || s == HandoffAcknowledged
+}
+
+// Handoff is a signed transfer summary waiting in the receiving department's
+// queue
+type Handoff struct {
+	ID                    uuid.UUID              `json:"id"`
+	HospitalID            uuid.UUID              `json:"hospital_id"`
+	DepartmentID          uuid.UUID              `json:"department_id"`
+	ReportID              uuid.UUID              `json:"report_id"`
+	SourceHospitalID      uuid.UUID              `json:"source_hospital_id"`
+	PatientID             uuid.UUID              `json:"patient_id"`
+	PatientCNP            string                 `json:"patient_cnp"`
+	PatientFirstName      string                 `json:"patient_first_name"`
+	PatientLastName       string                 `json:"patient_last_name"`
+	SourceDepartment      string                 `json:"source_department"`
+	Reason                string                 `json:"reason"`
+	CurrentStatus         string                 `json:"current_status"`
+	PendingInvestigations []PendingInvestigation `json:"pending_investigations"`
+	Tasks                 []HandoffTask          `json:"tasks"`
+	Status                HandoffStatus          `json:"status"`
+	CreatedAt             time.Time              `json:"created_at"`
+	AcknowledgedAt        *time.Time             `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy        *uuid.UUID             `json:"acknowledged_by,omitempty"`
+}
+
+// NewHandoff queues a signed transfer summary for the destination department.
+// It returns nil when the destination is not a department on the platform.
+func NewHandoff(report *Report) *Handoff {
+	transfer := report.Content.Transfer
+	if transfer == nil || transfer.Destination.DepartmentID == nil {
+		return nil
+	}
+
+	hospitalID := report.HospitalID
+	if transfer.Destination.HospitalID != nil {
+		hospitalID = *transfer.Destination.HospitalID
+	}
+
+	return &Handoff{
+		ID:                    uuid.New(),
+		HospitalID:            hospitalID,
+		DepartmentID:          *transfer.Destination.DepartmentID,
+		ReportID:              report.ID,
+		SourceHospitalID:      report.HospitalID,
+		PatientID:             report.PatientID,
+		PatientCNP:            report.PatientCNP,
+		PatientFirstName:      report.PatientFirstName,
+		PatientLastName:       report.PatientLastName,
+		SourceDepartment:      transfer.Source.Department,
+		Reason:                transfer.Reason,
+		CurrentStatus:         transfer.CurrentStatus,
+		PendingInvestigations: transfer.PendingInvestigations,
+		Tasks:                 transfer.HandoffTasks,
+		Status:                HandoffPending,
+		CreatedAt:             time.Now(),
+	}
+}
+
+// Acknowledge records that the receiving department took over the patient
+func (h *Handoff) Acknowledge(userID uuid.UUID) error {
+	if h.Status == HandoffAcknowledged {
+		return ErrHandoffAcknowledged
+	}
+
+	now := time.Now()
+	h.Status = HandoffAcknowledged
+	h.AcknowledgedAt = &now
+	h.AcknowledgedBy = &userID
+	return nil
+}
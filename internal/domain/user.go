@@ -6,17 +6,26 @@ import (
 	"github.com/google/uuid"
 )
 
+// User roles
+const (
+	RoleDoctor = "doctor"
+	RoleAdmin  = "admin"
+	// RolePlatformAdmin administers the whole platform and belongs to no
+	// hospital
+	RolePlatformAdmin = "platform_admin"
+)
+
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // Never expose password hash
-	FirstName    string    `json:"first_name"`
-	LastName     string    `json:"last_name"`
-	HospitalID   string    `json:"hospital_id"`
-	Specialty    string    `json:"specialty"`
-	Role         string    `json:"role"` // doctor, admin
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"` // Never expose password hash
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	HospitalID   *uuid.UUID `json:"hospital_id"` // nil for platform administrators and unassigned users
+	Specialty    string     `json:"specialty"`
+	Role         string     `json:"role"` // RoleDoctor, RoleAdmin or RolePlatformAdmin
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
 type LoginRequest struct {
@@ -38,17 +47,25 @@ type AuthResponse struct {
 	User  User   `json:"user"`
 }
 
-func NewUser(email, passwordHash, firstName, lastName, hospitalID, specialty string) *User {
+func NewUser(email, passwordHash, firstName, lastName string, hospitalID uuid.UUID, specialty string) *User {
 	return &User{
 		ID:           uuid.New(),
 		Email:        email,
 		PasswordHash: passwordHash,
 		FirstName:    firstName,
 		LastName:     lastName,
-		HospitalID:   hospitalID,
+		HospitalID:   &hospitalID,
 		Specialty:    specialty,
-		Role:         "doctor",
+		Role:         RoleDoctor,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
 }
+
+// TokenClaims identifies the user behind an authenticated request
+type TokenClaims struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	HospitalID string    `json:"hospital_id"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+// ValidationWarning is a non-blocking finding surfaced to the doctor.
+// Unlike validation errors it does not prevent saving or status changes.
+type ValidationWarning struct {
+	Code     string `json:"code"`
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+	Severity string `json:"severity,omitempty"`
+}
+
+const (
+	WarningNonBillableDiagnosis = "non_billable_diagnosis"
+	WarningDiagnosisNormalized  = "diagnosis_description_normalized"
+	WarningDrugInteraction      = "drug_interaction"
+	WarningAllergyConflict      = "allergy_conflict"
+	WarningMaxDailyDose         = "max_daily_dose_exceeded"
+	WarningUnstructuredDose     = "unstructured_dose"
+	WarningDRGUngroupable       = "drg_ungroupable"
+)
+
+// HasSeverity reports whether any warning carries the given severity
+func HasSeverity(warnings []ValidationWarning, severity string) bool {
+	for _, w := range warnings {
+		if w.Severity == severity {
+			return true
+		}
+	}
+	return false
+}
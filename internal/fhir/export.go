@@ -0,0 +1,664 @@
+package fhir
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/render"
+)
+
+// documentTypes are the LOINC document codes of the report types
+var documentTypes = map[domain.ReportType]Coding{
+	domain.ReportTypeDischargeSummary: {System: LOINCSystem, Code: "18842-5", Display: "Discharge summary"},
+	domain.ReportTypeTransferSummary:  {System: LOINCSystem, Code: "18761-7", Display: "Transfer summary note"},
+	domain.ReportTypeOperativeNote:    {System: LOINCSystem, Code: "11504-8", Display: "Surgical operation note"},
+}
+
+// DocumentBundle maps a report to a FHIR document: a Composition with one
+// section per content section, followed by the resources its sections
+// refer to. Resource IDs are derived from the report, so exporting the same
+// report twice yields the same bundle.
+func DocumentBundle(doc *render.Document) *Bundle {
+	b := &documentBuilder{doc: doc, report: doc.Report}
+	b.build()
+	return b.bundle
+}
+
+type documentBuilder struct {
+	doc    *render.Document
+	report *domain.Report
+	bundle *Bundle
+
+	patient   Reference
+	encounter Reference
+	custodian Reference
+	author    *Reference
+}
+
+// fhirSections maps each content section, in the order of the content model
+var fhirSections = map[string]func(b *documentBuilder, c domain.ReportContent) []CompositionSection{
+	"anamnesis":                 (*documentBuilder).anamnesis,
+	"examination":               (*documentBuilder).examination,
+	"lab_results":               (*documentBuilder).labResults,
+	"diagnosis":                 (*documentBuilder).diagnosis,
+	"treatment":                 (*documentBuilder).treatment,
+	"recommendations":           (*documentBuilder).recommendations,
+	"medication_reconciliation": (*documentBuilder).reconciliation,
+	"operative_note":            (*documentBuilder).operativeNote,
+	"transfer":                  (*documentBuilder).transfer,
+}
+
+func (b *documentBuilder) build() {
+	r := b.report
+	b.bundle = &Bundle{
+		ResourceType: "Bundle",
+		ID:           r.ID.String(),
+		Identifier:   &Identifier{System: "urn:ietf:rfc:3986", Value: "urn:uuid:" + r.ID.String()},
+		Type:         "document",
+		Timestamp:    dateTime(b.doc.Date()),
+		// The Composition is added first once its sections are known
+		Entry: []BundleEntry{{}},
+	}
+
+	b.custodian = b.add(r.HospitalID.String(), b.organization())
+	b.patient = b.add(r.PatientID.String(), b.patientResource())
+	b.encounter = b.add(r.EncounterID.String(), b.encounterResource())
+	if b.doc.Doctor != nil {
+		author := b.add(b.doc.Doctor.ID.String(), practitioner(b.doc.Doctor))
+		b.author = &author
+	}
+
+	var sections []CompositionSection
+	for _, name := range r.Content.Sections(r.ReportType) {
+		if write, ok := fhirSections[name]; ok {
+			sections = append(sections, write(b, r.Content)...)
+		}
+	}
+
+	composition := b.composition(sections)
+	b.bundle.Entry[0] = BundleEntry{FullURL: fullURL(composition.ID), Resource: composition}
+}
+
+// add appends a resource to the bundle and returns a reference to it
+func (b *documentBuilder) add(id string, resource interface{}) Reference {
+	url := fullURL(id)
+	b.bundle.Entry = append(b.bundle.Entry, BundleEntry{FullURL: url, Resource: resource})
+	return Reference{Reference: url}
+}
+
+// id derives a stable ID for the n-th resource of a kind within the report
+func (b *documentBuilder) id(kind string, n int) string {
+	return uuid.NewSHA1(b.report.ID, []byte(kind+"/"+strconv.Itoa(n))).String()
+}
+
+func fullURL(id string) string {
+	return "urn:uuid:" + id
+}
+
+func (b *documentBuilder) composition(sections []CompositionSection) *Composition {
+	r := b.report
+	c := &Composition{
+		ResourceType: "Composition",
+		ID:           r.ID.String(),
+		Identifier:   &Identifier{System: "urn:ietf:rfc:3986", Value: "urn:uuid:" + r.ID.String()},
+		Status:       "preliminary",
+		Type:         CodeableConcept{Coding: []Coding{documentTypes[r.ReportType]}, Text: r.ReportType.Title()},
+		Subject:      &b.patient,
+		Encounter:    &b.encounter,
+		Date:         dateTime(b.doc.Date()),
+		Title:        r.ReportType.Title(),
+		Custodian:    &b.custodian,
+		Section:      sections,
+	}
+
+	// Without the author's account the hospital stands as the author
+	if b.author != nil {
+		c.Author = []Reference{*b.author}
+	} else {
+		c.Author = []Reference{b.custodian}
+	}
+
+	if b.doc.Signed() {
+		c.Status = "final"
+		if sig := r.Signature; sig != nil {
+			attester := Attester{Mode: "legal", Time: dateTime(sig.SignedAt)}
+			if b.doc.Doctor != nil && b.doc.Doctor.ID == sig.SignedBy {
+				attester.Party = b.author
+			}
+			c.Attester = []Attester{attester}
+		}
+	}
+	return c
+}
+
+func (b *documentBuilder) organization() *Organization {
+	h := b.doc.Hospital
+	org := &Organization{ResourceType: "Organization", ID: h.ID.String(), Name: h.Name}
+	if h.CUI != "" {
+		org.Identifier = []Identifier{{System: CUISystem, Value: h.CUI}}
+	}
+	if address := h.Address.String(); address != "" {
+		org.Address = []Address{{Text: address}}
+	}
+	return org
+}
+
+func (b *documentBuilder) patientResource() *Patient {
+	p := b.report.Content.PatientData
+	patient := &Patient{
+		ResourceType: "Patient",
+		ID:           b.report.PatientID.String(),
+		Name:         []HumanName{{Use: "official", Family: p.LastName, Given: strings.Fields(p.FirstName)}},
+		BirthDate:    date(p.BirthDate),
+		Gender:       gender(domain.SexFromCNP(p.CNP)),
+	}
+	if p.CNP != "" {
+		patient.Identifier = []Identifier{{System: CNPSystem, Value: p.CNP}}
+	}
+	return patient
+}
+
+func gender(sex domain.Sex) string {
+	switch sex {
+	case domain.SexMale:
+		return "male"
+	case domain.SexFemale:
+		return "female"
+	}
+	return "unknown"
+}
+
+func (b *documentBuilder) encounterResource() *Encounter {
+	p := b.report.Content.PatientData
+	e := &Encounter{
+		ResourceType:    "Encounter",
+		ID:              b.report.EncounterID.String(),
+		Status:          "in-progress",
+		Class:           Coding{System: ActCodeSystem, Code: "IMP", Display: "inpatient encounter"},
+		Subject:         &b.patient,
+		ServiceProvider: &b.custodian,
+	}
+	if !p.AdmissionDate.IsZero() {
+		e.Period = &Period{Start: dateTime(p.AdmissionDate)}
+		if !p.DischargeDate.IsZero() {
+			e.Status = "finished"
+			e.Period.End = dateTime(p.DischargeDate)
+		}
+	}
+	if location := render.Location(p); location != "" {
+		e.Location = []EncounterLocation{{Location: Reference{Display: location}}}
+	}
+	return e
+}
+
+func practitioner(u *domain.User) *Practitioner {
+	return &Practitioner{
+		ResourceType: "Practitioner",
+		ID:           u.ID.String(),
+		Name:         []HumanName{{Family: u.LastName, Given: strings.Fields(u.FirstName), Prefix: []string{"Dr."}}},
+	}
+}
+
+func (b *documentBuilder) anamnesis(c domain.ReportContent) []CompositionSection {
+	a := c.Anamnesis
+	return []CompositionSection{{
+		Title: "Anamneză",
+		Code:  loinc("10164-2", "History of Present illness Narrative"),
+		Text: narrative(
+			field("Motivele internării", a.ChiefComplaint),
+			field("Istoricul bolii", a.HistoryOfPresentIllness),
+			field("Antecedente personale patologice", a.PastMedicalHistory),
+			field("Alergii", render.Allergies(a)),
+			field("Condiții de viață și muncă", a.SocialHistory),
+		),
+	}}
+}
+
+func (b *documentBuilder) examination(c domain.ReportContent) []CompositionSection {
+	e := c.Examination
+	return []CompositionSection{{
+		Title: "Examen clinic",
+		Code:  loinc("29545-1", "Physical findings Narrative"),
+		Text: narrative(
+			field("Stare generală", e.GeneralCondition),
+			field("Stare de conștiență", e.Consciousness),
+			field("Semne vitale", render.VitalSigns(e.VitalSigns)),
+			field("Examen pe aparate și sisteme", e.SystemsReview),
+		),
+		Entry: b.vitalSigns(e.VitalSigns),
+	}}
+}
+
+// vitalSigns records each vital sign as an observation at admission
+func (b *documentBuilder) vitalSigns(v domain.VitalSigns) []Reference {
+	effective := date(b.report.Content.PatientData.AdmissionDate)
+	var refs []Reference
+	add := func(code, display string, value *Quantity, components []ObservationComponent) {
+		id := b.id("vital-signs", len(refs))
+		refs = append(refs, b.add(id, &Observation{
+			ResourceType:      "Observation",
+			ID:                id,
+			Status:            "final",
+			Category:          []CodeableConcept{category(ObservationCategorySystem, "vital-signs", "Vital Signs")},
+			Code:              *loinc(code, display),
+			Subject:           &b.patient,
+			Encounter:         &b.encounter,
+			EffectiveDateTime: effective,
+			ValueQuantity:     value,
+			Component:         components,
+		}))
+	}
+
+	if systolic, diastolic, ok := bloodPressure(v.BloodPressure); ok {
+		add("85354-9", "Blood pressure panel with all children optional", nil, []ObservationComponent{
+			{Code: *loinc("8480-6", "Systolic blood pressure"), ValueQuantity: ucum(systolic, "mmHg", "mm[Hg]")},
+			{Code: *loinc("8462-4", "Diastolic blood pressure"), ValueQuantity: ucum(diastolic, "mmHg", "mm[Hg]")},
+		})
+	}
+	if v.HeartRate > 0 {
+		add("8867-4", "Heart rate", ucum(float64(v.HeartRate), "/min", "/min"), nil)
+	}
+	if v.Temperature > 0 {
+		add("8310-5", "Body temperature", ucum(v.Temperature, "°C", "Cel"), nil)
+	}
+	if v.RespiratoryRate > 0 {
+		add("9279-1", "Respiratory rate", ucum(float64(v.RespiratoryRate), "/min", "/min"), nil)
+	}
+	if v.OxygenSaturation > 0 {
+		add("59408-5", "Oxygen saturation in Arterial blood by Pulse oximetry", ucum(float64(v.OxygenSaturation), "%", "%"), nil)
+	}
+	return refs
+}
+
+// bloodPressure parses "150/90"
+func bloodPressure(s string) (float64, float64, bool) {
+	sys, dia, found := strings.Cut(strings.TrimSpace(s), "/")
+	if !found {
+		return 0, 0, false
+	}
+	systolic, err1 := strconv.Atoi(strings.TrimSpace(sys))
+	diastolic, err2 := strconv.Atoi(strings.TrimSpace(dia))
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return float64(systolic), float64(diastolic), true
+}
+
+func (b *documentBuilder) labResults(c domain.ReportContent) []CompositionSection {
+	lab := c.LabResults
+	if len(lab.LaboratoryTests) == 0 && len(lab.ImagingStudies) == 0 {
+		return nil
+	}
+
+	var lines []string
+	var refs []Reference
+	for i, t := range lab.LaboratoryTests {
+		lines = append(lines, field(t.Name, render.JoinNonEmpty(", ", render.LabResult(t), render.LabRange(t), render.FormatDate(t.Date))))
+
+		id := b.id("lab", i)
+		obs := &Observation{
+			ResourceType:      "Observation",
+			ID:                id,
+			Status:            "final",
+			Category:          []CodeableConcept{category(ObservationCategorySystem, "laboratory", "Laboratory")},
+			Code:              CodeableConcept{Text: t.Name},
+			Subject:           &b.patient,
+			Encounter:         &b.encounter,
+			EffectiveDateTime: date(t.Date),
+		}
+		if t.LOINCCode != "" {
+			obs.Code.Coding = []Coding{{System: LOINCSystem, Code: t.LOINCCode, Display: t.Name}}
+		}
+		if t.Value != nil {
+			obs.ValueQuantity = &Quantity{Value: *t.Value, Unit: t.Unit}
+		} else {
+			obs.ValueString = strings.TrimSpace(t.Result + " " + t.Unit)
+		}
+		if t.ReferenceLow != nil || t.ReferenceHigh != nil {
+			var rr ReferenceRange
+			if t.ReferenceLow != nil {
+				rr.Low = &Quantity{Value: *t.ReferenceLow, Unit: t.Unit}
+			}
+			if t.ReferenceHigh != nil {
+				rr.High = &Quantity{Value: *t.ReferenceHigh, Unit: t.Unit}
+			}
+			obs.ReferenceRange = []ReferenceRange{rr}
+		}
+		if t.IsAbnormal() {
+			code, display := "H", "High"
+			if t.ReferenceLow != nil && *t.Value < *t.ReferenceLow {
+				code, display = "L", "Low"
+			}
+			obs.Interpretation = []CodeableConcept{category(InterpretationSystem, code, display)}
+		}
+		refs = append(refs, b.add(id, obs))
+	}
+	for _, study := range lab.ImagingStudies {
+		lines = append(lines, field(render.JoinNonEmpty(" ", study.Type, render.FormatDate(study.Date)), study.Description))
+	}
+
+	return []CompositionSection{{
+		Title: "Investigații paraclinice",
+		Code:  loinc("30954-2", "Relevant diagnostic tests/laboratory data Narrative"),
+		Text:  narrative(lines...),
+		Entry: refs,
+	}}
+}
+
+func (b *documentBuilder) diagnosis(c domain.ReportContent) []CompositionSection {
+	d := c.Diagnosis
+	lines := []string{field("Diagnostic principal", render.Diagnosis(d.PrimaryDiagnosis))}
+	var refs []Reference
+	if ref, ok := b.condition("condition", d.PrimaryDiagnosis, 0); ok {
+		refs = append(refs, ref)
+	}
+	for i, code := range d.SecondaryDiagnoses {
+		lines = append(lines, field("Diagnostic secundar", render.Diagnosis(code)))
+		if ref, ok := b.condition("condition", code, i+1); ok {
+			refs = append(refs, ref)
+		}
+	}
+	lines = append(lines, field("Observații clinice", d.ClinicalObservations))
+
+	return []CompositionSection{{
+		Title: "Diagnostic",
+		Code:  loinc("11535-2", "Hospital discharge Dx Narrative"),
+		Text:  narrative(lines...),
+		Entry: refs,
+	}}
+}
+
+// condition adds an encounter diagnosis; diagnoses without code or
+// description are skipped
+func (b *documentBuilder) condition(kind string, code domain.ICD10Code, n int) (Reference, bool) {
+	if code.Code == "" && code.Description == "" {
+		return Reference{}, false
+	}
+	concept := &CodeableConcept{Text: code.Description}
+	if code.Code != "" {
+		concept.Coding = []Coding{{System: ICD10System, Code: code.Code, Display: code.Description}}
+	}
+	id := b.id(kind, n)
+	return b.add(id, &Condition{
+		ResourceType:       "Condition",
+		ID:                 id,
+		ClinicalStatus:     concept0(ConditionClinicalSystem, "active", "Active"),
+		VerificationStatus: concept0(ConditionVerStatusSystem, "confirmed", "Confirmed"),
+		Category:           []CodeableConcept{category(ConditionCategorySystem, "encounter-diagnosis", "Encounter Diagnosis")},
+		Code:               concept,
+		Subject:            b.patient,
+		Encounter:          &b.encounter,
+	}), true
+}
+
+func (b *documentBuilder) treatment(c domain.ReportContent) []CompositionSection {
+	t := c.Treatment
+	if len(t.Medications) == 0 && len(t.Procedures) == 0 {
+		return nil
+	}
+
+	lines := medicationLines(t.Medications)
+	refs := b.medicationStatements("inpatient-medication", t.Medications, "inpatient", "Inpatient")
+	for i, p := range t.Procedures {
+		lines = append(lines, field(render.JoinNonEmpty(" ", p.Code, p.Name, render.FormatDate(p.PerformedAt)), p.Description))
+		refs = append(refs, b.procedure("procedure", i, p.Code, p.Name, p.PerformedAt, "", p.Description))
+	}
+
+	return []CompositionSection{{
+		Title: "Tratament efectuat",
+		Code:  loinc("8648-8", "Hospital course Narrative"),
+		Text:  narrative(lines...),
+		Entry: refs,
+	}}
+}
+
+// medicationStatements adds a statement for each medication of a list
+func (b *documentBuilder) medicationStatements(kind string, meds []domain.Medication, categoryCode, categoryDisplay string) []Reference {
+	var refs []Reference
+	for i, m := range meds {
+		id := b.id(kind, i)
+		statement := &MedicationStatement{
+			ResourceType:              "MedicationStatement",
+			ID:                        id,
+			Status:                    "active",
+			Category:                  concept0(MedicationStatementCategory, categoryCode, categoryDisplay),
+			MedicationCodeableConcept: &CodeableConcept{Text: m.Name},
+			Subject:                   b.patient,
+			Context:                   &b.encounter,
+			Dosage:                    []Dosage{dosage(m)},
+		}
+		if !m.StartDate.IsZero() {
+			statement.EffectivePeriod = &Period{Start: date(m.StartDate)}
+		}
+		if m.EndDate != nil {
+			if statement.EffectivePeriod == nil {
+				statement.EffectivePeriod = &Period{}
+			}
+			statement.EffectivePeriod.End = date(*m.EndDate)
+			statement.Status = "completed"
+		}
+		refs = append(refs, b.add(id, statement))
+	}
+	return refs
+}
+
+func dosage(m domain.Medication) Dosage {
+	d := Dosage{Text: render.MedicationDose(m)}
+	route := m.Route
+	if m.Dose != nil {
+		d.AsNeeded = m.Dose.PRN
+		d.DoseAndRate = []DoseAndRate{{DoseQuantity: &Quantity{Value: m.Dose.Amount, Unit: string(m.Dose.Unit)}}}
+		if m.Dose.Route != "" {
+			route = string(m.Dose.Route)
+		}
+	}
+	if route != "" {
+		d.Route = &CodeableConcept{Text: route}
+	}
+	return d
+}
+
+func medicationLines(meds []domain.Medication) []string {
+	lines := make([]string, len(meds))
+	for i, m := range meds {
+		lines[i] = field(m.Name, render.JoinNonEmpty(", ", render.MedicationDose(m), m.Route, render.MedicationPeriod(m)))
+	}
+	return lines
+}
+
+func (b *documentBuilder) procedure(kind string, n int, code, name string, performed time.Time, surgeon, note string) Reference {
+	id := b.id(kind, n)
+	p := &Procedure{
+		ResourceType:      "Procedure",
+		ID:                id,
+		Status:            "completed",
+		Code:              &CodeableConcept{Text: name},
+		Subject:           b.patient,
+		Encounter:         &b.encounter,
+		PerformedDateTime: date(performed),
+	}
+	if code != "" {
+		p.Code.Coding = []Coding{{System: ACHISystem, Code: code, Display: name}}
+	}
+	if surgeon != "" {
+		p.Performer = []Performer{{Actor: Reference{Display: surgeon}}}
+	}
+	if note != "" {
+		p.Note = []Annotation{{Text: note}}
+	}
+	return b.add(id, p)
+}
+
+func (b *documentBuilder) recommendations(c domain.ReportContent) []CompositionSection {
+	r := c.Recommendations
+	return []CompositionSection{{
+		Title: "Recomandări la externare",
+		Code:  loinc("18776-5", "Plan of care note"),
+		Text: narrative(
+			field("Plan", r.DischargePlan),
+			field("Tratament", r.Medications),
+			field("Control", r.FollowUp),
+			field("Regim alimentar", r.DietRestrictions),
+			field("Activitate fizică", r.ActivityRestrictions),
+		),
+	}}
+}
+
+// reconciliation exports the chronic medication taken before admission and
+// the medication to continue after discharge as separate sections
+func (b *documentBuilder) reconciliation(c domain.ReportContent) []CompositionSection {
+	rec := c.MedicationReconciliation
+	var sections []CompositionSection
+	if len(rec.PreAdmission) > 0 {
+		sections = append(sections, CompositionSection{
+			Title: "Medicație cronică la internare",
+			Code:  loinc("10160-0", "History of Medication use Narrative"),
+			Text:  narrative(medicationLines(rec.PreAdmission)...),
+			Entry: b.medicationStatements("pre-admission-medication", rec.PreAdmission, "community", "Community"),
+		})
+	}
+	if len(rec.Discharge) > 0 {
+		lines := medicationLines(rec.Discharge)
+		for _, row := range c.ReconciliationTable() {
+			if row.Status == domain.ReconciliationStopped || row.Status == domain.ReconciliationChanged {
+				lines = append(lines, field(row.Medication, render.JoinNonEmpty(": ", row.Status.Label(), row.Reason)))
+			}
+		}
+		sections = append(sections, CompositionSection{
+			Title: "Medicație la externare",
+			Code:  loinc("10183-2", "Hospital discharge medications Narrative"),
+			Text:  narrative(lines...),
+			Entry: b.medicationStatements("discharge-medication", rec.Discharge, "community", "Community"),
+		})
+	}
+	return sections
+}
+
+func (b *documentBuilder) operativeNote(c domain.ReportContent) []CompositionSection {
+	n := c.OperativeNote
+	if n == nil {
+		return nil
+	}
+
+	var refs []Reference
+	if ref, ok := b.condition("postoperative-condition", n.PostOpDiagnosis, 0); ok {
+		refs = append(refs, ref)
+	}
+	performed := c.PatientData.AdmissionDate
+	for i, code := range n.ProcedureCodes {
+		refs = append(refs, b.procedure("operative-procedure", i, code.Code, code.Description, performed, n.Surgeon, n.Findings))
+	}
+
+	var blood string
+	if n.EstimatedBloodLossML != nil {
+		blood = fmt.Sprintf("%d ml", *n.EstimatedBloodLossML)
+	}
+	return []CompositionSection{{
+		Title: "Protocol operator",
+		Code:  loinc("59776-5", "Procedure findings Narrative"),
+		Text: narrative(
+			field("Diagnostic preoperator", render.Diagnosis(n.PreOpDiagnosis)),
+			field("Diagnostic postoperator", render.Diagnosis(n.PostOpDiagnosis)),
+			field("Operator", n.Surgeon),
+			field("Ajutoare", strings.Join(n.Assistants, ", ")),
+			field("Anestezie", render.JoinNonEmpty(", ", n.AnesthesiaType.Label(), n.Anesthesiologist)),
+			field("Incizie", n.Incision),
+			field("Constatări intraoperatorii", n.Findings),
+			field("Pierderi de sânge estimate", blood),
+			field("Complicații", n.Complications),
+		),
+		Entry: refs,
+	}}
+}
+
+func (b *documentBuilder) transfer(c domain.ReportContent) []CompositionSection {
+	t := c.Transfer
+	if t == nil {
+		return nil
+	}
+	lines := []string{
+		field("Din secția", render.JoinNonEmpty(", ", t.Source.Department, t.Source.Hospital)),
+		field("În secția", render.JoinNonEmpty(", ", t.Destination.Department, t.Destination.Hospital)),
+		field("Motivul transferului", t.Reason),
+		field("Starea actuală", t.CurrentStatus),
+	}
+	for _, investigation := range t.PendingInvestigations {
+		lines = append(lines, field("Investigație în așteptare", render.JoinNonEmpty(", ", investigation.Name, investigation.Notes)))
+	}
+	for _, task := range t.HandoffTasks {
+		lines = append(lines, field("De efectuat", task.Description))
+	}
+	return []CompositionSection{{
+		Title: "Transfer",
+		Code:  loinc("42349-1", "Reason for referral Narrative"),
+		Text:  narrative(lines...),
+	}}
+}
+
+// narrative builds the XHTML text of a section from escaped lines; a
+// section without content states so, since every section needs a text
+func narrative(lines ...string) *Narrative {
+	var b strings.Builder
+	b.WriteString(`<div xmlns="http://www.w3.org/1999/xhtml">`)
+	empty := true
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		empty = false
+		b.WriteString("<p>" + line + "</p>")
+	}
+	if empty {
+		b.WriteString("<p>Nu au fost consemnate date.</p>")
+	}
+	b.WriteString("</div>")
+	return &Narrative{Status: "generated", Div: b.String()}
+}
+
+// field is an escaped "Label: value" line of a narrative, empty without a value
+func field(label, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	value = strings.ReplaceAll(html.EscapeString(value), "\n", "<br/>")
+	return "<b>" + html.EscapeString(label) + ":</b> " + value
+}
+
+func loinc(code, display string) *CodeableConcept {
+	return &CodeableConcept{Coding: []Coding{{System: LOINCSystem, Code: code, Display: display}}}
+}
+
+func category(system, code, display string) CodeableConcept {
+	return CodeableConcept{Coding: []Coding{{System: system, Code: code, Display: display}}}
+}
+
+func concept0(system, code, display string) *CodeableConcept {
+	c := category(system, code, display)
+	return &c
+}
+
+func ucum(value float64, unit, code string) *Quantity {
+	return &Quantity{Value: value, Unit: unit, System: UCUMSystem, Code: code}
+}
+
+// date formats a FHIR date; zero dates are omitted
+func date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// dateTime formats a FHIR dateTime or instant
+func dateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,296 @@
+package fhir
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/render"
+)
+
+func testDocument(t *testing.T, reportType domain.ReportType, signed bool) *render.Document {
+	t.Helper()
+	hospital := &domain.Hospital{ID: uuid.New(), Name: "Spitalul Clinic Județean de Urgență Cluj", CUI: "4288080"}
+	doc := render.SampleDocument(hospital, reportType)
+	doc.Report.ID = uuid.New()
+	doc.Report.PatientID = uuid.New()
+	doc.Report.EncounterID = uuid.New()
+	doc.Doctor.ID = uuid.New()
+	doc.Report.CreatedBy = doc.Doctor.ID
+
+	if signed {
+		signedAt := time.Date(2026, time.March, 7, 10, 15, 0, 0, time.UTC)
+		doc.Report.Status = domain.StatusSigned
+		doc.Report.FinalizedAt = &signedAt
+		doc.Report.Signature = &domain.Signature{SignedBy: doc.Doctor.ID, SignedAt: signedAt}
+	}
+	return doc
+}
+
+func TestDocumentBundle(t *testing.T) {
+	reportTypes := []domain.ReportType{
+		domain.ReportTypeDischargeSummary,
+		domain.ReportTypeOperativeNote,
+		domain.ReportTypeTransferSummary,
+	}
+
+	for _, reportType := range reportTypes {
+		for _, signed := range []bool{false, true} {
+			name := string(reportType) + "/draft"
+			if signed {
+				name = string(reportType) + "/signed"
+			}
+			t.Run(name, func(t *testing.T) {
+				doc := testDocument(t, reportType, signed)
+				bundle := DocumentBundle(doc)
+
+				if err := bundle.Validate(); err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				checkDocumentStructure(t, bundle)
+				checkComposition(t, bundle, signed)
+				checkPatient(t, bundle, doc.Report.Content.PatientData.CNP)
+				checkConditions(t, bundle, reportType)
+				checkObservations(t, bundle, reportType)
+			})
+		}
+	}
+}
+
+// checkDocumentStructure checks the document invariants on the JSON form of
+// the bundle, as a receiving system reads it
+func checkDocumentStructure(t *testing.T, bundle *Bundle) {
+	t.Helper()
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var doc struct {
+		ResourceType string `json:"resourceType"`
+		Type         string `json:"type"`
+		Identifier   *struct {
+			System string `json:"system"`
+			Value  string `json:"value"`
+		} `json:"identifier"`
+		Timestamp string `json:"timestamp"`
+		Entry     []struct {
+			FullURL  string                 `json:"fullUrl"`
+			Resource map[string]interface{} `json:"resource"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if doc.ResourceType != "Bundle" || doc.Type != "document" {
+		t.Errorf("got %s of type %q, want a document Bundle", doc.ResourceType, doc.Type)
+	}
+	if doc.Identifier == nil || doc.Identifier.System == "" || doc.Identifier.Value == "" {
+		t.Errorf("Bundle.identifier is not set: %+v", doc.Identifier)
+	}
+	if _, err := time.Parse(time.RFC3339, doc.Timestamp); err != nil {
+		t.Errorf("Bundle.timestamp %q: %v", doc.Timestamp, err)
+	}
+	if len(doc.Entry) == 0 || doc.Entry[0].Resource["resourceType"] != "Composition" {
+		t.Fatalf("the first entry is not a Composition")
+	}
+
+	urls := make(map[string]bool)
+	for i, e := range doc.Entry {
+		if e.FullURL == "" || urls[e.FullURL] {
+			t.Errorf("entry %d: missing or duplicate fullUrl %q", i, e.FullURL)
+		}
+		urls[e.FullURL] = true
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			for key, value := range n {
+				if ref, ok := value.(string); ok && key == "reference" && !urls[ref] {
+					t.Errorf("reference %s does not resolve to an entry", ref)
+				}
+				walk(value)
+			}
+		case []interface{}:
+			for _, value := range n {
+				walk(value)
+			}
+		}
+	}
+	walk(tree)
+}
+
+func checkComposition(t *testing.T, bundle *Bundle, signed bool) {
+	t.Helper()
+	composition := bundle.Entry[0].Resource.(*Composition)
+
+	wantStatus := "preliminary"
+	if signed {
+		wantStatus = "final"
+	}
+	if composition.Status != wantStatus {
+		t.Errorf("Composition.status = %q, want %q", composition.Status, wantStatus)
+	}
+	if signed && (len(composition.Attester) != 1 || composition.Attester[0].Mode != "legal" || composition.Attester[0].Party == nil) {
+		t.Errorf("signed Composition has no legal attester: %+v", composition.Attester)
+	}
+	if !signed && len(composition.Attester) != 0 {
+		t.Errorf("draft Composition has attesters: %+v", composition.Attester)
+	}
+
+	if len(composition.Section) == 0 {
+		t.Fatalf("Composition has no sections")
+	}
+	for i, s := range composition.Section {
+		// cmp-1
+		if s.Text == nil && len(s.Entry) == 0 {
+			t.Errorf("section %d (%s) has neither text nor entries", i, s.Title)
+		}
+		if s.Code == nil || len(s.Code.Coding) == 0 || s.Code.Coding[0].System != LOINCSystem {
+			t.Errorf("section %d (%s) has no LOINC code", i, s.Title)
+		}
+	}
+}
+
+func TestOperativeNoteAnesthesia(t *testing.T) {
+	doc := testDocument(t, domain.ReportTypeOperativeNote, true)
+	composition := DocumentBundle(doc).Entry[0].Resource.(*Composition)
+
+	want := "<b>Anestezie:</b> generală, Dr. Pop Elena"
+	for _, s := range composition.Section {
+		if s.Title == "Protocol operator" {
+			if s.Text == nil || !strings.Contains(s.Text.Div, want) {
+				t.Errorf("Protocol operator narrative = %+v, want %q", s.Text, want)
+			}
+			return
+		}
+	}
+	t.Fatal("Composition has no Protocol operator section")
+}
+
+func checkPatient(t *testing.T, bundle *Bundle, cnp string) {
+	t.Helper()
+	patients := resources[*Patient](bundle)
+	if len(patients) != 1 {
+		t.Fatalf("got %d Patient resources, want 1", len(patients))
+	}
+	for _, id := range patients[0].Identifier {
+		if id.System == CNPSystem && id.Value == cnp {
+			return
+		}
+	}
+	t.Errorf("Patient has no identifier %s|%s: %+v", CNPSystem, cnp, patients[0].Identifier)
+}
+
+func checkConditions(t *testing.T, bundle *Bundle, reportType domain.ReportType) {
+	t.Helper()
+	want := map[domain.ReportType][]string{
+		domain.ReportTypeDischargeSummary: {"I50.0", "I10"},
+		domain.ReportTypeTransferSummary:  {"I50.0", "I10"},
+		domain.ReportTypeOperativeNote:    {"K80.2"},
+	}[reportType]
+
+	codes := make(map[string]bool)
+	for _, c := range resources[*Condition](bundle) {
+		if c.Code == nil {
+			continue
+		}
+		for _, coding := range c.Code.Coding {
+			if coding.System == ICD10System {
+				codes[coding.Code] = true
+			}
+		}
+	}
+	for _, code := range want {
+		if !codes[code] {
+			t.Errorf("no Condition coded %s in ICD-10; got %v", code, codes)
+		}
+	}
+}
+
+func checkObservations(t *testing.T, bundle *Bundle, reportType domain.ReportType) {
+	t.Helper()
+	byCategory := make(map[string][]*Observation)
+	for _, o := range resources[*Observation](bundle) {
+		for _, category := range o.Category {
+			for _, coding := range category.Coding {
+				byCategory[coding.Code] = append(byCategory[coding.Code], o)
+			}
+		}
+	}
+
+	model := reportType.Content()
+	if model.HasSection("examination") {
+		want := map[string]bool{"85354-9": true, "8867-4": true, "8310-5": true, "59408-5": true}
+		for _, o := range byCategory["vital-signs"] {
+			delete(want, o.Code.Coding[0].Code)
+		}
+		if len(want) > 0 {
+			t.Errorf("missing vital sign observations %v", want)
+		}
+	} else if len(byCategory["vital-signs"]) > 0 {
+		t.Errorf("%s reports have no examination, got vital signs", reportType)
+	}
+
+	if model.HasSection("lab_results") {
+		labs := byCategory["laboratory"]
+		if len(labs) != 1 {
+			t.Fatalf("got %d laboratory observations, want 1", len(labs))
+		}
+		lab := labs[0]
+		if lab.ValueQuantity == nil || lab.ValueQuantity.Value != 11.2 {
+			t.Errorf("hemoglobin value = %+v", lab.ValueQuantity)
+		}
+		if len(lab.Interpretation) != 1 || lab.Interpretation[0].Coding[0].Code != "L" {
+			t.Errorf("hemoglobin below range is not interpreted low: %+v", lab.Interpretation)
+		}
+	}
+}
+
+// resources returns the bundle's resources of one type
+func resources[T any](bundle *Bundle) []T {
+	var found []T
+	for _, e := range bundle.Entry {
+		if r, ok := e.Resource.(T); ok {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+func TestValidateRejectsBrokenDocuments(t *testing.T) {
+	doc := testDocument(t, domain.ReportTypeDischargeSummary, true)
+
+	tests := []struct {
+		name   string
+		mutate func(b *Bundle)
+	}{
+		{"composition not first", func(b *Bundle) { b.Entry[0], b.Entry[1] = b.Entry[1], b.Entry[0] }},
+		{"no identifier", func(b *Bundle) { b.Identifier = nil }},
+		{"no timestamp", func(b *Bundle) { b.Timestamp = "" }},
+		{"dangling reference", func(b *Bundle) { b.Entry = b.Entry[:len(b.Entry)-1] }},
+		{"empty section", func(b *Bundle) {
+			b.Entry[0].Resource.(*Composition).Section[0] = CompositionSection{Title: "Gol"}
+		}},
+		{"unknown status", func(b *Bundle) { b.Entry[0].Resource.(*Composition).Status = "signed" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := DocumentBundle(doc)
+			tt.mutate(bundle)
+			if err := bundle.Validate(); err == nil {
+				t.Errorf("Validate accepted a bundle with %s", tt.name)
+			}
+		})
+	}
+}
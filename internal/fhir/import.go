@@ -0,0 +1,616 @@
+package fhir
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// Admission is the admission data read from a bundle sent by the hospital
+// information system, ready to prefill a draft of one report type
+type Admission struct {
+	ReportType      domain.ReportType
+	CNP             string
+	FirstName       string
+	LastName        string
+	AdmissionNumber string
+	// AdmittedAt is zero when the bundle has no encounter period
+	AdmittedAt time.Time
+	Location   domain.BedLocation
+	// Diagnoses are the initial diagnoses, the primary one first
+	Diagnoses []domain.ICD10Code
+	// Medications are the chronic medications taken before admission
+	Medications []domain.Medication
+	// Unmapped lists the elements of the bundle left out of the draft
+	Unmapped []Unmapped
+
+	// patientEntry and name locate the imported name in the bundle; name is
+	// -1 when the Patient has none
+	patientEntry int
+	name         int
+}
+
+// Unmapped is an element of an imported bundle that has no place in the
+// draft. Entry is the element's index in Bundle.entry; Path is relative to
+// the entry's resource. Elements that are not read at all have no reason.
+type Unmapped struct {
+	Entry  int    `json:"entry"`
+	Path   string `json:"path"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// structuralElements are present on every resource and carry no data
+var structuralElements = []string{"resourceType", "id", "meta", "text"}
+
+// Prefill writes the initial diagnoses and chronic medications into the
+// content of a new report. Patient and admission details come from the
+// registry once the patient is registered and admitted.
+func (a *Admission) Prefill(c *domain.ReportContent) {
+	model := a.ReportType.Content()
+	if len(a.Diagnoses) > 0 {
+		switch {
+		case model.HasSection("diagnosis"):
+			c.Diagnosis.PrimaryDiagnosis = a.Diagnoses[0]
+			c.Diagnosis.SecondaryDiagnoses = append([]domain.ICD10Code(nil), a.Diagnoses[1:]...)
+		case c.OperativeNote != nil:
+			c.OperativeNote.PreOpDiagnosis = a.Diagnoses[0]
+		}
+	}
+	if model.HasSection("medication_reconciliation") && len(a.Medications) > 0 {
+		c.MedicationReconciliation.PreAdmission = append([]domain.Medication(nil), a.Medications...)
+	}
+}
+
+// KeepRegisteredName reports the imported name as unmapped when it differs
+// from the name of the registered patient, which the draft keeps
+func (a *Admission) KeepRegisteredName(p *domain.Patient) {
+	if a.name < 0 || (a.FirstName == p.FirstName && a.LastName == p.LastName) {
+		return
+	}
+	a.Unmapped = append(a.Unmapped, Unmapped{
+		Entry:  a.patientEntry,
+		Path:   fmt.Sprintf("Patient.name[%d]", a.name),
+		Reason: "differs from the registered name, which is kept",
+	})
+	sortUnmapped(a.Unmapped)
+}
+
+// ReadAdmission reads the Patient, Encounter, Condition and
+// MedicationStatement resources of a bundle. The bundle must describe one
+// patient identified by CNP; other resources and elements are reported as
+// unmapped.
+func ReadAdmission(data []byte, reportType domain.ReportType) (*Admission, error) {
+	if !reportType.IsValid() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidReportType, reportType)
+	}
+
+	var bundle struct {
+		ResourceType string `json:"resourceType"`
+		Entry        []struct {
+			FullURL  string          `json:"fullUrl"`
+			Resource json.RawMessage `json:"resource"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return nil, fmt.Errorf("%w: unexpected %s in %s", domain.ErrInvalidFHIRBundle, typeErr.Value, typeErr.Field)
+		}
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: expected a Bundle object", domain.ErrInvalidFHIRBundle)
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidFHIRBundle, err)
+	}
+	if bundle.ResourceType != "Bundle" {
+		return nil, fmt.Errorf("%w: resourceType must be Bundle", domain.ErrInvalidFHIRBundle)
+	}
+
+	r := &admissionReader{admission: &Admission{ReportType: reportType}, now: time.Now()}
+	for i, e := range bundle.Entry {
+		var head struct {
+			ResourceType string `json:"resourceType"`
+			ID           string `json:"id"`
+		}
+		if len(e.Resource) == 0 {
+			r.unmapped(i, "", "entry without a resource")
+			continue
+		}
+		if err := json.Unmarshal(e.Resource, &head); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %v", domain.ErrInvalidFHIRBundle, i, err)
+		}
+		r.entries = append(r.entries, entry{index: i, fullURL: e.FullURL, resourceType: head.ResourceType, id: head.ID, raw: e.Resource})
+	}
+
+	if err := r.read(); err != nil {
+		return nil, err
+	}
+	r.place()
+
+	sortUnmapped(r.admission.Unmapped)
+	return r.admission, nil
+}
+
+// sortUnmapped orders unmapped elements by entry, keeping the order within
+// an entry
+func sortUnmapped(unmapped []Unmapped) {
+	sort.SliceStable(unmapped, func(i, j int) bool {
+		return unmapped[i].Entry < unmapped[j].Entry
+	})
+}
+
+type entry struct {
+	index        int
+	fullURL      string
+	resourceType string
+	id           string
+	raw          json.RawMessage
+}
+
+// refersTo reports whether a reference points to the entry
+func (e entry) refersTo(ref string) bool {
+	if ref == "" {
+		return false
+	}
+	return ref == e.fullURL || (e.id != "" && ref == e.resourceType+"/"+e.id)
+}
+
+// importedDiagnosis is a mapped condition with its rank on the encounter
+type importedDiagnosis struct {
+	entry int
+	code  domain.ICD10Code
+	rank  int
+}
+
+type importedMedication struct {
+	entry      int
+	medication domain.Medication
+}
+
+type admissionReader struct {
+	admission   *Admission
+	now         time.Time
+	entries     []entry
+	ranks       map[int]int
+	diagnoses   []importedDiagnosis
+	medications []importedMedication
+}
+
+func (r *admissionReader) unmapped(entry int, path, reason string) {
+	r.admission.Unmapped = append(r.admission.Unmapped, Unmapped{Entry: entry, Path: path, Reason: reason})
+}
+
+// rest reports the elements of a resource that the mapping did not read
+func (r *admissionReader) rest(e entry, read ...string) {
+	var elements map[string]json.RawMessage
+	if err := json.Unmarshal(e.raw, &elements); err != nil {
+		return
+	}
+	known := make(map[string]bool, len(read)+len(structuralElements))
+	for _, name := range append(read, structuralElements...) {
+		known[name] = true
+	}
+
+	var names []string
+	for name := range elements {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.unmapped(e.index, e.resourceType+"."+name, "")
+	}
+}
+
+func (r *admissionReader) decode(e entry, resource interface{}) error {
+	if err := json.Unmarshal(e.raw, resource); err != nil {
+		return fmt.Errorf("%w: entry %d (%s): %v", domain.ErrInvalidFHIRBundle, e.index, e.resourceType, err)
+	}
+	return nil
+}
+
+func (r *admissionReader) read() error {
+	var patients, encounters []entry
+	for _, e := range r.entries {
+		switch e.resourceType {
+		case "Patient":
+			patients = append(patients, e)
+		case "Encounter":
+			encounters = append(encounters, e)
+		}
+	}
+	if len(patients) != 1 {
+		return fmt.Errorf("%w: the bundle must contain one Patient, found %d", domain.ErrInvalidFHIRBundle, len(patients))
+	}
+	if err := r.patient(patients[0]); err != nil {
+		return err
+	}
+
+	// Ranks come from the encounter and order the conditions read after it
+	for i, e := range encounters {
+		if i > 0 {
+			r.unmapped(e.index, "Encounter", "only one encounter is imported")
+			continue
+		}
+		if err := r.encounter(e); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range r.entries {
+		var err error
+		switch e.resourceType {
+		case "Patient", "Encounter":
+		case "Condition":
+			err = r.condition(e)
+		case "MedicationStatement":
+			err = r.medicationStatement(e)
+		default:
+			r.unmapped(e.index, e.resourceType, "resource type is not imported")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Ranked diagnoses come first, primary before secondary
+	sort.SliceStable(r.diagnoses, func(i, j int) bool {
+		ri, rj := r.diagnoses[i].rank, r.diagnoses[j].rank
+		return ri > 0 && (rj == 0 || ri < rj)
+	})
+	return nil
+}
+
+func (r *admissionReader) patient(e entry) error {
+	var p Patient
+	if err := r.decode(e, &p); err != nil {
+		return err
+	}
+
+	for i, id := range p.Identifier {
+		if id.System == CNPSystem && r.admission.CNP == "" {
+			r.admission.CNP = strings.TrimSpace(id.Value)
+			continue
+		}
+		r.unmapped(e.index, fmt.Sprintf("Patient.identifier[%d]", i), "only the CNP is imported")
+	}
+	if r.admission.CNP == "" {
+		return fmt.Errorf("%w: the Patient has no identifier with system %s", domain.ErrInvalidFHIRBundle, CNPSystem)
+	}
+
+	name := -1
+	for i, n := range p.Name {
+		if name < 0 || (n.Use == "official" && p.Name[name].Use != "official") {
+			name = i
+		}
+	}
+	r.admission.patientEntry, r.admission.name = e.index, name
+	for i, n := range p.Name {
+		if i != name {
+			r.unmapped(e.index, fmt.Sprintf("Patient.name[%d]", i), "only the official name is imported")
+			continue
+		}
+		r.admission.FirstName = strings.Join(n.Given, " ")
+		r.admission.LastName = n.Family
+		if len(n.Prefix) > 0 {
+			r.unmapped(e.index, fmt.Sprintf("Patient.name[%d].prefix", i), "")
+		}
+	}
+
+	// Sex and birth date are derived from the CNP
+	if p.Gender != "" && p.Gender != gender(domain.SexFromCNP(r.admission.CNP)) {
+		r.unmapped(e.index, "Patient.gender", "does not match the CNP, which the sex is read from")
+	}
+	if p.BirthDate != "" {
+		if birthDate, ok := domain.BirthDateFromCNP(r.admission.CNP); !ok || date(birthDate) != p.BirthDate {
+			r.unmapped(e.index, "Patient.birthDate", "does not match the CNP, which the birth date is read from")
+		}
+	}
+
+	r.rest(e, "identifier", "name", "gender", "birthDate")
+	return nil
+}
+
+// activeEncounterStatuses are the statuses of an admission in progress
+var activeEncounterStatuses = codes("planned", "arrived", "triaged", "in-progress", "onleave")
+
+func (r *admissionReader) encounter(e entry) error {
+	var enc Encounter
+	if err := r.decode(e, &enc); err != nil {
+		return err
+	}
+
+	for i, id := range enc.Identifier {
+		if i == 0 && id.Value != "" {
+			r.admission.AdmissionNumber = strings.TrimSpace(id.Value)
+			continue
+		}
+		r.unmapped(e.index, fmt.Sprintf("Encounter.identifier[%d]", i), "only the admission number is imported")
+	}
+
+	if enc.Status != "" && !activeEncounterStatuses[enc.Status] {
+		r.unmapped(e.index, "Encounter.status", "the draft is linked to the patient's active admission")
+	}
+
+	if enc.Period != nil {
+		if enc.Period.Start != "" {
+			start, err := parseDateTime(enc.Period.Start)
+			if err != nil {
+				return fmt.Errorf("%w: Encounter.period.start: %v", domain.ErrInvalidFHIRBundle, err)
+			}
+			r.admission.AdmittedAt = start
+		}
+		if enc.Period.End != "" {
+			r.unmapped(e.index, "Encounter.period.end", "the discharge is recorded when the patient is discharged")
+		}
+	}
+
+	// The current location is the active one, or the last one listed
+	current := -1
+	for i, l := range enc.Location {
+		if current < 0 || l.Status == "active" || enc.Location[current].Status != "active" {
+			current = i
+		}
+	}
+	for i, l := range enc.Location {
+		path := fmt.Sprintf("Encounter.location[%d]", i)
+		switch {
+		case i != current:
+			r.unmapped(e.index, path, "only the current location is imported")
+		case strings.TrimSpace(l.Location.Display) == "":
+			r.unmapped(e.index, path, "location without a display name")
+		default:
+			r.admission.Location = parseLocation(l.Location.Display)
+		}
+	}
+
+	r.ranks = make(map[int]int)
+	for i, d := range enc.Diagnosis {
+		found := false
+		for _, c := range r.entries {
+			if c.resourceType == "Condition" && c.refersTo(d.Condition.Reference) {
+				r.ranks[c.index] = d.Rank
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.unmapped(e.index, fmt.Sprintf("Encounter.diagnosis[%d]", i), "the condition is not in the bundle")
+		}
+	}
+
+	r.rest(e, "identifier", "status", "class", "subject", "period", "location", "diagnosis")
+	return nil
+}
+
+// parseLocation reads a location as exported: "Cardiologie, salon 12, pat 3"
+func parseLocation(display string) domain.BedLocation {
+	parts := strings.Split(display, ",")
+	location := domain.BedLocation{Department: strings.TrimSpace(parts[0])}
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "salon "):
+			location.Ward = strings.TrimSpace(strings.TrimPrefix(part, "salon "))
+		case strings.HasPrefix(part, "pat "):
+			location.Bed = strings.TrimSpace(strings.TrimPrefix(part, "pat "))
+		default:
+			location.Department += ", " + part
+		}
+	}
+	return location
+}
+
+func (r *admissionReader) condition(e entry) error {
+	var c Condition
+	if err := r.decode(e, &c); err != nil {
+		return err
+	}
+
+	if status := conceptCode(c.ClinicalStatus); status != "" && status != "active" && status != "recurrence" && status != "relapse" {
+		r.unmapped(e.index, "Condition", "the condition is not active")
+		return nil
+	}
+	if status := conceptCode(c.VerificationStatus); status == "refuted" || status == "entered-in-error" {
+		r.unmapped(e.index, "Condition", "the condition is "+status)
+		return nil
+	}
+
+	icd10 := -1
+	if c.Code != nil {
+		for i, coding := range c.Code.Coding {
+			if coding.System == ICD10System && coding.Code != "" {
+				icd10 = i
+				break
+			}
+		}
+	}
+	if icd10 < 0 {
+		r.unmapped(e.index, "Condition", "the condition has no ICD-10 code")
+		return nil
+	}
+	for i := range c.Code.Coding {
+		if i != icd10 {
+			r.unmapped(e.index, fmt.Sprintf("Condition.code.coding[%d]", i), "only ICD-10 codes are imported")
+		}
+	}
+
+	coding := c.Code.Coding[icd10]
+	code := domain.ICD10Code{Code: strings.TrimSpace(coding.Code), Description: coding.Display}
+	if code.Description == "" {
+		code.Description = c.Code.Text
+	}
+	r.diagnoses = append(r.diagnoses, importedDiagnosis{entry: e.index, code: code, rank: r.ranks[e.index]})
+	r.rest(e, "clinicalStatus", "verificationStatus", "category", "code", "subject", "encounter")
+	return nil
+}
+
+// chronicMedicationStatuses are the statuses of a medication still taken
+var chronicMedicationStatuses = codes("active", "intended", "on-hold", "unknown")
+
+func (r *admissionReader) medicationStatement(e entry) error {
+	var s MedicationStatement
+	if err := r.decode(e, &s); err != nil {
+		return err
+	}
+
+	if !chronicMedicationStatuses[s.Status] {
+		r.unmapped(e.index, "MedicationStatement", "the medication is not taken")
+		return nil
+	}
+	if conceptCode(s.Category) == "inpatient" {
+		r.unmapped(e.index, "MedicationStatement", "inpatient medication is not a chronic medication")
+		return nil
+	}
+
+	var m domain.Medication
+	switch {
+	case s.MedicationCodeableConcept != nil:
+		m.Name = s.MedicationCodeableConcept.Text
+		for i, coding := range s.MedicationCodeableConcept.Coding {
+			if m.Name == "" {
+				m.Name = coding.Display
+			}
+			r.unmapped(e.index, fmt.Sprintf("MedicationStatement.medicationCodeableConcept.coding[%d]", i), "only the medication name is imported")
+		}
+	case s.MedicationReference != nil:
+		m.Name = s.MedicationReference.Display
+	}
+	if strings.TrimSpace(m.Name) == "" {
+		r.unmapped(e.index, "MedicationStatement", "the medication has no name")
+		return nil
+	}
+
+	if s.EffectivePeriod != nil {
+		if s.EffectivePeriod.Start != "" {
+			start, err := parseDateTime(s.EffectivePeriod.Start)
+			if err != nil {
+				return fmt.Errorf("%w: entry %d: MedicationStatement.effectivePeriod.start: %v", domain.ErrInvalidFHIRBundle, e.index, err)
+			}
+			m.StartDate = start
+		}
+		if s.EffectivePeriod.End != "" {
+			end, err := parseDateTime(s.EffectivePeriod.End)
+			if err != nil {
+				return fmt.Errorf("%w: entry %d: MedicationStatement.effectivePeriod.end: %v", domain.ErrInvalidFHIRBundle, e.index, err)
+			}
+			if end.Before(r.now) {
+				r.unmapped(e.index, "MedicationStatement", "the course has ended")
+				return nil
+			}
+			m.EndDate = &end
+		}
+	}
+
+	if err := r.dosage(e, s.Dosage, &m); err != nil {
+		return err
+	}
+
+	r.medications = append(r.medications, importedMedication{entry: e.index, medication: m})
+	r.rest(e, "status", "category", "medicationCodeableConcept", "medicationReference", "subject", "context", "effectivePeriod", "dosage")
+	return nil
+}
+
+// dosage reads the first dosage as text; the sig is parsed from it when the
+// report is saved
+func (r *admissionReader) dosage(e entry, dosages []Dosage, m *domain.Medication) error {
+	if len(dosages) == 0 {
+		return nil
+	}
+	for i := range dosages[1:] {
+		r.unmapped(e.index, fmt.Sprintf("MedicationStatement.dosage[%d]", i+1), "only the first dosage is imported")
+	}
+
+	d := dosages[0]
+	m.Dosage = strings.TrimSpace(d.Text)
+	read := []string{"text", "route"}
+	if m.Dosage == "" && len(d.DoseAndRate) > 0 && d.DoseAndRate[0].DoseQuantity != nil {
+		q := d.DoseAndRate[0].DoseQuantity
+		m.Dosage = strings.TrimSpace(formatValue(q.Value) + " " + q.Unit)
+		read = append(read, "doseAndRate")
+	}
+	if d.Route != nil {
+		m.Route = d.Route.Text
+		if m.Route == "" && len(d.Route.Coding) > 0 {
+			m.Route = d.Route.Coding[0].Display
+		}
+	}
+
+	var elements map[string]json.RawMessage
+	var raw struct {
+		Dosage []json.RawMessage `json:"dosage"`
+	}
+	if err := json.Unmarshal(e.raw, &raw); err != nil || len(raw.Dosage) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw.Dosage[0], &elements); err != nil {
+		return nil
+	}
+	for _, name := range read {
+		delete(elements, name)
+	}
+	var names []string
+	for name := range elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.unmapped(e.index, "MedicationStatement.dosage[0]."+name, "the dosage is imported as text")
+	}
+	return nil
+}
+
+// place keeps the diagnoses and medications the report type has room for
+func (r *admissionReader) place() {
+	a := r.admission
+	model := a.ReportType.Content()
+
+	for i, d := range r.diagnoses {
+		switch {
+		case model.HasSection("diagnosis"):
+		case model.HasSection("operative_note") && i == 0:
+		case model.HasSection("operative_note"):
+			r.unmapped(d.entry, "Condition", "operative notes record one preoperative diagnosis")
+			continue
+		default:
+			r.unmapped(d.entry, "Condition", fmt.Sprintf("%s reports have no diagnosis", a.ReportType))
+			continue
+		}
+		a.Diagnoses = append(a.Diagnoses, d.code)
+	}
+
+	for _, m := range r.medications {
+		if !model.HasSection("medication_reconciliation") {
+			r.unmapped(m.entry, "MedicationStatement", fmt.Sprintf("%s reports have no medication reconciliation", a.ReportType))
+			continue
+		}
+		a.Medications = append(a.Medications, m.medication)
+	}
+}
+
+// conceptCode is the code of the first coding of a concept
+func conceptCode(c *CodeableConcept) string {
+	if c == nil || len(c.Coding) == 0 {
+		return ""
+	}
+	return c.Coding[0].Code
+}
+
+// parseDateTime reads a FHIR date or dateTime
+func parseDateTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+func formatValue(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", v), "0"), ".")
+}
@@ -0,0 +1,286 @@
+package fhir
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// admissionBundle is an admission sent by a hospital information system.
+// The encounter ranks the conditions against their order in the bundle.
+const admissionBundle = `{
+  "resourceType": "Bundle",
+  "type": "collection",
+  "entry": [
+    {"fullUrl": "urn:uuid:p1", "resource": {
+      "resourceType": "Patient", "id": "p1",
+      "identifier": [{"system": "urn:his:mrn", "value": "12"}, {"system": "urn:ro:cnp", "value": " 1740307123456 "}],
+      "name": [{"use": "usual", "given": ["Ionuț"]}, {"use": "official", "family": "Popescu", "given": ["Ion", "Mihai"]}],
+      "gender": "male",
+      "birthDate": "1974-03-07",
+      "telecom": [{"system": "phone", "value": "0722000000"}]
+    }},
+    {"fullUrl": "urn:uuid:e1", "resource": {
+      "resourceType": "Encounter", "id": "e1",
+      "identifier": [{"value": "A-2026-0042"}],
+      "status": "in-progress",
+      "period": {"start": "2026-03-02T08:30:00Z"},
+      "location": [{"location": {"display": "Cardiologie, salon 4, pat 2"}, "status": "active"}],
+      "diagnosis": [
+        {"condition": {"reference": "urn:uuid:c3"}, "rank": 2},
+        {"condition": {"reference": "Condition/c2"}, "rank": 1},
+        {"condition": {"reference": "Condition/missing"}}
+      ]
+    }},
+    {"fullUrl": "urn:uuid:c1", "resource": {
+      "resourceType": "Condition", "id": "c1",
+      "clinicalStatus": {"coding": [{"code": "active"}]},
+      "code": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-10", "code": "I10", "display": "Hipertensiune arterială esențială"}]}
+    }},
+    {"fullUrl": "urn:uuid:c2", "resource": {
+      "resourceType": "Condition", "id": "c2",
+      "code": {"coding": [
+        {"system": "http://hl7.org/fhir/sid/icd-10", "code": "I50.0"},
+        {"system": "http://snomed.info/sct", "code": "42343007"}
+      ], "text": "Insuficiență cardiacă congestivă"}
+    }},
+    {"fullUrl": "urn:uuid:c3", "resource": {
+      "resourceType": "Condition", "id": "c3",
+      "code": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-10", "code": "E11.9", "display": "Diabet zaharat tip 2"}]}
+    }},
+    {"fullUrl": "urn:uuid:c4", "resource": {
+      "resourceType": "Condition", "id": "c4",
+      "clinicalStatus": {"coding": [{"code": "resolved"}]},
+      "code": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-10", "code": "J18.9"}]}
+    }},
+    {"fullUrl": "urn:uuid:c5", "resource": {
+      "resourceType": "Condition", "id": "c5",
+      "verificationStatus": {"coding": [{"code": "refuted"}]},
+      "code": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-10", "code": "I21.9"}]}
+    }},
+    {"fullUrl": "urn:uuid:c6", "resource": {
+      "resourceType": "Condition", "id": "c6",
+      "code": {"coding": [{"system": "http://snomed.info/sct", "code": "73211009"}]}
+    }},
+    {"fullUrl": "urn:uuid:m1", "resource": {
+      "resourceType": "MedicationStatement", "id": "m1",
+      "status": "active",
+      "medicationCodeableConcept": {"text": "Bisoprolol 5 mg"},
+      "dosage": [{"text": "1 cp/zi", "route": {"text": "oral"}}]
+    }},
+    {"fullUrl": "urn:uuid:m2", "resource": {
+      "resourceType": "MedicationStatement", "id": "m2",
+      "status": "completed",
+      "medicationCodeableConcept": {"text": "Amoxicilină 500 mg"}
+    }},
+    {"fullUrl": "urn:uuid:m3", "resource": {
+      "resourceType": "MedicationStatement", "id": "m3",
+      "status": "active",
+      "medicationCodeableConcept": {"text": "Prednison 5 mg"},
+      "effectivePeriod": {"start": "2019-11-01", "end": "2020-01-01"}
+    }},
+    {"fullUrl": "urn:uuid:o1", "resource": {"resourceType": "Observation", "id": "o1", "status": "final"}}
+  ]
+}`
+
+func TestReadAdmission(t *testing.T) {
+	a, err := ReadAdmission([]byte(admissionBundle), domain.ReportTypeDischargeSummary)
+	if err != nil {
+		t.Fatalf("ReadAdmission: %v", err)
+	}
+
+	if a.CNP != "1740307123456" || a.FirstName != "Ion Mihai" || a.LastName != "Popescu" {
+		t.Errorf("patient = %q %q %q, want the CNP and the official name", a.CNP, a.FirstName, a.LastName)
+	}
+	if a.AdmissionNumber != "A-2026-0042" {
+		t.Errorf("AdmissionNumber = %q, want A-2026-0042", a.AdmissionNumber)
+	}
+	if want := time.Date(2026, time.March, 2, 8, 30, 0, 0, time.UTC); !a.AdmittedAt.Equal(want) {
+		t.Errorf("AdmittedAt = %v, want %v", a.AdmittedAt, want)
+	}
+	if want := (domain.BedLocation{Department: "Cardiologie", Ward: "4", Bed: "2"}); a.Location != want {
+		t.Errorf("Location = %+v, want %+v", a.Location, want)
+	}
+
+	// Ranked conditions first, in rank order, then the unranked one
+	wantDiagnoses := []domain.ICD10Code{
+		{Code: "I50.0", Description: "Insuficiență cardiacă congestivă"},
+		{Code: "E11.9", Description: "Diabet zaharat tip 2"},
+		{Code: "I10", Description: "Hipertensiune arterială esențială"},
+	}
+	if !reflect.DeepEqual(a.Diagnoses, wantDiagnoses) {
+		t.Errorf("Diagnoses = %+v, want %+v", a.Diagnoses, wantDiagnoses)
+	}
+
+	if len(a.Medications) != 1 {
+		t.Fatalf("Medications = %+v, want only the medication still taken", a.Medications)
+	}
+	if m := a.Medications[0]; m.Name != "Bisoprolol 5 mg" || m.Dosage != "1 cp/zi" || m.Route != "oral" {
+		t.Errorf("Medications[0] = %+v", m)
+	}
+
+	wantUnmapped := []Unmapped{
+		{Entry: 0, Path: "Patient.identifier[0]", Reason: "only the CNP is imported"},
+		{Entry: 0, Path: "Patient.name[0]", Reason: "only the official name is imported"},
+		{Entry: 0, Path: "Patient.telecom"},
+		{Entry: 1, Path: "Encounter.diagnosis[2]", Reason: "the condition is not in the bundle"},
+		{Entry: 3, Path: "Condition.code.coding[1]", Reason: "only ICD-10 codes are imported"},
+		{Entry: 5, Path: "Condition", Reason: "the condition is not active"},
+		{Entry: 6, Path: "Condition", Reason: "the condition is refuted"},
+		{Entry: 7, Path: "Condition", Reason: "the condition has no ICD-10 code"},
+		{Entry: 9, Path: "MedicationStatement", Reason: "the medication is not taken"},
+		{Entry: 10, Path: "MedicationStatement", Reason: "the course has ended"},
+		{Entry: 11, Path: "Observation", Reason: "resource type is not imported"},
+	}
+	if !reflect.DeepEqual(a.Unmapped, wantUnmapped) {
+		t.Errorf("Unmapped =\n%+v\nwant\n%+v", a.Unmapped, wantUnmapped)
+	}
+}
+
+func TestReadAdmissionPlacesByReportType(t *testing.T) {
+	tests := []struct {
+		reportType      domain.ReportType
+		wantDiagnoses   []string
+		wantMedications int
+		// wantUnmapped are the entries left out on top of the discharge summary's
+		wantUnmapped []Unmapped
+	}{
+		{domain.ReportTypeDischargeSummary, []string{"I50.0", "E11.9", "I10"}, 1, nil},
+		{domain.ReportTypeTransferSummary, []string{"I50.0", "E11.9", "I10"}, 1, nil},
+		{domain.ReportTypeOperativeNote, []string{"I50.0"}, 0, []Unmapped{
+			{Entry: 2, Path: "Condition", Reason: "operative notes record one preoperative diagnosis"},
+			{Entry: 4, Path: "Condition", Reason: "operative notes record one preoperative diagnosis"},
+			{Entry: 8, Path: "MedicationStatement", Reason: string(domain.ReportTypeOperativeNote) + " reports have no medication reconciliation"},
+		}},
+	}
+
+	discharge, err := ReadAdmission([]byte(admissionBundle), domain.ReportTypeDischargeSummary)
+	if err != nil {
+		t.Fatalf("ReadAdmission: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.reportType), func(t *testing.T) {
+			a, err := ReadAdmission([]byte(admissionBundle), tt.reportType)
+			if err != nil {
+				t.Fatalf("ReadAdmission: %v", err)
+			}
+
+			var codes []string
+			for _, d := range a.Diagnoses {
+				codes = append(codes, d.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantDiagnoses) {
+				t.Errorf("Diagnoses = %v, want %v", codes, tt.wantDiagnoses)
+			}
+			if len(a.Medications) != tt.wantMedications {
+				t.Errorf("got %d medications, want %d", len(a.Medications), tt.wantMedications)
+			}
+
+			var extra []Unmapped
+			for _, u := range a.Unmapped {
+				if !containsUnmapped(discharge.Unmapped, u) {
+					extra = append(extra, u)
+				}
+			}
+			if !reflect.DeepEqual(extra, tt.wantUnmapped) {
+				t.Errorf("unmapped beyond the discharge summary = %+v, want %+v", extra, tt.wantUnmapped)
+			}
+
+			// The draft takes what the report type has room for
+			var content domain.ReportContent
+			content.Normalize(tt.reportType)
+			a.Prefill(&content)
+			if tt.reportType == domain.ReportTypeOperativeNote {
+				if content.OperativeNote == nil || content.OperativeNote.PreOpDiagnosis.Code != "I50.0" {
+					t.Errorf("operative note = %+v, want the primary diagnosis as preoperative diagnosis", content.OperativeNote)
+				}
+				return
+			}
+			if content.Diagnosis.PrimaryDiagnosis.Code != "I50.0" || len(content.Diagnosis.SecondaryDiagnoses) != 2 {
+				t.Errorf("diagnosis = %+v", content.Diagnosis)
+			}
+			if len(content.MedicationReconciliation.PreAdmission) != 1 {
+				t.Errorf("pre-admission medications = %+v", content.MedicationReconciliation.PreAdmission)
+			}
+		})
+	}
+}
+
+func containsUnmapped(list []Unmapped, u Unmapped) bool {
+	for _, v := range list {
+		if v == u {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReadAdmissionRejectsInvalidBundles(t *testing.T) {
+	tests := []struct {
+		name       string
+		bundle     string
+		reportType domain.ReportType
+		want       error
+	}{
+		{"invalid report type", admissionBundle, "consult_note", domain.ErrInvalidReportType},
+		{"not JSON", "<Bundle/>", domain.ReportTypeDischargeSummary, domain.ErrInvalidFHIRBundle},
+		{"not an object", `[]`, domain.ReportTypeDischargeSummary, domain.ErrInvalidFHIRBundle},
+		{"not a bundle", `{"resourceType": "Patient"}`, domain.ReportTypeDischargeSummary, domain.ErrInvalidFHIRBundle},
+		{"no patient", `{"resourceType": "Bundle", "entry": [{"resource": {"resourceType": "Encounter"}}]}`,
+			domain.ReportTypeDischargeSummary, domain.ErrInvalidFHIRBundle},
+		{"two patients", `{"resourceType": "Bundle", "entry": [
+			{"resource": {"resourceType": "Patient", "identifier": [{"system": "urn:ro:cnp", "value": "1740307123456"}]}},
+			{"resource": {"resourceType": "Patient", "identifier": [{"system": "urn:ro:cnp", "value": "2740307123456"}]}}]}`,
+			domain.ReportTypeDischargeSummary, domain.ErrInvalidFHIRBundle},
+		{"patient without CNP", `{"resourceType": "Bundle", "entry": [
+			{"resource": {"resourceType": "Patient", "identifier": [{"system": "urn:his:mrn", "value": "12"}]}}]}`,
+			domain.ReportTypeDischargeSummary, domain.ErrInvalidFHIRBundle},
+		{"invalid admission date", strings.Replace(admissionBundle, "2026-03-02T08:30:00Z", "ieri", 1),
+			domain.ReportTypeDischargeSummary, domain.ErrInvalidFHIRBundle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ReadAdmission([]byte(tt.bundle), tt.reportType)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ReadAdmission = %+v, %v; want %v", a, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepRegisteredName(t *testing.T) {
+	tests := []struct {
+		name      string
+		patient   domain.Patient
+		wantEntry bool
+	}{
+		{"same name", domain.Patient{FirstName: "Ion Mihai", LastName: "Popescu"}, false},
+		{"other first name", domain.Patient{FirstName: "Ion", LastName: "Popescu"}, true},
+		{"other last name", domain.Patient{FirstName: "Ion Mihai", LastName: "Popescu-Ionescu"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ReadAdmission([]byte(admissionBundle), domain.ReportTypeDischargeSummary)
+			if err != nil {
+				t.Fatalf("ReadAdmission: %v", err)
+			}
+			before := len(a.Unmapped)
+			a.KeepRegisteredName(&tt.patient)
+
+			want := Unmapped{Entry: 0, Path: "Patient.name[1]", Reason: "differs from the registered name, which is kept"}
+			added := len(a.Unmapped) - before
+			if got := containsUnmapped(a.Unmapped, want); got != tt.wantEntry || (added == 1) != tt.wantEntry {
+				t.Errorf("Unmapped = %+v, want the name listed: %v", a.Unmapped, tt.wantEntry)
+			}
+			if a.Unmapped[len(a.Unmapped)-1].Entry != 11 {
+				t.Errorf("Unmapped = %+v, want it ordered by entry", a.Unmapped)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+// Package fhir maps reports to and from HL7 FHIR R4 resources.
+//
+// Only the elements the mapping uses are modelled; see
+// https://hl7.org/fhir/R4/resourcelist.html for the full resources.
+package fhir
+
+// Code systems and identifier systems used by the mapping
+const (
+	LOINCSystem = "http://loinc.org"
+	UCUMSystem  = "http://unitsofmeasure.org"
+	ICD10System = "http://hl7.org/fhir/sid/icd-10"
+	// CNPSystem identifies the Romanian personal numeric code (CNP)
+	CNPSystem = "urn:ro:cnp"
+	// CUISystem identifies the Romanian fiscal code of a hospital (CUI)
+	CUISystem = "urn:ro:cui"
+	// ACHISystem identifies procedure codes of the ACHI catalog
+	ACHISystem = "urn:achi"
+
+	ConditionCategorySystem     = "http://terminology.hl7.org/CodeSystem/condition-category"
+	ConditionClinicalSystem     = "http://terminology.hl7.org/CodeSystem/condition-clinical"
+	ConditionVerStatusSystem    = "http://terminology.hl7.org/CodeSystem/condition-ver-status"
+	ObservationCategorySystem   = "http://terminology.hl7.org/CodeSystem/observation-category"
+	InterpretationSystem        = "http://terminology.hl7.org/CodeSystem/v3-ObservationInterpretation"
+	ActCodeSystem               = "http://terminology.hl7.org/CodeSystem/v3-ActCode"
+	MedicationStatementCategory = "http://terminology.hl7.org/CodeSystem/medication-statement-category"
+)
+
+// Bundle is a collection of resources; reports are exported as documents
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	ID           string        `json:"id,omitempty"`
+	Identifier   *Identifier   `json:"identifier,omitempty"`
+	Type         string        `json:"type"`
+	Timestamp    string        `json:"timestamp,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+// BundleEntry holds one of the resource types of this package
+type BundleEntry struct {
+	FullURL  string      `json:"fullUrl,omitempty"`
+	Resource interface{} `json:"resource,omitempty"`
+}
+
+type Composition struct {
+	ResourceType string               `json:"resourceType"`
+	ID           string               `json:"id,omitempty"`
+	Identifier   *Identifier          `json:"identifier,omitempty"`
+	Status       string               `json:"status"`
+	Type         CodeableConcept      `json:"type"`
+	Subject      *Reference           `json:"subject,omitempty"`
+	Encounter    *Reference           `json:"encounter,omitempty"`
+	Date         string               `json:"date"`
+	Author       []Reference          `json:"author"`
+	Title        string               `json:"title"`
+	Attester     []Attester           `json:"attester,omitempty"`
+	Custodian    *Reference           `json:"custodian,omitempty"`
+	Section      []CompositionSection `json:"section,omitempty"`
+}
+
+// Attester records the legal signature of a composition
+type Attester struct {
+	Mode  string     `json:"mode"`
+	Time  string     `json:"time,omitempty"`
+	Party *Reference `json:"party,omitempty"`
+}
+
+type CompositionSection struct {
+	Title string           `json:"title,omitempty"`
+	Code  *CodeableConcept `json:"code,omitempty"`
+	Text  *Narrative       `json:"text,omitempty"`
+	Entry []Reference      `json:"entry,omitempty"`
+}
+
+type Patient struct {
+	ResourceType string       `json:"resourceType"`
+	ID           string       `json:"id,omitempty"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
+	Name         []HumanName  `json:"name,omitempty"`
+	Gender       string       `json:"gender,omitempty"`
+	BirthDate    string       `json:"birthDate,omitempty"`
+}
+
+type Encounter struct {
+	ResourceType    string               `json:"resourceType"`
+	ID              string               `json:"id,omitempty"`
+	Identifier      []Identifier         `json:"identifier,omitempty"`
+	Status          string               `json:"status"`
+	Class           Coding               `json:"class"`
+	Subject         *Reference           `json:"subject,omitempty"`
+	Period          *Period              `json:"period,omitempty"`
+	Diagnosis       []EncounterDiagnosis `json:"diagnosis,omitempty"`
+	Location        []EncounterLocation  `json:"location,omitempty"`
+	ServiceProvider *Reference           `json:"serviceProvider,omitempty"`
+}
+
+// EncounterDiagnosis ranks a condition of the encounter; rank 1 is primary
+type EncounterDiagnosis struct {
+	Condition Reference `json:"condition"`
+	Rank      int       `json:"rank,omitempty"`
+}
+
+type EncounterLocation struct {
+	Location Reference `json:"location"`
+	Status   string    `json:"status,omitempty"`
+}
+
+type Condition struct {
+	ResourceType       string            `json:"resourceType"`
+	ID                 string            `json:"id,omitempty"`
+	ClinicalStatus     *CodeableConcept  `json:"clinicalStatus,omitempty"`
+	VerificationStatus *CodeableConcept  `json:"verificationStatus,omitempty"`
+	Category           []CodeableConcept `json:"category,omitempty"`
+	Code               *CodeableConcept  `json:"code,omitempty"`
+	Subject            Reference         `json:"subject"`
+	Encounter          *Reference        `json:"encounter,omitempty"`
+}
+
+type MedicationStatement struct {
+	ResourceType              string           `json:"resourceType"`
+	ID                        string           `json:"id,omitempty"`
+	Status                    string           `json:"status"`
+	Category                  *CodeableConcept `json:"category,omitempty"`
+	MedicationCodeableConcept *CodeableConcept `json:"medicationCodeableConcept,omitempty"`
+	MedicationReference       *Reference       `json:"medicationReference,omitempty"`
+	Subject                   Reference        `json:"subject"`
+	Context                   *Reference       `json:"context,omitempty"`
+	EffectivePeriod           *Period          `json:"effectivePeriod,omitempty"`
+	Dosage                    []Dosage         `json:"dosage,omitempty"`
+}
+
+type Dosage struct {
+	Text        string           `json:"text,omitempty"`
+	AsNeeded    bool             `json:"asNeededBoolean,omitempty"`
+	Route       *CodeableConcept `json:"route,omitempty"`
+	DoseAndRate []DoseAndRate    `json:"doseAndRate,omitempty"`
+}
+
+type DoseAndRate struct {
+	DoseQuantity *Quantity `json:"doseQuantity,omitempty"`
+}
+
+type Observation struct {
+	ResourceType      string                 `json:"resourceType"`
+	ID                string                 `json:"id,omitempty"`
+	Status            string                 `json:"status"`
+	Category          []CodeableConcept      `json:"category,omitempty"`
+	Code              CodeableConcept        `json:"code"`
+	Subject           *Reference             `json:"subject,omitempty"`
+	Encounter         *Reference             `json:"encounter,omitempty"`
+	EffectiveDateTime string                 `json:"effectiveDateTime,omitempty"`
+	ValueQuantity     *Quantity              `json:"valueQuantity,omitempty"`
+	ValueString       string                 `json:"valueString,omitempty"`
+	Interpretation    []CodeableConcept      `json:"interpretation,omitempty"`
+	ReferenceRange    []ReferenceRange       `json:"referenceRange,omitempty"`
+	Component         []ObservationComponent `json:"component,omitempty"`
+}
+
+type ReferenceRange struct {
+	Low  *Quantity `json:"low,omitempty"`
+	High *Quantity `json:"high,omitempty"`
+}
+
+type ObservationComponent struct {
+	Code          CodeableConcept `json:"code"`
+	ValueQuantity *Quantity       `json:"valueQuantity,omitempty"`
+}
+
+type Procedure struct {
+	ResourceType      string           `json:"resourceType"`
+	ID                string           `json:"id,omitempty"`
+	Status            string           `json:"status"`
+	Code              *CodeableConcept `json:"code,omitempty"`
+	Subject           Reference        `json:"subject"`
+	Encounter         *Reference       `json:"encounter,omitempty"`
+	PerformedDateTime string           `json:"performedDateTime,omitempty"`
+	Performer         []Performer      `json:"performer,omitempty"`
+	Note              []Annotation     `json:"note,omitempty"`
+}
+
+type Performer struct {
+	Actor Reference `json:"actor"`
+}
+
+type Practitioner struct {
+	ResourceType string      `json:"resourceType"`
+	ID           string      `json:"id,omitempty"`
+	Name         []HumanName `json:"name,omitempty"`
+}
+
+type Organization struct {
+	ResourceType string       `json:"resourceType"`
+	ID           string       `json:"id,omitempty"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
+	Name         string       `json:"name,omitempty"`
+	Address      []Address    `json:"address,omitempty"`
+}
+
+// Data types
+
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+type HumanName struct {
+	Use    string   `json:"use,omitempty"`
+	Family string   `json:"family,omitempty"`
+	Given  []string `json:"given,omitempty"`
+	Prefix []string `json:"prefix,omitempty"`
+}
+
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Reference points to another entry of the bundle by its full URL, or only
+// describes the target when Display is set alone
+type Reference struct {
+	Reference string `json:"reference,omitempty"`
+	Display   string `json:"display,omitempty"`
+}
+
+type Period struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit,omitempty"`
+	System string  `json:"system,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+type Narrative struct {
+	Status string `json:"status"`
+	Div    string `json:"div"`
+}
+
+type Annotation struct {
+	Text string `json:"text"`
+}
+
+type Address struct {
+	Text string `json:"text,omitempty"`
+}
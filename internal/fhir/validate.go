@@ -0,0 +1,294 @@
+package fhir
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	dateFormat     = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2})?)?$`)
+	dateTimeFormat = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2}(T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2}))?)?)?$`)
+	instantFormat  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`)
+)
+
+// Status codes of the required value sets
+var (
+	compositionStatuses = codes("preliminary", "final", "amended", "entered-in-error")
+	encounterStatuses   = codes("planned", "arrived", "triaged", "in-progress", "onleave", "finished", "cancelled", "entered-in-error", "unknown")
+	medicationStatuses  = codes("active", "completed", "entered-in-error", "intended", "stopped", "on-hold", "unknown", "not-taken")
+	observationStatuses = codes("registered", "preliminary", "final", "amended", "corrected", "cancelled", "entered-in-error", "unknown")
+	procedureStatuses   = codes("preparation", "in-progress", "not-done", "on-hold", "stopped", "completed", "entered-in-error", "unknown")
+	genders             = codes("male", "female", "other", "unknown")
+	attesterModes       = codes("personal", "professional", "legal", "official")
+)
+
+func codes(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// Validate checks a document bundle against the structure of the base
+// resources: required elements and their cardinality, required value sets,
+// date formats, the document bundle invariants and that every reference
+// resolves to an entry of the bundle.
+func (b *Bundle) Validate() error {
+	v := &validator{}
+	v.bundle(b)
+	if len(v.problems) > 0 {
+		return fmt.Errorf("invalid FHIR bundle: %s", strings.Join(v.problems, "; "))
+	}
+	return nil
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) errorf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(path, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.errorf("%s is required", path)
+	}
+}
+
+func (v *validator) code(path, value string, allowed map[string]bool) {
+	if value == "" {
+		v.errorf("%s is required", path)
+	} else if !allowed[value] {
+		v.errorf("%s: unknown code %q", path, value)
+	}
+}
+
+func (v *validator) format(path, value string, format *regexp.Regexp) {
+	if value != "" && !format.MatchString(value) {
+		v.errorf("%s: invalid value %q", path, value)
+	}
+}
+
+func (v *validator) bundle(b *Bundle) {
+	if b.ResourceType != "Bundle" {
+		v.errorf("resourceType must be Bundle")
+	}
+	if b.Type != "document" {
+		v.errorf("Bundle.type must be document")
+	}
+	// bdl-9, bdl-10
+	if b.Identifier == nil || b.Identifier.System == "" || b.Identifier.Value == "" {
+		v.errorf("Bundle.identifier with system and value is required for documents")
+	}
+	v.required("Bundle.timestamp", b.Timestamp)
+	v.format("Bundle.timestamp", b.Timestamp, instantFormat)
+
+	// bdl-11
+	if len(b.Entry) == 0 {
+		v.errorf("Bundle.entry is required for documents")
+		return
+	}
+	if _, ok := b.Entry[0].Resource.(*Composition); !ok {
+		v.errorf("the first entry of a document must be a Composition")
+	}
+
+	// bdl-7
+	urls := make(map[string]bool, len(b.Entry))
+	for i, entry := range b.Entry {
+		path := fmt.Sprintf("Bundle.entry[%d]", i)
+		if entry.FullURL == "" {
+			v.errorf("%s.fullUrl is required", path)
+		} else if urls[entry.FullURL] {
+			v.errorf("%s.fullUrl %s is not unique", path, entry.FullURL)
+		}
+		urls[entry.FullURL] = true
+		v.resource(path+".resource", entry.Resource)
+	}
+
+	// Every reference must point to an entry of the document
+	for _, ref := range references(b) {
+		if strings.HasPrefix(ref, "urn:uuid:") && !urls[ref] {
+			v.errorf("reference %s does not resolve to an entry", ref)
+		}
+	}
+}
+
+func (v *validator) resource(path string, resource interface{}) {
+	switch r := resource.(type) {
+	case *Composition:
+		v.composition(path, r)
+	case *Patient:
+		v.required(path+".id", r.ID)
+		for i, id := range r.Identifier {
+			v.identifier(fmt.Sprintf("%s.identifier[%d]", path, i), id)
+		}
+		if r.Gender != "" {
+			v.code(path+".gender", r.Gender, genders)
+		}
+		v.format(path+".birthDate", r.BirthDate, dateFormat)
+	case *Encounter:
+		v.required(path+".id", r.ID)
+		v.code(path+".status", r.Status, encounterStatuses)
+		v.required(path+".class.code", r.Class.Code)
+		v.period(path+".period", r.Period)
+	case *Condition:
+		v.required(path+".id", r.ID)
+		v.required(path+".subject.reference", r.Subject.Reference)
+		v.concept(path+".code", r.Code)
+	case *MedicationStatement:
+		v.required(path+".id", r.ID)
+		v.code(path+".status", r.Status, medicationStatuses)
+		v.required(path+".subject.reference", r.Subject.Reference)
+		switch {
+		case r.MedicationCodeableConcept != nil:
+			v.concept(path+".medicationCodeableConcept", r.MedicationCodeableConcept)
+		case r.MedicationReference != nil:
+			v.required(path+".medicationReference.reference", r.MedicationReference.Reference)
+		default:
+			v.errorf("%s.medication[x] is required", path)
+		}
+		v.period(path+".effectivePeriod", r.EffectivePeriod)
+	case *Observation:
+		v.required(path+".id", r.ID)
+		v.code(path+".status", r.Status, observationStatuses)
+		v.concept(path+".code", &r.Code)
+		v.format(path+".effectiveDateTime", r.EffectiveDateTime, dateTimeFormat)
+		// value[x] is a choice of a single type
+		if r.ValueQuantity != nil && r.ValueString != "" {
+			v.errorf("%s has more than one value[x]", path)
+		}
+		for i, c := range r.Component {
+			v.concept(fmt.Sprintf("%s.component[%d].code", path, i), &c.Code)
+		}
+		// obs-3: a reference range has at least a low or a high
+		for i, rr := range r.ReferenceRange {
+			if rr.Low == nil && rr.High == nil {
+				v.errorf("%s.referenceRange[%d] must have low or high", path, i)
+			}
+		}
+	case *Procedure:
+		v.required(path+".id", r.ID)
+		v.code(path+".status", r.Status, procedureStatuses)
+		v.required(path+".subject.reference", r.Subject.Reference)
+		v.format(path+".performedDateTime", r.PerformedDateTime, dateTimeFormat)
+	case *Practitioner:
+		v.required(path+".id", r.ID)
+	case *Organization:
+		v.required(path+".id", r.ID)
+		// org-1: an organization has an identifier or a name
+		if len(r.Identifier) == 0 && r.Name == "" {
+			v.errorf("%s must have an identifier or a name", path)
+		}
+	case nil:
+		v.errorf("%s is required", path)
+	default:
+		v.errorf("%s: unsupported resource %T", path, resource)
+	}
+}
+
+func (v *validator) composition(path string, c *Composition) {
+	v.required(path+".id", c.ID)
+	v.code(path+".status", c.Status, compositionStatuses)
+	v.concept(path+".type", &c.Type)
+	v.required(path+".date", c.Date)
+	v.format(path+".date", c.Date, dateTimeFormat)
+	v.required(path+".title", c.Title)
+	if len(c.Author) == 0 {
+		v.errorf("%s.author is required", path)
+	}
+	if c.Subject == nil || c.Subject.Reference == "" {
+		v.errorf("%s.subject is required for documents", path)
+	}
+	for i, a := range c.Attester {
+		v.code(fmt.Sprintf("%s.attester[%d].mode", path, i), a.Mode, attesterModes)
+		v.format(fmt.Sprintf("%s.attester[%d].time", path, i), a.Time, dateTimeFormat)
+	}
+	for i, s := range c.Section {
+		spath := fmt.Sprintf("%s.section[%d]", path, i)
+		// cmp-1: a section has text, entries or sub-sections
+		if s.Text == nil && len(s.Entry) == 0 {
+			v.errorf("%s must have text or entries", spath)
+		}
+		if s.Text != nil {
+			v.narrative(spath+".text", s.Text)
+		}
+		for j, ref := range s.Entry {
+			v.required(fmt.Sprintf("%s.entry[%d].reference", spath, j), ref.Reference)
+		}
+	}
+}
+
+func (v *validator) identifier(path string, id Identifier) {
+	v.required(path+".system", id.System)
+	v.required(path+".value", id.Value)
+}
+
+// concept checks that a concept is present and carries a code or a text,
+// with a system on every coding
+func (v *validator) concept(path string, c *CodeableConcept) {
+	if c == nil || (len(c.Coding) == 0 && c.Text == "") {
+		v.errorf("%s is required", path)
+		return
+	}
+	for i, coding := range c.Coding {
+		v.required(fmt.Sprintf("%s.coding[%d].system", path, i), coding.System)
+		v.required(fmt.Sprintf("%s.coding[%d].code", path, i), coding.Code)
+	}
+}
+
+func (v *validator) period(path string, p *Period) {
+	if p == nil {
+		return
+	}
+	v.format(path+".start", p.Start, dateTimeFormat)
+	v.format(path+".end", p.End, dateTimeFormat)
+	// per-1: the start is not after the end; both use the same layout here
+	if p.Start != "" && p.End != "" && len(p.Start) == len(p.End) && p.Start > p.End {
+		v.errorf("%s.start is after the end", path)
+	}
+}
+
+func (v *validator) narrative(path string, n *Narrative) {
+	v.code(path+".status", n.Status, codes("generated", "extensions", "additional", "empty"))
+	// txt-1, txt-2: the div is non-empty XHTML
+	if !strings.HasPrefix(n.Div, `<div xmlns="http://www.w3.org/1999/xhtml">`) || !strings.HasSuffix(n.Div, "</div>") {
+		v.errorf("%s.div must be an XHTML div", path)
+	}
+}
+
+// references collects every reference of the bundle from its JSON form, so
+// that no reference element is missed by the resource checks
+func references(b *Bundle) []string {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil
+	}
+	var refs []string
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			for key, value := range n {
+				if s, ok := value.(string); ok && key == "reference" {
+					refs = append(refs, s)
+					continue
+				}
+				walk(value)
+			}
+		case []interface{}:
+			for _, value := range n {
+				walk(value)
+			}
+		}
+	}
+	walk(tree)
+	return refs
+}
@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// ParseDRGsCSV reads a grouper's DRG list with the columns code, description
+// and relative_weight
+func ParseDRGsCSV(r io.Reader) ([]domain.DRG, error) {
+	records, err := csvRecords(r, "code", "description", "relative_weight")
+	if err != nil {
+		return nil, err
+	}
+
+	drgs := make([]domain.DRG, 0, len(records))
+	seen := make(map[string]bool)
+	for i, rec := range records {
+		line := i + 2
+		code := strings.ToUpper(rec["code"])
+		if code == "" {
+			return nil, fmt.Errorf("line %d: empty code", line)
+		}
+		if seen[code] {
+			return nil, fmt.Errorf("line %d: duplicate DRG %s", line, code)
+		}
+		seen[code] = true
+
+		weight, err := optionalFloat(rec["relative_weight"])
+		if err != nil || weight == nil || *weight < 0 {
+			return nil, fmt.Errorf("line %d: invalid relative_weight %q", line, rec["relative_weight"])
+		}
+
+		drgs = append(drgs, domain.DRG{
+			Code:           code,
+			Description:    rec["description"],
+			RelativeWeight: *weight,
+		})
+	}
+
+	return drgs, nil
+}
+
+// ParseDRGRulesCSV reads a grouper's rule table. Rows are kept in file order
+// since the first matching rule assigns the DRG. Expected columns: drg_code
+// and optionally principal_diagnoses, secondary_diagnoses, procedures (code
+// prefixes separated by "|"), sex, min_age, max_age, min_los and max_los.
+func ParseDRGRulesCSV(r io.Reader) ([]domain.DRGRule, error) {
+	records, err := csvRecords(r, "drg_code")
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]domain.DRGRule, 0, len(records))
+	for i, rec := range records {
+		line := i + 2
+		rule := domain.DRGRule{
+			DRGCode:            strings.ToUpper(rec["drg_code"]),
+			PrincipalDiagnoses: splitCodes(rec["principal_diagnoses"]),
+			SecondaryDiagnoses: splitCodes(rec["secondary_diagnoses"]),
+			Procedures:         splitCodes(rec["procedures"]),
+		}
+		if rule.DRGCode == "" {
+			return nil, fmt.Errorf("line %d: empty drg_code", line)
+		}
+
+		switch sex := strings.ToUpper(rec["sex"]); sex {
+		case "", "M", "F":
+			rule.Sex = domain.Sex(sex)
+		default:
+			return nil, fmt.Errorf("line %d: invalid sex %q", line, rec["sex"])
+		}
+
+		bounds := []struct {
+			column string
+			value  **int
+		}{
+			{"min_age", &rule.MinAge},
+			{"max_age", &rule.MaxAge},
+			{"min_los", &rule.MinLengthOfStay},
+			{"max_los", &rule.MaxLengthOfStay},
+		}
+		for _, b := range bounds {
+			if *b.value, err = optionalInt(rec[b.column]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %w", line, b.column, err)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func splitCodes(s string) []string {
+	var codes []string
+	for _, code := range strings.Split(s, "|") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
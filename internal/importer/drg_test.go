@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+func TestParseDRGsCSV(t *testing.T) {
+	drgs, err := ParseDRGsCSV(strings.NewReader("\ufeffcode;description;relative_weight\nf62b;Insuficiență cardiacă;1,24\n960Z;Negrupabil;0\n"))
+	if err != nil {
+		t.Fatalf("ParseDRGsCSV: %v", err)
+	}
+	want := []domain.DRG{
+		{Code: "F62B", Description: "Insuficiență cardiacă", RelativeWeight: 1.24},
+		{Code: "960Z", Description: "Negrupabil", RelativeWeight: 0},
+	}
+	if len(drgs) != len(want) {
+		t.Fatalf("got %d DRGs, want %d", len(drgs), len(want))
+	}
+	for i := range want {
+		if drgs[i] != want[i] {
+			t.Errorf("DRG %d = %+v, want %+v", i, drgs[i], want[i])
+		}
+	}
+}
+
+func TestParseDRGsCSVRejectsMalformedRows(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"missing column", "code,description\nF62B,Insuficiență cardiacă\n"},
+		{"empty code", "code,description,relative_weight\n,Insuficiență cardiacă,1.24\n"},
+		{"duplicate code", "code,description,relative_weight\nF62B,a,1.24\nf62b,b,1.3\n"},
+		{"missing weight", "code,description,relative_weight\nF62B,Insuficiență cardiacă,\n"},
+		{"invalid weight", "code,description,relative_weight\nF62B,Insuficiență cardiacă,mare\n"},
+		{"negative weight", "code,description,relative_weight\nF62B,Insuficiență cardiacă,-1\n"},
+		{"unbalanced quote", "code,description,relative_weight\nF62B,\"Insuficiență,1.24\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if drgs, err := ParseDRGsCSV(strings.NewReader(tt.csv)); err == nil {
+				t.Errorf("ParseDRGsCSV accepted %q: %+v", tt.csv, drgs)
+			}
+		})
+	}
+}
+
+func TestParseDRGRulesCSV(t *testing.T) {
+	rules, err := ParseDRGRulesCSV(strings.NewReader(
+		"drg_code,principal_diagnoses,secondary_diagnoses,procedures,sex,min_age,max_age,min_los,max_los\n" +
+			"h08a,K80| K81 ,A41|,30445,,,,,\n" +
+			"O60Z,O80,,,f,12,55,0,3\n" +
+			"960Z\n"))
+	if err != nil {
+		t.Fatalf("ParseDRGRulesCSV: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("got %d rules, want 3 in file order", len(rules))
+	}
+
+	h08a := rules[0]
+	if h08a.DRGCode != "H08A" || strings.Join(h08a.PrincipalDiagnoses, "|") != "K80|K81" ||
+		strings.Join(h08a.SecondaryDiagnoses, "|") != "A41" || strings.Join(h08a.Procedures, "|") != "30445" {
+		t.Errorf("rule 1 = %+v", h08a)
+	}
+	if h08a.Sex != domain.SexUnknown || h08a.MinAge != nil || h08a.MaxLengthOfStay != nil {
+		t.Errorf("empty columns must match every stay: %+v", h08a)
+	}
+
+	o60z := rules[1]
+	if o60z.Sex != domain.SexFemale || *o60z.MinAge != 12 || *o60z.MaxAge != 55 || *o60z.MinLengthOfStay != 0 || *o60z.MaxLengthOfStay != 3 {
+		t.Errorf("rule 2 = %+v", o60z)
+	}
+
+	if catchAll := rules[2]; catchAll.DRGCode != "960Z" || !catchAll.Matches(domain.DRGCase{PrincipalDiagnosis: "Z00.0", AgeYears: 40}) {
+		t.Errorf("rule 3 = %+v, want a catch-all", catchAll)
+	}
+}
+
+func TestParseDRGRulesCSVRejectsMalformedRows(t *testing.T) {
+	const header = "drg_code,principal_diagnoses,secondary_diagnoses,procedures,sex,min_age,max_age,min_los,max_los\n"
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"missing column", "principal_diagnoses\nK80\n"},
+		{"empty code", header + ",K80,,,,,,,\n"},
+		{"invalid sex", header + "H08B,K80,,,X,,,,\n"},
+		{"invalid age", header + "H08B,K80,,,,optsprezece,,,\n"},
+		{"invalid length of stay", header + "H08B,K80,,,,,,,3.5\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rules, err := ParseDRGRulesCSV(strings.NewReader(tt.csv)); err == nil {
+				t.Errorf("ParseDRGRulesCSV accepted %q: %+v", tt.csv, rules)
+			}
+		})
+	}
+}
+
+// TestSampleDefinitions groups stays with the bundled definitions
+func TestSampleDefinitions(t *testing.T) {
+	const dir = "../../data/drg/ar-drg-v5-sample/"
+	drgFile, err := os.Open(dir + "drgs.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drgFile.Close()
+	ruleFile, err := os.Open(dir + "rules.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ruleFile.Close()
+
+	drgs, err := ParseDRGsCSV(drgFile)
+	if err != nil {
+		t.Fatalf("drgs.csv: %v", err)
+	}
+	rules, err := ParseDRGRulesCSV(ruleFile)
+	if err != nil {
+		t.Fatalf("rules.csv: %v", err)
+	}
+	grouper, err := domain.NewTableGrouper("ar-drg-v5-sample", drgs, rules)
+	if err != nil {
+		t.Fatalf("NewTableGrouper: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		c    domain.DRGCase
+		want string
+	}{
+		{"laparoscopic cholecystectomy with sepsis", domain.DRGCase{PrincipalDiagnosis: "K80.2", SecondaryDiagnoses: []string{"A41.9"}, Procedures: []string{"30445-00"}, LengthOfStayDays: 4}, "H08A"},
+		{"laparoscopic cholecystectomy", domain.DRGCase{PrincipalDiagnosis: "K80.2", SecondaryDiagnoses: []string{"I10"}, Procedures: []string{"30445-00"}, LengthOfStayDays: 2}, "H08B"},
+		{"open cholecystectomy", domain.DRGCase{PrincipalDiagnosis: "K81.0", Procedures: []string{"30443-00"}, LengthOfStayDays: 6}, "H07B"},
+		{"day dialysis", domain.DRGCase{PrincipalDiagnosis: "N18.5", Procedures: []string{"13100-00"}, LengthOfStayDays: 0}, "L61Z"},
+		{"dialysis with an overnight stay", domain.DRGCase{PrincipalDiagnosis: "N18.5", Procedures: []string{"13100-00"}, LengthOfStayDays: 1}, "960Z"},
+		{"heart failure", domain.DRGCase{PrincipalDiagnosis: "I50.0", SecondaryDiagnoses: []string{"I10"}, LengthOfStayDays: 7}, "F62B"},
+		{"pneumonia with respiratory failure", domain.DRGCase{PrincipalDiagnosis: "J18.9", SecondaryDiagnoses: []string{"J96.0"}, LengthOfStayDays: 9}, "E62A"},
+		{"catch-all", domain.DRGCase{PrincipalDiagnosis: "S72.0", LengthOfStayDays: 12}, "960Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := grouper.Group(tt.c)
+			if err != nil {
+				t.Fatalf("Group: %v", err)
+			}
+			if got.Code != tt.want {
+				t.Errorf("Group = %s, want %s", got.Code, tt.want)
+			}
+		})
+	}
+}
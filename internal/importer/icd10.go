@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// ParseICD10CSV reads a flat ICD-10 code list with the columns
+// code, description_ro (or description) and optionally category.
+func ParseICD10CSV(r io.Reader, version string) (*domain.ICD10Release, error) {
+	records, err := csvRecords(r, "code")
+	if err != nil {
+		return nil, err
+	}
+
+	release := &domain.ICD10Release{Version: version, SourceFormat: "csv"}
+	seen := make(map[string]bool, len(records))
+
+	for i, rec := range records {
+		line := i + 2
+		code := normalizeICD10Code(rec["code"])
+		description := rec["description_ro"]
+		if description == "" {
+			description = rec["description"]
+		}
+
+		if code == "" || description == "" {
+			return nil, fmt.Errorf("line %d: code and description are required", line)
+		}
+		if seen[code] {
+			return nil, fmt.Errorf("line %d: duplicate code %s", line, code)
+		}
+		seen[code] = true
+
+		release.Codes = append(release.Codes, domain.ICD10Reference{
+			Code:          code,
+			DescriptionRO: description,
+			Category:      rec["category"],
+			Level:         domain.ICD10LevelOf(code),
+			ParentCode:    domain.ICD10ParentCode(code),
+		})
+	}
+
+	return release, nil
+}
+
+// claml mirrors the subset of the ClaML 2.0 schema used by ICD-10 releases
+type claml struct {
+	Title struct {
+		Version string `xml:"version,attr"`
+	} `xml:"Title"`
+	Classes []clamlClass `xml:"Class"`
+}
+
+type clamlClass struct {
+	Code       string `xml:"code,attr"`
+	Kind       string `xml:"kind,attr"`
+	SuperClass struct {
+		Code string `xml:"code,attr"`
+	} `xml:"SuperClass"`
+	Rubrics []struct {
+		Kind  string `xml:"kind,attr"`
+		Label struct {
+			Inner string `xml:",innerxml"`
+		} `xml:"Label"`
+	} `xml:"Rubric"`
+}
+
+func (c clamlClass) preferredLabel() string {
+	for _, rubric := range c.Rubrics {
+		if rubric.Kind == "preferred" {
+			return stripTags(rubric.Label.Inner)
+		}
+	}
+	return ""
+}
+
+// ParseICD10ClaML reads an ICD-10 release in ClaML XML format, including the
+// chapter and block structure. When version is empty the release title's
+// version attribute is used.
+func ParseICD10ClaML(r io.Reader, version string) (*domain.ICD10Release, error) {
+	var doc claml
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding ClaML: %w", err)
+	}
+
+	if version == "" {
+		version = doc.Title.Version
+	}
+	if version == "" {
+		return nil, fmt.Errorf("release version is required")
+	}
+
+	release := &domain.ICD10Release{Version: version, SourceFormat: "claml"}
+	chapterIndex := make(map[string]int)
+
+	for _, class := range doc.Classes {
+		label := class.preferredLabel()
+		switch class.Kind {
+		case "chapter":
+			chapterIndex[class.Code] = len(release.Chapters)
+			release.Chapters = append(release.Chapters, domain.ICD10Chapter{
+				Code:  class.Code,
+				Title: label,
+			})
+
+		case "block":
+			start, end, ok := strings.Cut(class.Code, "-")
+			if !ok {
+				end = start
+			}
+			release.Blocks = append(release.Blocks, domain.ICD10Block{
+				Code:        class.Code,
+				ChapterCode: class.SuperClass.Code,
+				Title:       label,
+				RangeStart:  start,
+				RangeEnd:    end,
+			})
+
+		case "category":
+			code := normalizeICD10Code(class.Code)
+			release.Codes = append(release.Codes, domain.ICD10Reference{
+				Code:          code,
+				DescriptionRO: label,
+				Level:         domain.ICD10LevelOf(code),
+				ParentCode:    domain.ICD10ParentCode(code),
+			})
+		}
+	}
+
+	// Blocks may nest inside other blocks in ClaML; attach them to their chapter
+	blockChapter := make(map[string]string)
+	for _, b := range release.Blocks {
+		blockChapter[b.Code] = b.ChapterCode
+	}
+	for i := range release.Blocks {
+		chapter := release.Blocks[i].ChapterCode
+		for depth := 0; depth < 5; depth++ {
+			if _, isChapter := chapterIndex[chapter]; isChapter {
+				break
+			}
+			chapter = blockChapter[chapter]
+		}
+		if _, isChapter := chapterIndex[chapter]; !isChapter {
+			return nil, fmt.Errorf("block %s has no chapter", release.Blocks[i].Code)
+		}
+		release.Blocks[i].ChapterCode = chapter
+
+		ch := &release.Chapters[chapterIndex[chapter]]
+		if ch.RangeStart == "" || release.Blocks[i].RangeStart < ch.RangeStart {
+			ch.RangeStart = release.Blocks[i].RangeStart
+		}
+		if release.Blocks[i].RangeEnd > ch.RangeEnd {
+			ch.RangeEnd = release.Blocks[i].RangeEnd
+		}
+	}
+
+	return release, nil
+}
+
+func normalizeICD10Code(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	// Some releases mark dagger/asterisk codes or pad with a trailing dash
+	return strings.TrimRight(code, "*†+-")
+}
+
+// stripTags removes inline markup such as <Reference> from a ClaML label
+func stripTags(s string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(xmlUnescape(b.String())), " ")
+}
+
+var xmlEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&#39;", "'", "&#34;", `"`, "&amp;", "&")
+
+// xmlUnescape decodes the predefined entities left in raw inner XML
+func xmlUnescape(s string) string {
+	return xmlEntities.Replace(s)
+}
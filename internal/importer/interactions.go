@@ -0,0 +1,41 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// ParseInteractionsCSV reads a drug–drug interaction table with the columns
+// substance_a, substance_b, severity (minor, moderate, major,
+// contraindicated) and description
+func ParseInteractionsCSV(r io.Reader) ([]domain.DrugInteraction, error) {
+	records, err := csvRecords(r, "substance_a", "substance_b", "severity")
+	if err != nil {
+		return nil, err
+	}
+
+	interactions := make([]domain.DrugInteraction, 0, len(records))
+	for i, rec := range records {
+		line := i + 2
+		in := domain.DrugInteraction{
+			SubstanceA:  rec["substance_a"],
+			SubstanceB:  rec["substance_b"],
+			Severity:    domain.InteractionSeverity(strings.ToLower(rec["severity"])),
+			Description: rec["description"],
+		}
+
+		if in.SubstanceA == "" || in.SubstanceB == "" {
+			return nil, fmt.Errorf("line %d: both substances are required", line)
+		}
+		if !in.Severity.IsValid() {
+			return nil, fmt.Errorf("line %d: invalid severity %q", line, rec["severity"])
+		}
+
+		interactions = append(interactions, in)
+	}
+
+	return interactions, nil
+}
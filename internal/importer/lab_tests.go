@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// ParseLabTestsCSV reads a lab test catalog export. Each row describes one
+// reference range; rows sharing a LOINC code are merged into a single test.
+// Expected columns: loinc_code, name_ro, default_unit and optionally
+// sex, age_min_years, age_max_years, low, high.
+func ParseLabTestsCSV(r io.Reader) ([]domain.LabTestReference, error) {
+	records, err := csvRecords(r, "loinc_code", "name_ro")
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []domain.LabTestReference
+	index := make(map[string]int)
+
+	for i, rec := range records {
+		line := i + 2
+		code := rec["loinc_code"]
+		if code == "" {
+			return nil, fmt.Errorf("line %d: empty loinc_code", line)
+		}
+
+		pos, seen := index[code]
+		if !seen {
+			if rec["name_ro"] == "" {
+				return nil, fmt.Errorf("line %d: empty name_ro for %s", line, code)
+			}
+			tests = append(tests, domain.LabTestReference{
+				LOINCCode:   code,
+				NameRO:      rec["name_ro"],
+				DefaultUnit: rec["default_unit"],
+			})
+			pos = len(tests) - 1
+			index[code] = pos
+		}
+
+		rng, hasRange, err := parseLabRange(rec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if hasRange {
+			tests[pos].ReferenceRanges = append(tests[pos].ReferenceRanges, rng)
+		}
+	}
+
+	return tests, nil
+}
+
+func parseLabRange(rec map[string]string) (domain.LabReferenceRange, bool, error) {
+	var rng domain.LabReferenceRange
+	var err error
+
+	switch sex := strings.ToUpper(rec["sex"]); sex {
+	case "", "M", "F":
+		rng.Sex = domain.Sex(sex)
+	default:
+		return rng, false, fmt.Errorf("invalid sex %q", rec["sex"])
+	}
+
+	if rng.AgeMinYears, err = optionalInt(rec["age_min_years"]); err != nil {
+		return rng, false, fmt.Errorf("invalid age_min_years: %w", err)
+	}
+	if rng.AgeMaxYears, err = optionalInt(rec["age_max_years"]); err != nil {
+		return rng, false, fmt.Errorf("invalid age_max_years: %w", err)
+	}
+	if rng.Low, err = optionalFloat(rec["low"]); err != nil {
+		return rng, false, fmt.Errorf("invalid low: %w", err)
+	}
+	if rng.High, err = optionalFloat(rec["high"]); err != nil {
+		return rng, false, fmt.Errorf("invalid high: %w", err)
+	}
+
+	return rng, rng.Low != nil || rng.High != nil, nil
+}
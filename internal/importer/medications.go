@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// medicationColumns maps nomenclature export headers (Romanian regulator
+// names and their English equivalents) to the field they populate
+var medicationColumns = map[string][]string{
+	"authorization_code":  {"cod_cim", "cod cim", "authorization_code"},
+	"name":                {"denumire_comerciala", "denumire comerciala", "name"},
+	"active_substance":    {"dci", "substanta_activa", "active_substance"},
+	"form":                {"forma_farmaceutica", "forma farmaceutica", "form"},
+	"strength":            {"concentratie", "strength"},
+	"atc_code":            {"cod_atc", "cod atc", "atc_code"},
+	"package":             {"ambalaj", "package"},
+	"prescription_status": {"prescriptie", "mod_prescriere", "prescription_status"},
+	"manufacturer":        {"firma_detinatoare", "producator", "manufacturer"},
+	"max_daily_dose_mg":   {"doza_maxima_zilnica_mg", "doza maxima zilnica", "max_daily_dose_mg"},
+}
+
+func medicationField(rec map[string]string, field string) string {
+	for _, alias := range medicationColumns[field] {
+		if v, ok := rec[alias]; ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseMedicationsCSV reads the national medicines nomenclature CSV export.
+// Products without a marketing authorization code (CIM) are rejected since
+// it is the key used to upsert them.
+func ParseMedicationsCSV(r io.Reader) ([]domain.MedicationReference, error) {
+	records, err := csvRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]domain.MedicationReference, 0, len(records))
+	for i, rec := range records {
+		product := domain.MedicationReference{
+			AuthorizationCode:  medicationField(rec, "authorization_code"),
+			Name:               medicationField(rec, "name"),
+			ActiveSubstance:    medicationField(rec, "active_substance"),
+			Form:               medicationField(rec, "form"),
+			Dosage:             medicationField(rec, "strength"),
+			ATCCode:            strings.ToUpper(medicationField(rec, "atc_code")),
+			Package:            medicationField(rec, "package"),
+			PrescriptionStatus: medicationField(rec, "prescription_status"),
+			Manufacturer:       medicationField(rec, "manufacturer"),
+		}
+		maxDose, err := optionalFloat(medicationField(rec, "max_daily_dose_mg"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: max daily dose: %w", i+2, err)
+		}
+		product.MaxDailyDoseMg = maxDose
+		if err := validateProduct(product); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+		products = append(products, product)
+	}
+
+	return products, nil
+}
+
+type nomenclatorXML struct {
+	Products []struct {
+		AuthorizationCode  string `xml:"CodCIM"`
+		Name               string `xml:"DenumireComerciala"`
+		ActiveSubstance    string `xml:"DCI"`
+		Form               string `xml:"FormaFarmaceutica"`
+		Strength           string `xml:"Concentratie"`
+		ATCCode            string `xml:"CodATC"`
+		Package            string `xml:"Ambalaj"`
+		PrescriptionStatus string `xml:"Prescriptie"`
+		Manufacturer       string `xml:"FirmaDetinatoare"`
+	} `xml:"Medicament"`
+}
+
+// ParseMedicationsXML reads the XML variant of the nomenclature, a list of
+// <Medicament> elements under the document root
+func ParseMedicationsXML(r io.Reader) ([]domain.MedicationReference, error) {
+	var doc nomenclatorXML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding nomenclature XML: %w", err)
+	}
+
+	products := make([]domain.MedicationReference, 0, len(doc.Products))
+	for i, p := range doc.Products {
+		product := domain.MedicationReference{
+			AuthorizationCode:  strings.TrimSpace(p.AuthorizationCode),
+			Name:               strings.TrimSpace(p.Name),
+			ActiveSubstance:    strings.TrimSpace(p.ActiveSubstance),
+			Form:               strings.TrimSpace(p.Form),
+			Dosage:             strings.TrimSpace(p.Strength),
+			ATCCode:            strings.ToUpper(strings.TrimSpace(p.ATCCode)),
+			Package:            strings.TrimSpace(p.Package),
+			PrescriptionStatus: strings.TrimSpace(p.PrescriptionStatus),
+			Manufacturer:       strings.TrimSpace(p.Manufacturer),
+		}
+		if err := validateProduct(product); err != nil {
+			return nil, fmt.Errorf("product %d: %w", i+1, err)
+		}
+		products = append(products, product)
+	}
+
+	return products, nil
+}
+
+func validateProduct(p domain.MedicationReference) error {
+	if p.AuthorizationCode == "" {
+		return fmt.Errorf("missing authorization code (CIM)")
+	}
+	if p.Name == "" || p.ActiveSubstance == "" {
+		return fmt.Errorf("%s: name and active substance are required", p.AuthorizationCode)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// ParseProceduresCSV reads an ACHI procedure classification export.
+// Expected columns: code, title_ro and optionally block and active
+// (1/0, true/false or da/nu; codes are active when the column is empty).
+func ParseProceduresCSV(r io.Reader) ([]domain.ProcedureReference, error) {
+	records, err := csvRecords(r, "code", "title_ro")
+	if err != nil {
+		return nil, err
+	}
+
+	var procedures []domain.ProcedureReference
+	seen := make(map[string]int)
+
+	for i, rec := range records {
+		line := i + 2
+		code, ok := domain.NormalizeProcedureCode(rec["code"])
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid procedure code %q", line, rec["code"])
+		}
+		if prev, dup := seen[code]; dup {
+			return nil, fmt.Errorf("line %d: duplicate code %s (first on line %d)", line, code, prev)
+		}
+		seen[code] = line
+
+		if rec["title_ro"] == "" {
+			return nil, fmt.Errorf("line %d: empty title_ro for %s", line, code)
+		}
+
+		active, err := parseActive(rec["active"])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		procedures = append(procedures, domain.ProcedureReference{
+			Code:    code,
+			Block:   rec["block"],
+			TitleRO: rec["title_ro"],
+			Active:  active,
+		})
+	}
+
+	return procedures, nil
+}
+
+func parseActive(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "", "1", "true", "da":
+		return true, nil
+	case "0", "false", "nu":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid active %q", s)
+}
@@ -0,0 +1,209 @@
+// Package render produces printable documents from reports.
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// Document is everything printed on a report: the report itself, the
+// letterhead of its hospital and the doctor in the signature block
+type Document struct {
+	Report   *domain.Report
+	Hospital *domain.Hospital
+	// Logo is a PNG or JPEG image, nil when the hospital has none
+	Logo     []byte
+	LogoType string
+	// Doctor is the report's author; nil when the user no longer exists
+	Doctor *domain.User
+	// VerificationURL is the public link encoded in the QR code of signed
+	// reports; empty when the report cannot be verified
+	VerificationURL string
+}
+
+// Signed reports whether the report is printed as a final document
+func (d *Document) Signed() bool {
+	return d.Report.Status == domain.StatusSigned
+}
+
+// Date is the date printed next to the signature: when the report was
+// finalized, or last changed for drafts
+func (d *Document) Date() time.Time {
+	if d.Report.FinalizedAt != nil {
+		return *d.Report.FinalizedAt
+	}
+	return d.Report.LastModified
+}
+
+// FormatDate formats a date the Romanian way (31.12.2025); zero dates are empty
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("02.01.2006")
+}
+
+// FormatDateTime formats a date and time the Romanian way (31.12.2025 14:30)
+func FormatDateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("02.01.2006 15:04")
+}
+
+var monthNames = [...]string{
+	"ianuarie", "februarie", "martie", "aprilie", "mai", "iunie",
+	"iulie", "august", "septembrie", "octombrie", "noiembrie", "decembrie",
+}
+
+// FormatLongDate writes the month in words (4 martie 2026)
+func FormatLongDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d %s %d", t.Day(), monthNames[t.Month()-1], t.Year())
+}
+
+// FormatNumber formats a number with a decimal comma and dots between
+// thousands (1.234,5)
+func FormatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteByte(',')
+		b.WriteString(fraction)
+	}
+	return sign + b.String()
+}
+
+// DoctorName is the doctor as printed in the signature block
+func DoctorName(u *domain.User) string {
+	if u == nil {
+		return ""
+	}
+	return strings.TrimSpace("Dr. " + u.LastName + " " + u.FirstName)
+}
+
+// PatientName is the patient as printed in the header, family name first
+func PatientName(p domain.PatientDataSection) string {
+	return strings.TrimSpace(strings.ToUpper(p.LastName) + " " + p.FirstName)
+}
+
+// Diagnosis is an ICD-10 code with its description
+func Diagnosis(c domain.ICD10Code) string {
+	if c.Code == "" {
+		return c.Description
+	}
+	return strings.TrimSpace(c.Code + " " + c.Description)
+}
+
+// MedicationDose is the dose of a medication: the structured sig when
+// present, otherwise the dosage and frequency as written
+func MedicationDose(m domain.Medication) string {
+	if m.Dose != nil {
+		return m.Dose.String()
+	}
+	return JoinNonEmpty(", ", m.Dosage, m.Frequency)
+}
+
+// MedicationPeriod is the start and end date of a medication
+func MedicationPeriod(m domain.Medication) string {
+	if m.EndDate == nil {
+		return FormatDate(m.StartDate)
+	}
+	return FormatDate(m.StartDate) + " – " + FormatDate(*m.EndDate)
+}
+
+// LabResult is a lab test result with its unit
+func LabResult(t domain.LabTest) string {
+	result := t.Result
+	if t.Value != nil {
+		result = FormatNumber(*t.Value)
+	}
+	return JoinNonEmpty(" ", result, t.Unit)
+}
+
+// LabRange is the reference range of a lab test
+func LabRange(t domain.LabTest) string {
+	switch {
+	case t.ReferenceLow != nil && t.ReferenceHigh != nil:
+		return FormatNumber(*t.ReferenceLow) + " – " + FormatNumber(*t.ReferenceHigh)
+	case t.ReferenceLow != nil:
+		return "≥ " + FormatNumber(*t.ReferenceLow)
+	case t.ReferenceHigh != nil:
+		return "≤ " + FormatNumber(*t.ReferenceHigh)
+	}
+	return ""
+}
+
+// VitalSigns summarizes the recorded vital signs on one line
+func VitalSigns(v domain.VitalSigns) string {
+	var parts []string
+	if v.BloodPressure != "" {
+		parts = append(parts, "TA "+v.BloodPressure+" mmHg")
+	}
+	if v.HeartRate > 0 {
+		parts = append(parts, fmt.Sprintf("AV %d/min", v.HeartRate))
+	}
+	if v.Temperature > 0 {
+		parts = append(parts, "T "+FormatNumber(v.Temperature)+" °C")
+	}
+	if v.RespiratoryRate > 0 {
+		parts = append(parts, fmt.Sprintf("FR %d/min", v.RespiratoryRate))
+	}
+	if v.OxygenSaturation > 0 {
+		parts = append(parts, fmt.Sprintf("SpO2 %d%%", v.OxygenSaturation))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Allergies lists the structured allergies after the free-text notes
+func Allergies(a domain.AnamnesisSection) string {
+	var parts []string
+	for _, allergy := range a.AllergyList {
+		parts = append(parts, JoinNonEmpty(" – ", allergy.Label(), allergy.Reaction))
+	}
+	return JoinNonEmpty("; ", a.Allergies, strings.Join(parts, "; "))
+}
+
+// Location is the department, ward and bed of the patient
+func Location(p domain.PatientDataSection) string {
+	var parts []string
+	if p.Department != "" {
+		parts = append(parts, p.Department)
+	}
+	if p.Ward != "" {
+		parts = append(parts, "salon "+p.Ward)
+	}
+	if p.Bed != "" {
+		parts = append(parts, "pat "+p.Bed)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// JoinNonEmpty joins the parts that are not blank
+func JoinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
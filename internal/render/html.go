@@ -0,0 +1,255 @@
+package render
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+//go:embed layouts/default.html
+var defaultLayout string
+
+// DefaultLayout is the built-in print layout, used when a hospital has not
+// stored its own
+func DefaultLayout() string {
+	return defaultLayout
+}
+
+// layoutFuncs are available in every print layout. Their results are plain
+// strings, escaped by html/template like any other value; only paragraphs
+// returns markup, built from escaped lines.
+var layoutFuncs = template.FuncMap{
+	"date":        FormatDate,
+	"datetime":    FormatDateTime,
+	"longDate":    FormatLongDate,
+	"number":      FormatNumber,
+	"doctor":      DoctorName,
+	"department":  doctorDepartment,
+	"patientName": PatientName,
+	"diagnosis":   Diagnosis,
+	"dose":        MedicationDose,
+	"period":      MedicationPeriod,
+	"labResult":   LabResult,
+	"labRange":    LabRange,
+	"hasAbnormal": hasAbnormal,
+	"vitals":      VitalSigns,
+	"allergies":   Allergies,
+	"location":    Location,
+	"join":        func(sep string, values []string) string { return strings.Join(values, sep) },
+	"shortHash":   ShortHash,
+	"paragraphs":  paragraphs,
+}
+
+// PrintView is the data a print layout is executed with
+type PrintView struct {
+	Title string
+	// Number is the short report number printed under the title
+	Number   string
+	Draft    bool
+	Date     time.Time
+	Report   *domain.Report
+	Content  domain.ReportContent
+	Hospital *domain.Hospital
+	Doctor   *domain.User
+	// LogoURL is the hospital logo as a data URL, empty without a logo
+	LogoURL        template.URL
+	Reconciliation []domain.ReconciliationRow
+	// Verification is set on signed reports that can be verified
+	Verification *VerificationView
+}
+
+// VerificationView is the QR code block of a signed report
+type VerificationView struct {
+	URL string
+	// QR is the QR code image as a data URL
+	QR   template.URL
+	Code string
+	Hash string
+}
+
+// Has reports whether the report prints the section: a section of its type,
+// or a legacy section it still holds
+func (v PrintView) Has(section string) bool {
+	for _, name := range v.Content.Sections(v.Report.ReportType) {
+		if name == section {
+			return true
+		}
+	}
+	return false
+}
+
+func newPrintView(doc *Document) (PrintView, error) {
+	view := PrintView{
+		Title:          doc.Report.ReportType.Title(),
+		Number:         strings.ToUpper(doc.Report.ID.String()[:8]),
+		Draft:          !doc.Signed(),
+		Date:           doc.Date(),
+		Report:         doc.Report,
+		Content:        doc.Report.Content,
+		Hospital:       doc.Hospital,
+		Doctor:         doc.Doctor,
+		Reconciliation: doc.Report.Content.ReconciliationTable(),
+	}
+	if len(doc.Logo) > 0 && (doc.LogoType == "image/png" || doc.LogoType == "image/jpeg") {
+		view.LogoURL = template.URL("data:" + doc.LogoType + ";base64," + base64.StdEncoding.EncodeToString(doc.Logo))
+	}
+	if doc.Verifiable() {
+		png, err := doc.VerificationQR()
+		if err != nil {
+			return view, err
+		}
+		view.Verification = &VerificationView{
+			URL:  doc.VerificationURL,
+			QR:   template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png)),
+			Code: VerificationCode(doc.Report.Signature.Token),
+			Hash: doc.Report.Signature.ContentHash,
+		}
+	}
+	return view, nil
+}
+
+// ParseLayout parses the source of a print layout
+func ParseLayout(source string) (*template.Template, error) {
+	tmpl, err := template.New("layout").Funcs(layoutFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPrintLayout, err)
+	}
+	return tmpl, nil
+}
+
+// HTML writes the report as an HTML page using the layout source. The page
+// is rendered in full before anything is written, so a layout failing
+// halfway does not produce a truncated page.
+func HTML(w io.Writer, doc *Document, source string) error {
+	tmpl, err := ParseLayout(source)
+	if err != nil {
+		return err
+	}
+
+	view, err := newPrintView(doc)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, view); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidPrintLayout, err)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// CheckLayout renders the layout against a sample report of the type, so
+// that references to missing fields are caught before the layout is saved
+func CheckLayout(source string, hospital *domain.Hospital, reportType domain.ReportType) error {
+	return HTML(io.Discard, SampleDocument(hospital, reportType), source)
+}
+
+// paragraphs escapes free text and keeps its line breaks
+func paragraphs(text string) template.HTML {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = template.HTMLEscapeString(line)
+	}
+	return template.HTML(strings.Join(lines, "<br>\n"))
+}
+
+func doctorDepartment(u *domain.User) string {
+	if u == nil || u.Specialty == "" {
+		return ""
+	}
+	return domain.Specialty(u.Specialty).DepartmentName()
+}
+
+// SampleDocument is a made-up draft report of the type, printed when a
+// layout is previewed without a report
+func SampleDocument(hospital *domain.Hospital, reportType domain.ReportType) *Document {
+	admitted := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	discharged := admitted.AddDate(0, 0, 5)
+	hemoglobin, low, high := 11.2, 12.0, 16.0
+
+	content := domain.NewContent(reportType)
+	content.PatientData = domain.PatientDataSection{
+		FirstName:     "Maria",
+		LastName:      "Popescu",
+		CNP:           "2600315123456",
+		BirthDate:     time.Date(1960, time.March, 15, 0, 0, 0, 0, time.UTC),
+		Department:    "Cardiologie",
+		Ward:          "12",
+		Bed:           "3",
+		AdmissionDate: admitted,
+		DischargeDate: discharged,
+	}
+	content.Anamnesis = domain.AnamnesisSection{
+		ChiefComplaint:          "Dispnee la eforturi mici, edeme gambiere.",
+		HistoryOfPresentIllness: "Simptomatologie debutată în urmă cu două săptămâni.\nAgravare progresivă în ultimele zile.",
+		PastMedicalHistory:      "HTA gradul II, diabet zaharat tip 2.",
+		Allergies:               "Nu se cunosc alergii medicamentoase.",
+	}
+	content.Examination = domain.ExaminationSection{
+		GeneralCondition: "Stare generală influențată",
+		Consciousness:    "Conștientă, cooperantă",
+		VitalSigns:       domain.VitalSigns{BloodPressure: "150/90", HeartRate: 96, Temperature: 36.8, OxygenSaturation: 93},
+	}
+	content.LabResults = domain.LabResultsSection{
+		LaboratoryTests: []domain.LabTest{
+			{Name: "Hemoglobină", Unit: "g/dL", Date: admitted, Value: &hemoglobin, ReferenceLow: &low, ReferenceHigh: &high},
+		},
+	}
+	content.Diagnosis = domain.DiagnosisSection{
+		PrimaryDiagnosis:   domain.ICD10Code{Code: "I50.0", Description: "Insuficiență cardiacă congestivă"},
+		SecondaryDiagnoses: []domain.ICD10Code{{Code: "I10", Description: "Hipertensiune esențială (primară)"}},
+	}
+	content.Treatment = domain.TreatmentSection{
+		Medications: []domain.Medication{
+			{Name: "Furosemid", Dosage: "40 mg", Frequency: "1 dată pe zi", Route: "iv", StartDate: admitted},
+		},
+	}
+	content.Recommendations = domain.RecommendationsSection{
+		DischargePlan: "Regim hiposodat, monitorizarea greutății zilnic.",
+		FollowUp:      "Control cardiologic peste o lună.",
+	}
+	if content.OperativeNote != nil {
+		content.OperativeNote.PreOpDiagnosis = domain.ICD10Code{Code: "K80.2", Description: "Calcul al vezicii biliare fără colecistită"}
+		content.OperativeNote.PostOpDiagnosis = content.OperativeNote.PreOpDiagnosis
+		content.OperativeNote.ProcedureCodes = []domain.ProcedureCode{{Code: "30445-00", Description: "Colecistectomie laparoscopică"}}
+		content.OperativeNote.Surgeon = "Dr. Ionescu Andrei"
+		content.OperativeNote.AnesthesiaType = domain.AnesthesiaGeneral
+		content.OperativeNote.Anesthesiologist = "Dr. Pop Elena"
+		content.OperativeNote.Findings = "Vezică biliară cu pereți îngroșați, multipli calculi."
+		content.OperativeNote.Complications = "Fără complicații"
+	}
+	if content.Transfer != nil {
+		content.Transfer.Source = domain.TransferUnit{Department: "Cardiologie"}
+		content.Transfer.Destination = domain.TransferUnit{Department: "Terapie intensivă"}
+		content.Transfer.Reason = "Agravarea insuficienței respiratorii."
+	}
+	// Only the sections of the report type, so the sample has no legacy ones
+	content.Normalize(reportType)
+
+	return &Document{
+		Report: &domain.Report{
+			ID:               uuid.Nil,
+			HospitalID:       hospital.ID,
+			PatientCNP:       content.PatientData.CNP,
+			PatientFirstName: content.PatientData.FirstName,
+			PatientLastName:  content.PatientData.LastName,
+			Specialty:        domain.SpecialtyCardiology,
+			ReportType:       reportType,
+			Status:           domain.StatusDraft,
+			Content:          content,
+			CreatedAt:        admitted,
+			LastModified:     discharged,
+		},
+		Hospital: hospital,
+		Doctor:   &domain.User{FirstName: "Elena", LastName: "Ionescu", Specialty: string(domain.SpecialtyCardiology)},
+	}
+}
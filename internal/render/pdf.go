@@ -0,0 +1,481 @@
+package render
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+//go:embed fonts/DejaVuSansCondensed.ttf
+var regularFont []byte
+
+//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+var boldFont []byte
+
+const (
+	fontFamily = "DejaVu"
+	lineHeight = 5.0
+	margin     = 15.0
+)
+
+// PDF writes the report as an A4 letter: hospital letterhead, patient header,
+// the sections of the report type and the signature block
+func PDF(w io.Writer, doc *Document) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", boldFont)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.SetTitle(doc.Report.ReportType.Title(), true)
+	pdf.SetCreator(doc.Hospital.Name, true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Pagina %d/{nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+	pdf.AddPage()
+
+	p := &pdfWriter{pdf: pdf}
+	p.letterhead(doc)
+	p.title(doc)
+	p.patientHeader(doc.Report.Content.PatientData)
+
+	for _, section := range doc.Report.Content.Sections(doc.Report.ReportType) {
+		if write, ok := pdfSections[section]; ok {
+			write(p, doc.Report.Content)
+		}
+	}
+
+	p.signature(doc)
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+type pdfWriter struct {
+	pdf *fpdf.Fpdf
+}
+
+// pdfSections writes each content section in the order of the content model
+var pdfSections = map[string]func(p *pdfWriter, c domain.ReportContent){
+	"anamnesis":                 (*pdfWriter).anamnesis,
+	"examination":               (*pdfWriter).examination,
+	"lab_results":               (*pdfWriter).labResults,
+	"diagnosis":                 (*pdfWriter).diagnosis,
+	"treatment":                 (*pdfWriter).treatment,
+	"recommendations":           (*pdfWriter).recommendations,
+	"medication_reconciliation": (*pdfWriter).reconciliation,
+	"operative_note":            (*pdfWriter).operativeNote,
+	"transfer":                  (*pdfWriter).transfer,
+}
+
+func (p *pdfWriter) letterhead(doc *Document) {
+	h := doc.Hospital
+	textX := margin
+
+	// A logo the PDF writer cannot read is left out rather than failing the
+	// whole document
+	if len(doc.Logo) > 0 && p.pdf.Ok() {
+		imageType := "PNG"
+		if doc.LogoType == "image/jpeg" {
+			imageType = "JPG"
+		}
+		options := fpdf.ImageOptions{ImageType: imageType}
+		info := p.pdf.RegisterImageOptionsReader("logo", options, bytes.NewReader(doc.Logo))
+		if !p.pdf.Ok() {
+			p.pdf.ClearError()
+		} else if info != nil && info.Height() > 0 {
+			const logoHeight = 20.0
+			logoWidth := info.Width() * logoHeight / info.Height()
+			p.pdf.ImageOptions("logo", margin, margin, logoWidth, logoHeight, false, options, 0, "")
+			textX += logoWidth + 5
+		}
+	}
+
+	p.pdf.SetXY(textX, margin)
+	p.pdf.SetFont(fontFamily, "B", 12)
+	p.pdf.CellFormat(0, 6, h.Name, "", 2, "L", false, 0, "")
+	p.pdf.SetFont(fontFamily, "", 9)
+	lines := []string{
+		h.Address.String(),
+		JoinNonEmpty("  ·  ", prefixed("CUI ", h.CUI), prefixed("Tel. ", h.Phone), prefixed("E-mail ", h.Email)),
+	}
+	for _, line := range lines {
+		if line != "" {
+			p.pdf.CellFormat(0, 4.5, line, "", 2, "L", false, 0, "")
+		}
+	}
+
+	y := p.pdf.GetY()
+	if y < margin+22 {
+		y = margin + 22
+	}
+	p.pdf.SetLineWidth(0.4)
+	p.pdf.Line(margin, y, p.pageWidth()+margin, y)
+	p.pdf.SetLineWidth(0.2)
+	p.pdf.SetXY(margin, y+4)
+}
+
+func (p *pdfWriter) title(doc *Document) {
+	p.pdf.SetFont(fontFamily, "B", 14)
+	p.pdf.CellFormat(0, 8, strings.ToUpper(doc.Report.ReportType.Title()), "", 1, "C", false, 0, "")
+	p.pdf.SetFont(fontFamily, "", 9)
+	p.pdf.CellFormat(0, 4.5, "Nr. "+strings.ToUpper(doc.Report.ID.String()[:8]), "", 1, "C", false, 0, "")
+
+	if !doc.Signed() {
+		p.pdf.SetTextColor(180, 0, 0)
+		p.pdf.SetFont(fontFamily, "B", 9)
+		p.pdf.CellFormat(0, 5, "CIORNĂ – document nesemnat", "", 1, "C", false, 0, "")
+		p.pdf.SetTextColor(0, 0, 0)
+	}
+	p.pdf.Ln(3)
+}
+
+func (p *pdfWriter) patientHeader(patient domain.PatientDataSection) {
+	p.field("Pacient", PatientName(patient))
+	p.field("CNP", patient.CNP)
+	p.field("Data nașterii", FormatDate(patient.BirthDate))
+	p.field("Secția", Location(patient))
+	p.field("Perioada internării", JoinNonEmpty(" – ", FormatDate(patient.AdmissionDate), FormatDate(patient.DischargeDate)))
+	p.pdf.Ln(2)
+}
+
+func (p *pdfWriter) anamnesis(c domain.ReportContent) {
+	a := c.Anamnesis
+	p.heading("Anamneză")
+	p.field("Motivele internării", a.ChiefComplaint)
+	p.field("Istoricul bolii", a.HistoryOfPresentIllness)
+	p.field("Antecedente personale patologice", a.PastMedicalHistory)
+	p.field("Alergii", Allergies(a))
+	p.field("Condiții de viață și muncă", a.SocialHistory)
+}
+
+func (p *pdfWriter) examination(c domain.ReportContent) {
+	e := c.Examination
+	p.heading("Examen clinic")
+	p.field("Stare generală", e.GeneralCondition)
+	p.field("Stare de conștiență", e.Consciousness)
+	p.field("Semne vitale", VitalSigns(e.VitalSigns))
+	p.field("Examen pe aparate și sisteme", e.SystemsReview)
+}
+
+func (p *pdfWriter) labResults(c domain.ReportContent) {
+	lab := c.LabResults
+	if len(lab.LaboratoryTests) == 0 && len(lab.ImagingStudies) == 0 {
+		return
+	}
+	p.heading("Investigații paraclinice")
+
+	if len(lab.LaboratoryTests) > 0 {
+		rows := make([][]string, len(lab.LaboratoryTests))
+		for i, t := range lab.LaboratoryTests {
+			name := t.Name
+			if t.IsAbnormal() {
+				name += " *"
+			}
+			rows[i] = []string{name, LabResult(t), LabRange(t), FormatDate(t.Date)}
+		}
+		p.table([]string{"Analiză", "Rezultat", "Interval de referință", "Data"}, []float64{70, 40, 45, 25}, rows)
+		if hasAbnormal(lab.LaboratoryTests) {
+			p.note("* valoare în afara intervalului de referință")
+		}
+	}
+
+	for _, study := range lab.ImagingStudies {
+		p.field(JoinNonEmpty(" ", study.Type, FormatDate(study.Date)), study.Description)
+	}
+}
+
+func hasAbnormal(tests []domain.LabTest) bool {
+	for _, t := range tests {
+		if t.IsAbnormal() {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pdfWriter) diagnosis(c domain.ReportContent) {
+	d := c.Diagnosis
+	p.heading("Diagnostic")
+	p.field("Diagnostic principal", Diagnosis(d.PrimaryDiagnosis))
+	if len(d.SecondaryDiagnoses) > 0 {
+		secondary := make([]string, len(d.SecondaryDiagnoses))
+		for i, code := range d.SecondaryDiagnoses {
+			secondary[i] = Diagnosis(code)
+		}
+		p.field("Diagnostice secundare", strings.Join(secondary, "; "))
+	}
+	p.field("Observații clinice", d.ClinicalObservations)
+}
+
+func (p *pdfWriter) treatment(c domain.ReportContent) {
+	t := c.Treatment
+	if len(t.Medications) == 0 && len(t.Procedures) == 0 {
+		return
+	}
+	p.heading("Tratament efectuat")
+	p.medications(t.Medications)
+
+	for _, procedure := range t.Procedures {
+		label := JoinNonEmpty(" ", procedure.Code, procedure.Name, FormatDate(procedure.PerformedAt))
+		p.field(label, procedure.Description)
+	}
+}
+
+func (p *pdfWriter) medications(medications []domain.Medication) {
+	if len(medications) == 0 {
+		return
+	}
+	rows := make([][]string, len(medications))
+	for i, m := range medications {
+		rows[i] = []string{m.Name, MedicationDose(m), m.Route, MedicationPeriod(m)}
+	}
+	p.table([]string{"Medicament", "Doză și frecvență", "Cale", "Perioada"}, []float64{55, 65, 20, 40}, rows)
+}
+
+func (p *pdfWriter) recommendations(c domain.ReportContent) {
+	r := c.Recommendations
+	p.heading("Recomandări la externare")
+	p.field("Plan", r.DischargePlan)
+	p.field("Tratament", r.Medications)
+	p.medications(c.MedicationReconciliation.Discharge)
+	p.field("Control", r.FollowUp)
+	p.field("Regim alimentar", r.DietRestrictions)
+	p.field("Activitate fizică", r.ActivityRestrictions)
+}
+
+func (p *pdfWriter) reconciliation(c domain.ReportContent) {
+	table := c.ReconciliationTable()
+	if len(table) == 0 {
+		return
+	}
+	p.heading("Reconcilierea medicației")
+
+	rows := make([][]string, len(table))
+	for i, row := range table {
+		status := row.Status.Label()
+		if row.Reason != "" {
+			status += ": " + row.Reason
+		}
+		rows[i] = []string{row.Medication, row.PreAdmission, row.InHospital, row.Discharge, status}
+	}
+	p.table([]string{"Medicament", "Înainte de internare", "În spital", "La externare", "Status"}, []float64{34, 36, 36, 36, 38}, rows)
+}
+
+func (p *pdfWriter) operativeNote(c domain.ReportContent) {
+	n := c.OperativeNote
+	if n == nil {
+		return
+	}
+	p.heading("Protocol operator")
+	p.field("Diagnostic preoperator", Diagnosis(n.PreOpDiagnosis))
+	p.field("Diagnostic postoperator", Diagnosis(n.PostOpDiagnosis))
+
+	procedures := make([]string, len(n.ProcedureCodes))
+	for i, code := range n.ProcedureCodes {
+		procedures[i] = JoinNonEmpty(" ", code.Code, code.Description)
+	}
+	p.field("Intervenție", strings.Join(procedures, "; "))
+	p.field("Operator", n.Surgeon)
+	p.field("Ajutoare", strings.Join(n.Assistants, ", "))
+	p.field("Anestezie", JoinNonEmpty(", ", n.AnesthesiaType.Label(), n.Anesthesiologist))
+	p.field("Incizie", n.Incision)
+	p.field("Constatări intraoperatorii", n.Findings)
+	for _, specimen := range n.Specimens {
+		p.field("Piesă trimisă", JoinNonEmpty(" → ", specimen.Description, specimen.SentTo))
+	}
+	if n.EstimatedBloodLossML != nil {
+		p.field("Pierderi de sânge estimate", fmt.Sprintf("%d ml", *n.EstimatedBloodLossML))
+	}
+	for _, implant := range n.Implants {
+		p.field("Implant", JoinNonEmpty(", ", implant.Name, implant.Manufacturer, prefixed("lot ", implant.LotNumber), prefixed("serie ", implant.SerialNumber)))
+	}
+	p.field("Complicații", n.Complications)
+}
+
+func (p *pdfWriter) transfer(c domain.ReportContent) {
+	t := c.Transfer
+	if t == nil {
+		return
+	}
+	p.heading("Transfer")
+	p.field("Din secția", JoinNonEmpty(", ", t.Source.Department, t.Source.Hospital))
+	p.field("În secția", JoinNonEmpty(", ", t.Destination.Department, t.Destination.Hospital))
+	p.field("Motivul transferului", t.Reason)
+	p.field("Starea actuală", t.CurrentStatus)
+	for _, investigation := range t.PendingInvestigations {
+		p.field("Investigație în așteptare", JoinNonEmpty(", ", investigation.Name, investigation.Notes))
+	}
+	for _, task := range t.HandoffTasks {
+		due := ""
+		if task.DueAt != nil {
+			due = "până la " + FormatDateTime(*task.DueAt)
+		}
+		p.field("De efectuat", JoinNonEmpty(", ", task.Description, due))
+	}
+}
+
+func (p *pdfWriter) signature(doc *Document) {
+	_, pageHeight := p.pdf.GetPageSize()
+	if p.pdf.GetY() > pageHeight-60 {
+		p.pdf.AddPage()
+	}
+	p.pdf.Ln(8)
+
+	y := p.pdf.GetY()
+	p.pdf.SetFont(fontFamily, "", 10)
+	p.pdf.CellFormat(80, lineHeight, "Data: "+FormatDate(doc.Date()), "", 0, "L", false, 0, "")
+
+	x := margin + p.pageWidth() - 75
+	p.pdf.SetXY(x, y)
+	p.pdf.CellFormat(75, lineHeight, "Medic curant,", "", 2, "C", false, 0, "")
+	p.pdf.SetFont(fontFamily, "B", 10)
+	p.pdf.CellFormat(75, lineHeight, DoctorName(doc.Doctor), "", 2, "C", false, 0, "")
+	p.pdf.SetFont(fontFamily, "", 9)
+	if doc.Doctor != nil && doc.Doctor.Specialty != "" {
+		p.pdf.CellFormat(75, lineHeight, domain.Specialty(doc.Doctor.Specialty).DepartmentName(), "", 2, "C", false, 0, "")
+	}
+	p.pdf.Ln(12)
+	p.pdf.SetX(x)
+	p.pdf.CellFormat(75, lineHeight, "Semnătura și parafa", "T", 1, "C", false, 0, "")
+
+	if doc.Verifiable() {
+		p.verification(doc, y+lineHeight+2, x-margin-5)
+	}
+}
+
+// verification prints the QR code linking to the public verification page,
+// with the code and content hash for readers who type them in
+func (p *pdfWriter) verification(doc *Document, y, width float64) {
+	const qrSide = 24.0
+
+	png, err := doc.VerificationQR()
+	if err != nil {
+		p.pdf.SetError(err)
+		return
+	}
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	p.pdf.RegisterImageOptionsReader("verification", options, bytes.NewReader(png))
+	p.pdf.ImageOptions("verification", margin, y, qrSide, qrSide, false, options, 0, "")
+
+	textX := margin + qrSide + 2
+	p.pdf.SetXY(textX, y+1)
+	p.pdf.SetFont(fontFamily, "", 7)
+	signature := doc.Report.Signature
+	for _, line := range []string{
+		"Verificați autenticitatea documentului:",
+		doc.VerificationURL,
+		"Cod: " + VerificationCode(signature.Token),
+		"Amprentă SHA-256: " + ShortHash(signature.ContentHash),
+	} {
+		p.pdf.SetX(textX)
+		p.pdf.MultiCell(width-qrSide-2, 3.2, line, "", "L", false)
+	}
+
+	if bottom := y + qrSide; p.pdf.GetY() < bottom {
+		p.pdf.SetY(bottom)
+	}
+}
+
+func (p *pdfWriter) heading(text string) {
+	p.pdf.Ln(2)
+	p.pdf.SetFont(fontFamily, "B", 11)
+	p.pdf.CellFormat(0, 6, text, "B", 1, "L", false, 0, "")
+	p.pdf.Ln(1)
+}
+
+// field writes "Label: value", wrapping the value; empty values are skipped
+func (p *pdfWriter) field(label, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	p.pdf.SetFont(fontFamily, "B", 10)
+	p.pdf.Write(lineHeight, label+": ")
+	p.pdf.SetFont(fontFamily, "", 10)
+	p.pdf.Write(lineHeight, value)
+	p.pdf.Ln(lineHeight + 1)
+}
+
+func (p *pdfWriter) note(text string) {
+	p.pdf.SetFont(fontFamily, "", 8)
+	p.pdf.CellFormat(0, 4, text, "", 1, "L", false, 0, "")
+}
+
+// table writes a bordered table whose cells wrap; widths are scaled to the
+// page width
+func (p *pdfWriter) table(headers []string, widths []float64, rows [][]string) {
+	total := 0.0
+	for _, w := range widths {
+		total += w
+	}
+	scaled := make([]float64, len(widths))
+	for i, w := range widths {
+		scaled[i] = w * p.pageWidth() / total
+	}
+
+	p.pdf.SetFont(fontFamily, "B", 9)
+	p.tableRow(scaled, headers, true)
+	p.pdf.SetFont(fontFamily, "", 9)
+	for _, row := range rows {
+		p.tableRow(scaled, row, false)
+	}
+	p.pdf.Ln(2)
+}
+
+func (p *pdfWriter) tableRow(widths []float64, cells []string, header bool) {
+	const cellLine = 4.5
+
+	lines := make([][]string, len(cells))
+	count := 1
+	for i, cell := range cells {
+		lines[i] = p.pdf.SplitText(cell, widths[i])
+		if len(lines[i]) > count {
+			count = len(lines[i])
+		}
+	}
+	height := float64(count)*cellLine + 1.5
+
+	_, pageHeight := p.pdf.GetPageSize()
+	if p.pdf.GetY()+height > pageHeight-20 {
+		p.pdf.AddPage()
+	}
+
+	x, y := margin, p.pdf.GetY()
+	style := "D"
+	if header {
+		p.pdf.SetFillColor(235, 235, 235)
+		style = "FD"
+	}
+	for i := range cells {
+		p.pdf.Rect(x, y, widths[i], height, style)
+		for j, line := range lines[i] {
+			p.pdf.SetXY(x, y+0.75+float64(j)*cellLine)
+			p.pdf.CellFormat(widths[i], cellLine, line, "", 0, "L", false, 0, "")
+		}
+		x += widths[i]
+	}
+	p.pdf.SetXY(margin, y+height)
+}
+
+func (p *pdfWriter) pageWidth() float64 {
+	width, _ := p.pdf.GetPageSize()
+	return width - 2*margin
+}
+
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
@@ -0,0 +1,40 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// qrSize is the side of the QR code image in pixels
+const qrSize = 256
+
+// Verifiable reports whether the document carries a verification QR code
+func (d *Document) Verifiable() bool {
+	return d.Signed() && d.Report.Signature != nil && d.VerificationURL != ""
+}
+
+// VerificationQR is the verification link as a PNG QR code
+func (d *Document) VerificationQR() ([]byte, error) {
+	return qrcode.Encode(d.VerificationURL, qrcode.Medium, qrSize)
+}
+
+// VerificationCode groups the verification token by four characters, for
+// typing it from paper (ABCD-EFGH-IJKL-MNOP)
+func VerificationCode(token string) string {
+	var groups []string
+	for len(token) > 4 {
+		groups = append(groups, token[:4])
+		token = token[4:]
+	}
+	return strings.Join(append(groups, token), "-")
+}
+
+// ShortHash is the beginning of the content hash, printed next to the QR
+// code to compare with the hash the verification page shows
+func ShortHash(hash string) string {
+	if len(hash) > 16 {
+		return hash[:16]
+	}
+	return hash
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tudormiron/medical-reports/internal/domain"
@@ -11,9 +12,16 @@ import (
 type ReportRepository interface {
 	Create(ctx context.Context, report *domain.Report) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Report, error)
+	GetByVerificationToken(ctx context.Context, token string) (*domain.Report, error)
 	Update(ctx context.Context, report *domain.Report) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, doctorID uuid.UUID, status domain.Status, limit, offset int) ([]*domain.Report, error)
+	ListByPatient(ctx context.Context, patientID uuid.UUID) ([]*domain.Report, error)
+	ListByEncounter(ctx context.Context, encounterID uuid.UUID) ([]*domain.Report, error)
+	UpdatePatientIdentity(ctx context.Context, patient *domain.Patient) error
+	
+	// Statistics
+	CountPrimaryDiagnosesByBlock(ctx context.Context, hospitalID uuid.UUID, from, to *time.Time) ([]domain.ICD10BlockStatistic, error)
 	
 	// Version management
 	SaveVersion(ctx context.Context, version *domain.ReportVersion) error
@@ -21,11 +29,122 @@ type ReportRepository interface {
 	GetVersion(ctx context.Context, reportID uuid.UUID, versionNumber int) (*domain.ReportVersion, error)
 }
 
-// ReferenceRepository defines interface for reference data (ICD-10, medications)
+// ReferenceRepository defines interface for reference data (ICD-10, medications, lab tests, procedures)
 type ReferenceRepository interface {
-	SearchICD10(ctx context.Context, query string, limit int) ([]domain.ICD10Reference, error)
+	SearchICD10(ctx context.Context, query, chapterCode string, limit int) ([]domain.ICD10Reference, error)
 	GetICD10ByCode(ctx context.Context, code string) (*domain.ICD10Reference, error)
+	ListICD10Chapters(ctx context.Context) ([]domain.ICD10Node, error)
+	GetICD10Children(ctx context.Context, code string) ([]domain.ICD10Node, error)
+	GetICD10Ancestors(ctx context.Context, code string) ([]domain.ICD10Node, error)
+	ImportICD10Release(ctx context.Context, release *domain.ICD10Release) (*domain.ICD10ImportSummary, error)
 	
-	SearchMedications(ctx context.Context, query string, limit int) ([]domain.MedicationReference, error)
+	SearchMedications(ctx context.Context, search domain.MedicationSearch, limit int) ([]domain.MedicationReference, error)
 	GetMedicationByID(ctx context.Context, id uuid.UUID) (*domain.MedicationReference, error)
+	FindMedicationByName(ctx context.Context, name string) (*domain.MedicationReference, error)
+	FindMedicationsByNames(ctx context.Context, names []string) (map[string]*domain.MedicationReference, error)
+	UpsertMedications(ctx context.Context, products []domain.MedicationReference) (*domain.MedicationImportSummary, error)
+	
+	SearchLabTests(ctx context.Context, query string, limit int) ([]domain.LabTestReference, error)
+	GetLabTestByCode(ctx context.Context, loincCode string) (*domain.LabTestReference, error)
+	UpsertLabTests(ctx context.Context, tests []domain.LabTestReference) error
+	
+	SearchProcedures(ctx context.Context, query string, limit int) ([]domain.ProcedureReference, error)
+	GetProcedureByCode(ctx context.Context, code string) (*domain.ProcedureReference, error)
+	UpsertProcedures(ctx context.Context, procedures []domain.ProcedureReference) error
+}
+
+// PatientRepository defines persistence for the patient registry. Every
+// lookup is scoped to a hospital.
+type PatientRepository interface {
+	Create(ctx context.Context, patient *domain.Patient) error
+	Delete(ctx context.Context, hospitalID, id uuid.UUID) error
+	GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Patient, error)
+	GetByCNP(ctx context.Context, hospitalID uuid.UUID, cnp string) (*domain.Patient, error)
+	Update(ctx context.Context, patient *domain.Patient) error
+	Search(ctx context.Context, hospitalID uuid.UUID, search domain.PatientSearch, limit, offset int) ([]*domain.Patient, error)
+	ListActive(ctx context.Context, hospitalID uuid.UUID) ([]*domain.Patient, error)
+	ListHospitalIDs(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// HospitalRepository defines persistence for hospital master data and
+// letterhead logos
+type HospitalRepository interface {
+	Create(ctx context.Context, hospital *domain.Hospital) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Hospital, error)
+	List(ctx context.Context) ([]*domain.Hospital, error)
+	Update(ctx context.Context, hospital *domain.Hospital) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetLogo(ctx context.Context, id uuid.UUID) ([]byte, string, error)
+	SetLogo(ctx context.Context, id uuid.UUID, logo []byte, contentType string) error
+}
+
+// DepartmentRepository defines persistence for the departments of a hospital
+type DepartmentRepository interface {
+	Create(ctx context.Context, department *domain.Department) error
+	GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Department, error)
+	ListByHospital(ctx context.Context, hospitalID uuid.UUID) ([]*domain.Department, error)
+	Update(ctx context.Context, department *domain.Department) error
+	Delete(ctx context.Context, hospitalID, id uuid.UUID) error
+}
+
+// EncounterRepository defines persistence for admission episodes and their
+// department stays. Every lookup is scoped to a hospital.
+type EncounterRepository interface {
+	Create(ctx context.Context, encounter *domain.Encounter) error
+	GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Encounter, error)
+	ListByPatient(ctx context.Context, hospitalID, patientID uuid.UUID) ([]*domain.Encounter, error)
+	Update(ctx context.Context, encounter *domain.Encounter) error
+	Delete(ctx context.Context, hospitalID, id uuid.UUID) error
+}
+
+// TemplateRepository defines persistence for versioned report templates.
+// Every change to a template is stored as a new version.
+type TemplateRepository interface {
+	Create(ctx context.Context, template *domain.ReportTemplate) error
+	Update(ctx context.Context, template *domain.ReportTemplate) error
+	SetActive(ctx context.Context, hospitalID, id uuid.UUID, active bool) error
+	GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.ReportTemplate, error)
+	GetVersion(ctx context.Context, hospitalID, id uuid.UUID, version int) (*domain.ReportTemplate, error)
+	List(ctx context.Context, hospitalID uuid.UUID, includeInactive bool) ([]*domain.ReportTemplate, error)
+	ListVersions(ctx context.Context, hospitalID, id uuid.UUID) ([]*domain.ReportTemplate, error)
+}
+
+// PrintLayoutRepository defines persistence for the print layouts of a
+// hospital, one per report type
+type PrintLayoutRepository interface {
+	Get(ctx context.Context, hospitalID uuid.UUID, reportType domain.ReportType) (*domain.PrintLayout, error)
+	List(ctx context.Context, hospitalID uuid.UUID) ([]*domain.PrintLayout, error)
+	Save(ctx context.Context, layout *domain.PrintLayout) error
+	Delete(ctx context.Context, hospitalID uuid.UUID, reportType domain.ReportType) error
+}
+
+// HandoffRepository defines persistence for the receiving department queue
+// of signed transfer summaries
+type HandoffRepository interface {
+	Create(ctx context.Context, handoff *domain.Handoff) error
+	GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Handoff, error)
+	ListByDepartment(ctx context.Context, hospitalID, departmentID uuid.UUID, status domain.HandoffStatus, limit, offset int) ([]*domain.Handoff, error)
+	CountByDepartment(ctx context.Context, hospitalID, departmentID uuid.UUID, status domain.HandoffStatus) (int, error)
+	Update(ctx context.Context, handoff *domain.Handoff) error
+}
+
+// DuplicateRepository defines persistence for the duplicate patient review
+// queue and for merges. Merge and Unmerge move reports atomically.
+type DuplicateRepository interface {
+	SaveCandidates(ctx context.Context, candidates []*domain.DuplicateCandidate) (int, error)
+	GetCandidate(ctx context.Context, hospitalID, id uuid.UUID) (*domain.DuplicateCandidate, error)
+	ListCandidates(ctx context.Context, hospitalID uuid.UUID, status domain.DuplicateStatus, limit, offset int) ([]*domain.DuplicateCandidate, error)
+	UpdateCandidateStatus(ctx context.Context, candidate *domain.DuplicateCandidate) error
+	
+	Merge(ctx context.Context, merge *domain.PatientMerge) error
+	Unmerge(ctx context.Context, merge *domain.PatientMerge) error
+	GetMerge(ctx context.Context, hospitalID, id uuid.UUID) (*domain.PatientMerge, error)
+	ListMerges(ctx context.Context, hospitalID uuid.UUID, limit, offset int) ([]*domain.PatientMerge, error)
+}
+
+// AuditRepository defines persistence for the append-only audit log
+type AuditRepository interface {
+	Log(ctx context.Context, entry *domain.AuditEntry) error
+	ListByReport(ctx context.Context, reportID uuid.UUID) ([]*domain.AuditEntry, error)
+	ListByPatient(ctx context.Context, patientID uuid.UUID) ([]*domain.AuditEntry, error)
 }
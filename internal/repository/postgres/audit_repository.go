@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Log(ctx context.Context, entry *domain.AuditEntry) error {
+	data, err := json.Marshal(entry.EventData)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_log (id, report_id, patient_id, event_type, event_data, user_id, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	// Patient-level entries may have no report
+	reportID := uuid.NullUUID{UUID: entry.ReportID, Valid: entry.ReportID != uuid.Nil}
+
+	_, err = r.db.ExecContext(ctx, query,
+		entry.ID,
+		reportID,
+		entry.PatientID,
+		entry.EventType,
+		data,
+		entry.UserID,
+		entry.Timestamp,
+	)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) ListByReport(ctx context.Context, reportID uuid.UUID) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT id, report_id, patient_id, event_type, event_data, user_id, timestamp
+		FROM audit_log
+		WHERE report_id = $1
+		ORDER BY timestamp DESC
+	`
+
+	return r.queryEntries(ctx, query, reportID)
+}
+
+func (r *AuditRepository) ListByPatient(ctx context.Context, patientID uuid.UUID) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT id, report_id, patient_id, event_type, event_data, user_id, timestamp
+		FROM audit_log
+		WHERE patient_id = $1
+		ORDER BY timestamp DESC
+	`
+
+	return r.queryEntries(ctx, query, patientID)
+}
+
+func (r *AuditRepository) queryEntries(ctx context.Context, query string, args ...interface{}) ([]*domain.AuditEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var reportID, patientID uuid.NullUUID
+		var data []byte
+		if err := rows.Scan(&entry.ID, &reportID, &patientID, &entry.EventType, &data, &entry.UserID, &entry.Timestamp); err != nil {
+			return nil, err
+		}
+		entry.ReportID = reportID.UUID
+		if patientID.Valid {
+			entry.PatientID = &patientID.UUID
+		}
+		if err := json.Unmarshal(data, &entry.EventData); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
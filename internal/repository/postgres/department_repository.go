@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type DepartmentRepository struct {
+	db *sql.DB
+}
+
+func NewDepartmentRepository(db *sql.DB) *DepartmentRepository {
+	return &DepartmentRepository{db: db}
+}
+
+const departmentColumns = `id, hospital_id, code, name, specialty, created_at, updated_at`
+
+func (r *DepartmentRepository) Create(ctx context.Context, department *domain.Department) error {
+	query := `
+		INSERT INTO departments (id, hospital_id, code, name, specialty, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		department.ID,
+		department.HospitalID,
+		department.Code,
+		department.Name,
+		nullIfEmpty(string(department.Specialty)),
+		department.CreatedAt,
+		department.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDepartmentCodeExists
+		}
+		if isForeignKeyViolation(err) {
+			return domain.ErrHospitalNotFound
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	return nil
+}
+
+func (r *DepartmentRepository) GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Department, error) {
+	query := `SELECT ` + departmentColumns + ` FROM departments WHERE hospital_id = $1 AND id = $2`
+
+	department, err := scanDepartment(r.db.QueryRowContext(ctx, query, hospitalID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrDepartmentNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return department, nil
+}
+
+func (r *DepartmentRepository) ListByHospital(ctx context.Context, hospitalID uuid.UUID) ([]*domain.Department, error) {
+	query := `SELECT ` + departmentColumns + ` FROM departments WHERE hospital_id = $1 ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, hospitalID)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	departments := []*domain.Department{}
+	for rows.Next() {
+		department, err := scanDepartment(rows)
+		if err != nil {
+			return nil, err
+		}
+		departments = append(departments, department)
+	}
+
+	return departments, rows.Err()
+}
+
+func (r *DepartmentRepository) Update(ctx context.Context, department *domain.Department) error {
+	query := `
+		UPDATE departments
+		SET code = $3, name = $4, specialty = $5, updated_at = $6
+		WHERE hospital_id = $1 AND id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		department.HospitalID,
+		department.ID,
+		department.Code,
+		department.Name,
+		nullIfEmpty(string(department.Specialty)),
+		department.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDepartmentCodeExists
+		}
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrDepartmentNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a department no encounter stay or template references
+func (r *DepartmentRepository) Delete(ctx context.Context, hospitalID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM departments WHERE hospital_id = $1 AND id = $2`, hospitalID, id)
+	if err != nil {
+		if isForeignKeyViolation(err) && violatedConstraint(err) == "report_templates_department_id_fkey" {
+			return fmt.Errorf("%w: report templates are assigned to it", domain.ErrDepartmentInUse)
+		}
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("%w: encounter stays reference it", domain.ErrDepartmentInUse)
+		}
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrDepartmentNotFound
+	}
+
+	return nil
+}
+
+func scanDepartment(row interface{ Scan(...interface{}) error }) (*domain.Department, error) {
+	var d domain.Department
+	var specialty sql.NullString
+
+	err := row.Scan(
+		&d.ID,
+		&d.HospitalID,
+		&d.Code,
+		&d.Name,
+		&specialty,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Specialty = domain.Specialty(specialty.String)
+	return &d, nil
+}
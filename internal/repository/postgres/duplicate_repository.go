@@ -0,0 +1,463 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type DuplicateRepository struct {
+	db *sql.DB
+}
+
+func NewDuplicateRepository(db *sql.DB) *DuplicateRepository {
+	return &DuplicateRepository{db: db}
+}
+
+const candidateColumns = `
+	id, hospital_id, patient_a_id, patient_b_id, reason, detail,
+	suggested_survivor_id, status, detected_at, reviewed_by, reviewed_at
+`
+
+const mergeColumns = `
+	id, hospital_id, survivor_id, merged_id, candidate_id, report_ids,
+	merged_by, merged_at, reverted_by, reverted_at
+`
+
+// SaveCandidates queues newly detected pairs. Pairs already in the queue keep
+// their review status; pending ones get the latest reason. Returns the number
+// of new candidates.
+func (r *DuplicateRepository) SaveCandidates(ctx context.Context, candidates []*domain.DuplicateCandidate) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	created := 0
+	for _, c := range candidates {
+		var inserted bool
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO patient_duplicate_candidates (
+				id, hospital_id, patient_a_id, patient_b_id, reason, detail,
+				suggested_survivor_id, status, detected_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (patient_a_id, patient_b_id) DO UPDATE
+			SET reason = EXCLUDED.reason,
+			    detail = EXCLUDED.detail,
+			    suggested_survivor_id = EXCLUDED.suggested_survivor_id
+			WHERE patient_duplicate_candidates.status = 'pending'
+			RETURNING xmax = 0
+		`,
+			c.ID,
+			c.HospitalID,
+			c.PatientAID,
+			c.PatientBID,
+			c.Reason,
+			c.Detail,
+			c.SuggestedSurvivorID,
+			c.Status,
+			c.DetectedAt,
+		).Scan(&inserted)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrDatabaseQuery
+		}
+		if inserted {
+			created++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+func (r *DuplicateRepository) GetCandidate(ctx context.Context, hospitalID, id uuid.UUID) (*domain.DuplicateCandidate, error) {
+	query := `SELECT ` + candidateColumns + ` FROM patient_duplicate_candidates WHERE hospital_id = $1 AND id = $2`
+
+	candidate, err := scanCandidate(r.db.QueryRowContext(ctx, query, hospitalID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrDuplicateNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return candidate, nil
+}
+
+func (r *DuplicateRepository) ListCandidates(ctx context.Context, hospitalID uuid.UUID, status domain.DuplicateStatus, limit, offset int) ([]*domain.DuplicateCandidate, error) {
+	query := `
+		SELECT ` + candidateColumns + `
+		FROM patient_duplicate_candidates
+		WHERE hospital_id = $1 AND status = $2
+		ORDER BY detected_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, hospitalID, status, limit, offset)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	candidates := []*domain.DuplicateCandidate{}
+	for rows.Next() {
+		candidate, err := scanCandidate(rows)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+func (r *DuplicateRepository) UpdateCandidateStatus(ctx context.Context, candidate *domain.DuplicateCandidate) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE patient_duplicate_candidates
+		SET status = $3, reviewed_by = $4, reviewed_at = $5
+		WHERE hospital_id = $1 AND id = $2
+	`, candidate.HospitalID, candidate.ID, candidate.Status, candidate.ReviewedBy, candidate.ReviewedAt)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrDuplicateNotFound
+	}
+	return nil
+}
+
+// Merge re-points every report of the merged patient to the survivor, marks
+// the merged record and stores the merge, all in one transaction. The moved
+// report IDs are recorded in merge.ReportIDs.
+func (r *DuplicateRepository) Merge(ctx context.Context, merge *domain.PatientMerge) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, merged_into_id FROM patients
+		WHERE hospital_id = $1 AND id IN ($2, $3)
+		FOR UPDATE
+	`, merge.HospitalID, merge.SurvivorID, merge.MergedID)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	found := 0
+	for rows.Next() {
+		var id uuid.UUID
+		var mergedInto uuid.NullUUID
+		if err := rows.Scan(&id, &mergedInto); err != nil {
+			rows.Close()
+			return err
+		}
+		if mergedInto.Valid {
+			rows.Close()
+			return fmt.Errorf("%w: patient %s was already merged", domain.ErrInvalidMerge, id)
+		}
+		found++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if found != 2 {
+		return domain.ErrPatientNotFound
+	}
+
+	reportIDs, err := moveReports(ctx, tx, `
+		UPDATE reports SET patient_id = $1
+		WHERE patient_id = $2
+		RETURNING id
+	`, merge.SurvivorID, merge.MergedID)
+	if err != nil {
+		return err
+	}
+	merge.ReportIDs = reportIDs
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE encounters SET patient_id = $1 WHERE patient_id = $2
+	`, merge.SurvivorID, merge.MergedID); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE patients SET merged_into_id = $2, updated_at = $3 WHERE id = $1
+	`, merge.MergedID, merge.SurvivorID, merge.MergedAt); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO patient_merges (
+			id, hospital_id, survivor_id, merged_id, candidate_id, report_ids,
+			merged_by, merged_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		merge.ID,
+		merge.HospitalID,
+		merge.SurvivorID,
+		merge.MergedID,
+		merge.CandidateID,
+		pq.Array(uuidStrings(merge.ReportIDs)),
+		merge.MergedBy,
+		merge.MergedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	if merge.CandidateID != nil {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE patient_duplicate_candidates
+			SET status = 'merged', reviewed_by = $2, reviewed_at = $3
+			WHERE id = $1
+		`, *merge.CandidateID, merge.MergedBy, merge.MergedAt); err != nil {
+			return domain.ErrDatabaseQuery
+		}
+	}
+
+	// Other pending pairs of the merged record are moot; a rescan after an
+	// un-merge finds them again
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM patient_duplicate_candidates
+		WHERE status = 'pending' AND (patient_a_id = $1 OR patient_b_id = $1)
+	`, merge.MergedID); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	return tx.Commit()
+}
+
+// Unmerge moves the reports recorded by the merge back to the merged patient
+// and reactivates it. The candidate, if any, is dismissed so that the pair is
+// not queued again.
+func (r *DuplicateRepository) Unmerge(ctx context.Context, merge *domain.PatientMerge) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var revertedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT reverted_at FROM patient_merges WHERE id = $1 FOR UPDATE
+	`, merge.ID).Scan(&revertedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrMergeNotFound
+		}
+		return domain.ErrDatabaseQuery
+	}
+	if revertedAt.Valid {
+		return domain.ErrMergeAlreadyReverted
+	}
+
+	var survivorMergedInto uuid.NullUUID
+	if err := tx.QueryRowContext(ctx, `
+		SELECT merged_into_id FROM patients WHERE id = $1 FOR UPDATE
+	`, merge.SurvivorID).Scan(&survivorMergedInto); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	if survivorMergedInto.Valid {
+		return fmt.Errorf("%w: the surviving patient was merged again; revert that merge first", domain.ErrInvalidMerge)
+	}
+
+	// Reports created for the survivor after the merge stay where they are
+	reportIDs, err := moveReports(ctx, tx, `
+		UPDATE reports SET patient_id = $1
+		WHERE patient_id = $2 AND id = ANY($3::uuid[])
+		RETURNING id
+	`, merge.MergedID, merge.SurvivorID, pq.Array(uuidStrings(merge.ReportIDs)))
+	if err != nil {
+		return err
+	}
+	merge.ReportIDs = reportIDs
+
+	// Encounters follow the reports moved back
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE encounters SET patient_id = $1
+		WHERE patient_id = $2 AND id IN (
+			SELECT encounter_id FROM reports WHERE id = ANY($3::uuid[])
+		)
+	`, merge.MergedID, merge.SurvivorID, pq.Array(uuidStrings(reportIDs))); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE patients SET merged_into_id = NULL, updated_at = $2 WHERE id = $1
+	`, merge.MergedID, merge.RevertedAt); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE patient_merges SET reverted_by = $2, reverted_at = $3 WHERE id = $1
+	`, merge.ID, merge.RevertedBy, merge.RevertedAt); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	if merge.CandidateID != nil {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE patient_duplicate_candidates
+			SET status = 'dismissed', reviewed_by = $2, reviewed_at = $3
+			WHERE id = $1
+		`, *merge.CandidateID, merge.RevertedBy, merge.RevertedAt); err != nil {
+			return domain.ErrDatabaseQuery
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *DuplicateRepository) GetMerge(ctx context.Context, hospitalID, id uuid.UUID) (*domain.PatientMerge, error) {
+	query := `SELECT ` + mergeColumns + ` FROM patient_merges WHERE hospital_id = $1 AND id = $2`
+
+	merge, err := scanMerge(r.db.QueryRowContext(ctx, query, hospitalID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMergeNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return merge, nil
+}
+
+func (r *DuplicateRepository) ListMerges(ctx context.Context, hospitalID uuid.UUID, limit, offset int) ([]*domain.PatientMerge, error) {
+	query := `
+		SELECT ` + mergeColumns + `
+		FROM patient_merges
+		WHERE hospital_id = $1
+		ORDER BY merged_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, hospitalID, limit, offset)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	merges := []*domain.PatientMerge{}
+	for rows.Next() {
+		merge, err := scanMerge(rows)
+		if err != nil {
+			return nil, err
+		}
+		merges = append(merges, merge)
+	}
+
+	return merges, rows.Err()
+}
+
+func moveReports(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func scanCandidate(row interface{ Scan(...interface{}) error }) (*domain.DuplicateCandidate, error) {
+	var c domain.DuplicateCandidate
+	var reviewedBy uuid.NullUUID
+	var reviewedAt sql.NullTime
+
+	err := row.Scan(
+		&c.ID,
+		&c.HospitalID,
+		&c.PatientAID,
+		&c.PatientBID,
+		&c.Reason,
+		&c.Detail,
+		&c.SuggestedSurvivorID,
+		&c.Status,
+		&c.DetectedAt,
+		&reviewedBy,
+		&reviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if reviewedBy.Valid {
+		c.ReviewedBy = &reviewedBy.UUID
+	}
+	if reviewedAt.Valid {
+		c.ReviewedAt = &reviewedAt.Time
+	}
+
+	return &c, nil
+}
+
+func scanMerge(row interface{ Scan(...interface{}) error }) (*domain.PatientMerge, error) {
+	var m domain.PatientMerge
+	var candidateID, revertedBy uuid.NullUUID
+	var revertedAt sql.NullTime
+	var reportIDs pq.StringArray
+
+	err := row.Scan(
+		&m.ID,
+		&m.HospitalID,
+		&m.SurvivorID,
+		&m.MergedID,
+		&candidateID,
+		&reportIDs,
+		&m.MergedBy,
+		&m.MergedAt,
+		&revertedBy,
+		&revertedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidateID.Valid {
+		m.CandidateID = &candidateID.UUID
+	}
+	if revertedBy.Valid {
+		m.RevertedBy = &revertedBy.UUID
+	}
+	if revertedAt.Valid {
+		t := revertedAt.Time
+		m.RevertedAt = &t
+	}
+	m.ReportIDs = make([]uuid.UUID, 0, len(reportIDs))
+	for _, s := range reportIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		m.ReportIDs = append(m.ReportIDs, id)
+	}
+
+	return &m, nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type EncounterRepository struct {
+	db *sql.DB
+}
+
+func NewEncounterRepository(db *sql.DB) *EncounterRepository {
+	return &EncounterRepository{db: db}
+}
+
+const encounterColumns = `
+	id, hospital_id, patient_id, admission_number, status,
+	admitted_at, discharged_at, created_at, updated_at
+`
+
+func (r *EncounterRepository) Create(ctx context.Context, encounter *domain.Encounter) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO encounters (
+			id, hospital_id, patient_id, admission_number, status,
+			admitted_at, discharged_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		encounter.ID,
+		encounter.HospitalID,
+		encounter.PatientID,
+		nullIfEmpty(encounter.AdmissionNumber),
+		encounter.Status,
+		encounter.AdmittedAt,
+		encounter.DischargedAt,
+		encounter.CreatedAt,
+		encounter.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrAdmissionNumberExists
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	if err := saveStays(ctx, tx, encounter); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes an encounter no report references, with its stays. It
+// undoes an admission made for a request that failed.
+func (r *EncounterRepository) Delete(ctx context.Context, hospitalID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM encounters WHERE hospital_id = $1 AND id = $2`, hospitalID, id)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrEncounterNotFound
+	}
+
+	return nil
+}
+
+func (r *EncounterRepository) GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Encounter, error) {
+	query := `SELECT ` + encounterColumns + ` FROM encounters WHERE hospital_id = $1 AND id = $2`
+
+	encounter, err := scanEncounter(r.db.QueryRowContext(ctx, query, hospitalID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrEncounterNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	if err := r.loadStays(ctx, []*domain.Encounter{encounter}); err != nil {
+		return nil, err
+	}
+	return encounter, nil
+}
+
+// ListByPatient returns the patient's encounters, most recent admission first
+func (r *EncounterRepository) ListByPatient(ctx context.Context, hospitalID, patientID uuid.UUID) ([]*domain.Encounter, error) {
+	query := `
+		SELECT ` + encounterColumns + `
+		FROM encounters
+		WHERE hospital_id = $1 AND patient_id = $2
+		ORDER BY admitted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, hospitalID, patientID)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	encounters := []*domain.Encounter{}
+	for rows.Next() {
+		encounter, err := scanEncounter(rows)
+		if err != nil {
+			return nil, err
+		}
+		encounters = append(encounters, encounter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadStays(ctx, encounters); err != nil {
+		return nil, err
+	}
+	return encounters, nil
+}
+
+// Update saves the status, discharge and stays of an encounter
+func (r *EncounterRepository) Update(ctx context.Context, encounter *domain.Encounter) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE encounters
+		SET admission_number = $3, status = $4, discharged_at = $5, updated_at = $6
+		WHERE hospital_id = $1 AND id = $2
+	`,
+		encounter.HospitalID,
+		encounter.ID,
+		nullIfEmpty(encounter.AdmissionNumber),
+		encounter.Status,
+		encounter.DischargedAt,
+		encounter.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrAdmissionNumberExists
+		}
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrEncounterNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM encounter_stays WHERE encounter_id = $1`, encounter.ID); err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	if err := saveStays(ctx, tx, encounter); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func saveStays(ctx context.Context, tx *sql.Tx, encounter *domain.Encounter) error {
+	for _, stay := range encounter.Stays {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO encounter_stays (id, encounter_id, department_id, department, ward, bed, started_at, ended_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`,
+			stay.ID,
+			encounter.ID,
+			stay.DepartmentID,
+			stay.Department,
+			nullIfEmpty(stay.Ward),
+			nullIfEmpty(stay.Bed),
+			stay.From,
+			stay.To,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return domain.ErrDepartmentNotFound
+			}
+			return domain.ErrDatabaseQuery
+		}
+	}
+	return nil
+}
+
+func (r *EncounterRepository) loadStays(ctx context.Context, encounters []*domain.Encounter) error {
+	if len(encounters) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.Encounter, len(encounters))
+	ids := make([]string, len(encounters))
+	for i, e := range encounters {
+		byID[e.ID] = e
+		e.Stays = []domain.EncounterStay{}
+		ids[i] = e.ID.String()
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, encounter_id, department_id, department, ward, bed, started_at, ended_at
+		FROM encounter_stays
+		WHERE encounter_id = ANY($1::uuid[])
+		ORDER BY started_at, ended_at NULLS LAST
+	`, pq.Array(ids))
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stay domain.EncounterStay
+		var encounterID uuid.UUID
+		var departmentID uuid.NullUUID
+		var ward, bed sql.NullString
+		var endedAt sql.NullTime
+		if err := rows.Scan(&stay.ID, &encounterID, &departmentID, &stay.Department, &ward, &bed, &stay.From, &endedAt); err != nil {
+			return err
+		}
+		if departmentID.Valid {
+			stay.DepartmentID = &departmentID.UUID
+		}
+		stay.Ward = ward.String
+		stay.Bed = bed.String
+		if endedAt.Valid {
+			stay.To = &endedAt.Time
+		}
+		if e, ok := byID[encounterID]; ok {
+			e.Stays = append(e.Stays, stay)
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanEncounter(row interface{ Scan(...interface{}) error }) (*domain.Encounter, error) {
+	var e domain.Encounter
+	var admissionNumber sql.NullString
+	var dischargedAt sql.NullTime
+
+	err := row.Scan(
+		&e.ID,
+		&e.HospitalID,
+		&e.PatientID,
+		&admissionNumber,
+		&e.Status,
+		&e.AdmittedAt,
+		&dischargedAt,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e.AdmissionNumber = admissionNumber.String
+	if dischargedAt.Valid {
+		e.DischargedAt = &dischargedAt.Time
+	}
+
+	return &e, nil
+}
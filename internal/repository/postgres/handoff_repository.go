@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type HandoffRepository struct {
+	db *sql.DB
+}
+
+func NewHandoffRepository(db *sql.DB) *HandoffRepository {
+	return &HandoffRepository{db: db}
+}
+
+const handoffColumns = `
+	id, hospital_id, department_id, report_id, source_hospital_id, patient_id,
+	patient_cnp, patient_first_name, patient_last_name, source_department,
+	reason, current_status, pending_investigations, tasks, status,
+	created_at, acknowledged_by, acknowledged_at
+`
+
+// Create queues a handoff; a report is queued at most once
+func (r *HandoffRepository) Create(ctx context.Context, handoff *domain.Handoff) error {
+	investigations, err := json.Marshal(handoff.PendingInvestigations)
+	if err != nil {
+		return err
+	}
+	tasks, err := json.Marshal(handoff.Tasks)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO transfer_handoffs (`+handoffColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (report_id) DO NOTHING
+	`,
+		handoff.ID,
+		handoff.HospitalID,
+		handoff.DepartmentID,
+		handoff.ReportID,
+		handoff.SourceHospitalID,
+		handoff.PatientID,
+		handoff.PatientCNP,
+		handoff.PatientFirstName,
+		handoff.PatientLastName,
+		handoff.SourceDepartment,
+		handoff.Reason,
+		handoff.CurrentStatus,
+		investigations,
+		tasks,
+		handoff.Status,
+		handoff.CreatedAt,
+		handoff.AcknowledgedBy,
+		handoff.AcknowledgedAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrDepartmentNotFound
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	return nil
+}
+
+func (r *HandoffRepository) GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Handoff, error) {
+	query := `SELECT ` + handoffColumns + ` FROM transfer_handoffs WHERE hospital_id = $1 AND id = $2`
+
+	handoff, err := scanHandoff(r.db.QueryRowContext(ctx, query, hospitalID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrHandoffNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return handoff, nil
+}
+
+// ListByDepartment returns the department's queue, oldest first
+func (r *HandoffRepository) ListByDepartment(ctx context.Context, hospitalID, departmentID uuid.UUID, status domain.HandoffStatus, limit, offset int) ([]*domain.Handoff, error) {
+	query := `
+		SELECT ` + handoffColumns + `
+		FROM transfer_handoffs
+		WHERE hospital_id = $1 AND department_id = $2 AND status = $3
+		ORDER BY created_at
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, hospitalID, departmentID, status, limit, offset)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	handoffs := []*domain.Handoff{}
+	for rows.Next() {
+		handoff, err := scanHandoff(rows)
+		if err != nil {
+			return nil, err
+		}
+		handoffs = append(handoffs, handoff)
+	}
+
+	return handoffs, rows.Err()
+}
+
+// CountByDepartment returns the size of the department's queue
+func (r *HandoffRepository) CountByDepartment(ctx context.Context, hospitalID, departmentID uuid.UUID, status domain.HandoffStatus) (int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM transfer_handoffs
+		WHERE hospital_id = $1 AND department_id = $2 AND status = $3
+	`, hospitalID, departmentID, status).Scan(&total)
+	if err != nil {
+		return 0, domain.ErrDatabaseQuery
+	}
+
+	return total, nil
+}
+
+func (r *HandoffRepository) Update(ctx context.Context, handoff *domain.Handoff) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE transfer_handoffs
+		SET status = $3, acknowledged_by = $4, acknowledged_at = $5
+		WHERE hospital_id = $1 AND id = $2
+	`, handoff.HospitalID, handoff.ID, handoff.Status, handoff.AcknowledgedBy, handoff.AcknowledgedAt)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrHandoffNotFound
+	}
+	return nil
+}
+
+func scanHandoff(row interface{ Scan(...interface{}) error }) (*domain.Handoff, error) {
+	var h domain.Handoff
+	var investigations, tasks []byte
+	var acknowledgedBy uuid.NullUUID
+	var acknowledgedAt sql.NullTime
+
+	err := row.Scan(
+		&h.ID,
+		&h.HospitalID,
+		&h.DepartmentID,
+		&h.ReportID,
+		&h.SourceHospitalID,
+		&h.PatientID,
+		&h.PatientCNP,
+		&h.PatientFirstName,
+		&h.PatientLastName,
+		&h.SourceDepartment,
+		&h.Reason,
+		&h.CurrentStatus,
+		&investigations,
+		&tasks,
+		&h.Status,
+		&h.CreatedAt,
+		&acknowledgedBy,
+		&acknowledgedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(investigations, &h.PendingInvestigations); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tasks, &h.Tasks); err != nil {
+		return nil, err
+	}
+	if acknowledgedBy.Valid {
+		h.AcknowledgedBy = &acknowledgedBy.UUID
+	}
+	if acknowledgedAt.Valid {
+		h.AcknowledgedAt = &acknowledgedAt.Time
+	}
+
+	return &h, nil
+}
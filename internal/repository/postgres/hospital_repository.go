@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type HospitalRepository struct {
+	db *sql.DB
+}
+
+func NewHospitalRepository(db *sql.DB) *HospitalRepository {
+	return &HospitalRepository{db: db}
+}
+
+const hospitalColumns = `
+	id, name, cui, siruta, street, city, county, postal_code,
+	phone, email, logo IS NOT NULL, created_at, updated_at
+`
+
+func (r *HospitalRepository) Create(ctx context.Context, hospital *domain.Hospital) error {
+	query := `
+		INSERT INTO hospitals (
+			id, name, cui, siruta, street, city, county, postal_code,
+			phone, email, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		hospital.ID,
+		hospital.Name,
+		nullIfEmpty(hospital.CUI),
+		nullIfEmpty(hospital.SIRUTA),
+		nullIfEmpty(hospital.Address.Street),
+		nullIfEmpty(hospital.Address.City),
+		nullIfEmpty(hospital.Address.County),
+		nullIfEmpty(hospital.Address.PostalCode),
+		nullIfEmpty(hospital.Phone),
+		nullIfEmpty(hospital.Email),
+		hospital.CreatedAt,
+		hospital.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrHospitalCUIExists
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	return nil
+}
+
+func (r *HospitalRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Hospital, error) {
+	query := `SELECT ` + hospitalColumns + ` FROM hospitals WHERE id = $1`
+
+	hospital, err := scanHospital(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrHospitalNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return hospital, nil
+}
+
+func (r *HospitalRepository) List(ctx context.Context) ([]*domain.Hospital, error) {
+	query := `SELECT ` + hospitalColumns + ` FROM hospitals ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	hospitals := []*domain.Hospital{}
+	for rows.Next() {
+		hospital, err := scanHospital(rows)
+		if err != nil {
+			return nil, err
+		}
+		hospitals = append(hospitals, hospital)
+	}
+
+	return hospitals, rows.Err()
+}
+
+func (r *HospitalRepository) Update(ctx context.Context, hospital *domain.Hospital) error {
+	query := `
+		UPDATE hospitals
+		SET name = $2, cui = $3, siruta = $4, street = $5, city = $6, county = $7,
+		    postal_code = $8, phone = $9, email = $10, updated_at = $11
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		hospital.ID,
+		hospital.Name,
+		nullIfEmpty(hospital.CUI),
+		nullIfEmpty(hospital.SIRUTA),
+		nullIfEmpty(hospital.Address.Street),
+		nullIfEmpty(hospital.Address.City),
+		nullIfEmpty(hospital.Address.County),
+		nullIfEmpty(hospital.Address.PostalCode),
+		nullIfEmpty(hospital.Phone),
+		nullIfEmpty(hospital.Email),
+		hospital.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrHospitalCUIExists
+		}
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrHospitalNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a hospital and its departments. Hospitals still referenced by
+// patients, reports or users cannot be deleted.
+func (r *HospitalRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM hospitals WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrHospitalInUse
+		}
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrHospitalNotFound
+	}
+
+	return nil
+}
+
+// GetLogo returns the letterhead logo and its content type
+func (r *HospitalRepository) GetLogo(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
+	var logo []byte
+	var contentType sql.NullString
+
+	err := r.db.QueryRowContext(ctx, `SELECT logo, logo_content_type FROM hospitals WHERE id = $1`, id).Scan(&logo, &contentType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", domain.ErrHospitalNotFound
+		}
+		return nil, "", domain.ErrDatabaseQuery
+	}
+	if logo == nil {
+		return nil, "", domain.ErrLogoNotFound
+	}
+
+	return logo, contentType.String, nil
+}
+
+// SetLogo replaces the letterhead logo; a nil logo removes it
+func (r *HospitalRepository) SetLogo(ctx context.Context, id uuid.UUID, logo []byte, contentType string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE hospitals SET logo = $2, logo_content_type = $3, updated_at = NOW() WHERE id = $1
+	`, id, logo, nullIfEmpty(contentType))
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrHospitalNotFound
+	}
+
+	return nil
+}
+
+func scanHospital(row interface{ Scan(...interface{}) error }) (*domain.Hospital, error) {
+	var h domain.Hospital
+	var cui, siruta, street, city, county, postalCode, phone, email sql.NullString
+
+	err := row.Scan(
+		&h.ID,
+		&h.Name,
+		&cui,
+		&siruta,
+		&street,
+		&city,
+		&county,
+		&postalCode,
+		&phone,
+		&email,
+		&h.HasLogo,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	h.CUI = cui.String
+	h.SIRUTA = siruta.String
+	h.Address = domain.Address{
+		Street:     street.String,
+		City:       city.String,
+		County:     county.String,
+		PostalCode: postalCode.String,
+	}
+	h.Phone = phone.String
+	h.Email = email.String
+
+	return &h, nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+func (r *ReferenceRepository) ListICD10Chapters(ctx context.Context) ([]domain.ICD10Node, error) {
+	query := `
+		SELECT code, title_ro,
+		       EXISTS (SELECT 1 FROM icd10_blocks b WHERE b.chapter_code = icd10_chapters.code)
+		FROM icd10_chapters
+		ORDER BY range_start
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []domain.ICD10Node
+	for rows.Next() {
+		node := domain.ICD10Node{Level: domain.ICD10LevelChapter}
+		if err := rows.Scan(&node.Code, &node.Title, &node.HasChildren); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes, rows.Err()
+}
+
+// GetICD10Children returns the direct children of a chapter, block or code
+func (r *ReferenceRepository) GetICD10Children(ctx context.Context, code string) ([]domain.ICD10Node, error) {
+	level, err := r.icd10Level(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var query string
+	switch level {
+	case domain.ICD10LevelChapter:
+		query = `
+			SELECT code, title_ro, 'block', chapter_code,
+			       EXISTS (SELECT 1 FROM icd10_codes c WHERE c.block_code = icd10_blocks.code AND c.active)
+			FROM icd10_blocks
+			WHERE chapter_code = $1
+			ORDER BY range_start
+		`
+	case domain.ICD10LevelBlock:
+		query = `
+			SELECT code, description_ro, level, block_code,
+			       EXISTS (SELECT 1 FROM icd10_codes c WHERE c.parent_code = icd10_codes.code AND c.active)
+			FROM icd10_codes
+			WHERE block_code = $1 AND level = 'category' AND active
+			ORDER BY code
+		`
+	default:
+		query = `
+			SELECT code, description_ro, level, parent_code,
+			       EXISTS (SELECT 1 FROM icd10_codes c WHERE c.parent_code = icd10_codes.code AND c.active)
+			FROM icd10_codes
+			WHERE parent_code = $1 AND active
+			ORDER BY code
+		`
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := []domain.ICD10Node{}
+	for rows.Next() {
+		var node domain.ICD10Node
+		var parent sql.NullString
+		if err := rows.Scan(&node.Code, &node.Title, &node.Level, &parent, &node.HasChildren); err != nil {
+			return nil, err
+		}
+		node.ParentCode = parent.String
+		nodes = append(nodes, node)
+	}
+
+	return nodes, rows.Err()
+}
+
+// GetICD10Ancestors returns the path from the chapter down to the parent of code
+func (r *ReferenceRepository) GetICD10Ancestors(ctx context.Context, code string) ([]domain.ICD10Node, error) {
+	level, err := r.icd10Level(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []domain.ICD10Node
+
+	switch level {
+	case domain.ICD10LevelChapter:
+		return []domain.ICD10Node{}, nil
+
+	case domain.ICD10LevelBlock:
+		chapter, err := r.icd10ChapterOfBlock(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.ICD10Node{*chapter}, nil
+	}
+
+	ref, err := r.GetICD10ByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk up the parent chain (subcategory → category)
+	for parentCode := ref.ParentCode; parentCode != ""; {
+		parent, err := r.GetICD10ByCode(ctx, parentCode)
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append([]domain.ICD10Node{{
+			Code:        parent.Code,
+			Title:       parent.DescriptionRO,
+			Level:       parent.Level,
+			ParentCode:  parent.ParentCode,
+			HasChildren: true,
+		}}, ancestors...)
+		parentCode = parent.ParentCode
+	}
+
+	if ref.BlockCode == "" {
+		return ancestors, nil
+	}
+
+	var block domain.ICD10Node
+	err = r.db.QueryRowContext(ctx, `
+		SELECT code, title_ro, chapter_code FROM icd10_blocks WHERE code = $1
+	`, ref.BlockCode).Scan(&block.Code, &block.Title, &block.ParentCode)
+	if err != nil {
+		return nil, err
+	}
+	block.Level = domain.ICD10LevelBlock
+	block.HasChildren = true
+
+	chapter, err := r.icd10ChapterOfBlock(ctx, block.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]domain.ICD10Node{*chapter, block}, ancestors...), nil
+}
+
+func (r *ReferenceRepository) icd10ChapterOfBlock(ctx context.Context, blockCode string) (*domain.ICD10Node, error) {
+	chapter := domain.ICD10Node{Level: domain.ICD10LevelChapter, HasChildren: true}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ch.code, ch.title_ro
+		FROM icd10_blocks b
+		JOIN icd10_chapters ch ON ch.code = b.chapter_code
+		WHERE b.code = $1
+	`, blockCode).Scan(&chapter.Code, &chapter.Title)
+	if err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// icd10Level determines which level of the hierarchy a code belongs to
+func (r *ReferenceRepository) icd10Level(ctx context.Context, code string) (domain.ICD10Level, error) {
+	query := `
+		SELECT 'chapter' FROM icd10_chapters WHERE code = $1
+		UNION ALL
+		SELECT 'block' FROM icd10_blocks WHERE code = $1
+		UNION ALL
+		SELECT level FROM icd10_codes WHERE code = $1
+		LIMIT 1
+	`
+
+	var level domain.ICD10Level
+	if err := r.db.QueryRowContext(ctx, query, code).Scan(&level); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrReferenceNotFound
+		}
+		return "", err
+	}
+	return level, nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type existingICD10Code struct {
+	description string
+	active      bool
+}
+
+// ImportICD10Release upserts a full ICD-10 release. Codes missing from the
+// release are marked inactive rather than deleted so that signed reports keep
+// resolving; codes that reappear in a later release are reactivated.
+func (r *ReferenceRepository) ImportICD10Release(ctx context.Context, release *domain.ICD10Release) (*domain.ICD10ImportSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	summary := &domain.ICD10ImportSummary{
+		Version:    release.Version,
+		ImportedAt: time.Now(),
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO icd10_releases (version, source_format, imported_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (version) DO UPDATE
+		SET source_format = EXCLUDED.source_format, imported_at = EXCLUDED.imported_at
+	`, release.Version, release.SourceFormat, summary.ImportedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ch := range release.Chapters {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO icd10_chapters (code, title_ro, range_start, range_end)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (code) DO UPDATE
+			SET title_ro = EXCLUDED.title_ro, range_start = EXCLUDED.range_start, range_end = EXCLUDED.range_end
+		`, ch.Code, ch.Title, ch.RangeStart, ch.RangeEnd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, b := range release.Blocks {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO icd10_blocks (code, chapter_code, title_ro, range_start, range_end)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (code) DO UPDATE
+			SET chapter_code = EXCLUDED.chapter_code, title_ro = EXCLUDED.title_ro,
+			    range_start = EXCLUDED.range_start, range_end = EXCLUDED.range_end
+		`, b.Code, b.ChapterCode, b.Title, b.RangeStart, b.RangeEnd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := loadExistingICD10Codes(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Insert parents before their subdivisions
+	codes := make([]domain.ICD10Reference, len(release.Codes))
+	copy(codes, release.Codes)
+	sort.Slice(codes, func(i, j int) bool {
+		if len(codes[i].Code) != len(codes[j].Code) {
+			return len(codes[i].Code) < len(codes[j].Code)
+		}
+		return codes[i].Code < codes[j].Code
+	})
+
+	inRelease := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		inRelease[code.Code] = true
+
+		prev, found := existing[code.Code]
+		switch {
+		case !found:
+			summary.Added++
+		case !prev.active || prev.description != code.DescriptionRO:
+			summary.Changed++
+		}
+
+		var category sql.NullString
+		if code.Category != "" {
+			category = sql.NullString{String: code.Category, Valid: true}
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO icd10_codes (code, description_ro, category, level, active, release_version, retired_in)
+			VALUES ($1, $2, $3, $4, TRUE, $5, NULL)
+			ON CONFLICT (code) DO UPDATE
+			SET description_ro = EXCLUDED.description_ro,
+			    category = COALESCE(EXCLUDED.category, icd10_codes.category),
+			    level = EXCLUDED.level,
+			    active = TRUE,
+			    release_version = EXCLUDED.release_version,
+			    retired_in = NULL
+		`, code.Code, code.DescriptionRO, category, domain.ICD10LevelOf(code.Code), release.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for code, prev := range existing {
+		if !prev.active || inRelease[code] {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE icd10_codes SET active = FALSE, retired_in = $2 WHERE code = $1
+		`, code, release.Version)
+		if err != nil {
+			return nil, err
+		}
+		summary.Retired++
+	}
+
+	// Link subdivisions to their parents and categories to their blocks
+	for _, code := range codes {
+		parent := domain.ICD10ParentCode(code.Code)
+		if parent == "" || !(inRelease[parent] || hasICD10Code(existing, parent)) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE icd10_codes SET parent_code = $2 WHERE code = $1`, code.Code, parent); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE icd10_codes c
+		SET block_code = b.code, chapter_code = b.chapter_code
+		FROM icd10_blocks b
+		WHERE c.release_version = $1
+		  AND left(c.code, 3) BETWEEN b.range_start AND b.range_end
+	`, release.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE icd10_releases
+		SET added_count = $2, changed_count = $3, retired_count = $4
+		WHERE version = $1
+	`, release.Version, summary.Added, summary.Changed, summary.Retired)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func loadExistingICD10Codes(ctx context.Context, tx *sql.Tx) (map[string]existingICD10Code, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT code, description_ro, active FROM icd10_codes`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]existingICD10Code)
+	for rows.Next() {
+		var code string
+		var entry existingICD10Code
+		if err := rows.Scan(&code, &entry.description, &entry.active); err != nil {
+			return nil, err
+		}
+		existing[code] = entry
+	}
+
+	return existing, rows.Err()
+}
+
+func hasICD10Code(codes map[string]existingICD10Code, code string) bool {
+	_, ok := codes[code]
+	return ok
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+func (r *ReferenceRepository) SearchLabTests(ctx context.Context, query string, limit int) ([]domain.LabTestReference, error) {
+	sqlQuery := `
+		SELECT loinc_code, name_ro, default_unit
+		FROM lab_tests
+		WHERE search_vector @@ plainto_tsquery('romanian', $1)
+		   OR name_ro ILIKE $2
+		   OR loinc_code ILIKE $2
+		ORDER BY 
+			CASE 
+				WHEN loinc_code ILIKE $2 OR name_ro ILIKE $2 THEN 0
+				ELSE 1
+			END,
+			name_ro
+		LIMIT $3
+	`
+
+	searchPattern := query + "%"
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query, searchPattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []domain.LabTestReference
+	var codes []string
+	for rows.Next() {
+		var ref domain.LabTestReference
+		var unit sql.NullString
+		if err := rows.Scan(&ref.LOINCCode, &ref.NameRO, &unit); err != nil {
+			return nil, err
+		}
+		ref.DefaultUnit = unit.String
+		results = append(results, ref)
+		codes = append(codes, ref.LOINCCode)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ranges, err := r.loadLabReferenceRanges(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].ReferenceRanges = ranges[results[i].LOINCCode]
+	}
+
+	return results, nil
+}
+
+func (r *ReferenceRepository) GetLabTestByCode(ctx context.Context, loincCode string) (*domain.LabTestReference, error) {
+	query := `
+		SELECT loinc_code, name_ro, default_unit
+		FROM lab_tests
+		WHERE loinc_code = $1
+	`
+
+	var ref domain.LabTestReference
+	var unit sql.NullString
+	err := r.db.QueryRowContext(ctx, query, loincCode).Scan(&ref.LOINCCode, &ref.NameRO, &unit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReferenceNotFound
+		}
+		return nil, err
+	}
+	ref.DefaultUnit = unit.String
+
+	ranges, err := r.loadLabReferenceRanges(ctx, []string{ref.LOINCCode})
+	if err != nil {
+		return nil, err
+	}
+	ref.ReferenceRanges = ranges[ref.LOINCCode]
+
+	return &ref, nil
+}
+
+// UpsertLabTests inserts or replaces catalog entries together with their reference ranges
+func (r *ReferenceRepository) UpsertLabTests(ctx context.Context, tests []domain.LabTestReference) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, test := range tests {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO lab_tests (loinc_code, name_ro, default_unit)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (loinc_code) DO UPDATE
+			SET name_ro = EXCLUDED.name_ro, default_unit = EXCLUDED.default_unit
+		`, test.LOINCCode, test.NameRO, test.DefaultUnit)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM lab_test_reference_ranges WHERE loinc_code = $1`, test.LOINCCode); err != nil {
+			return err
+		}
+
+		for _, rng := range test.ReferenceRanges {
+			var sex sql.NullString
+			if rng.Sex != domain.SexUnknown {
+				sex = sql.NullString{String: string(rng.Sex), Valid: true}
+			}
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO lab_test_reference_ranges (loinc_code, sex, age_min_years, age_max_years, low, high)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, test.LOINCCode, sex, rng.AgeMinYears, rng.AgeMaxYears, rng.Low, rng.High)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *ReferenceRepository) loadLabReferenceRanges(ctx context.Context, codes []string) (map[string][]domain.LabReferenceRange, error) {
+	ranges := make(map[string][]domain.LabReferenceRange)
+	if len(codes) == 0 {
+		return ranges, nil
+	}
+
+	query := `
+		SELECT loinc_code, sex, age_min_years, age_max_years, low, high
+		FROM lab_test_reference_ranges
+		WHERE loinc_code = ANY($1)
+		ORDER BY loinc_code, sex NULLS LAST, age_min_years NULLS FIRST
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(codes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code string
+		var sex sql.NullString
+		var ageMin, ageMax sql.NullInt64
+		var low, high sql.NullFloat64
+		if err := rows.Scan(&code, &sex, &ageMin, &ageMax, &low, &high); err != nil {
+			return nil, err
+		}
+
+		rng := domain.LabReferenceRange{Sex: domain.Sex(sex.String)}
+		if ageMin.Valid {
+			v := int(ageMin.Int64)
+			rng.AgeMinYears = &v
+		}
+		if ageMax.Valid {
+			v := int(ageMax.Int64)
+			rng.AgeMaxYears = &v
+		}
+		if low.Valid {
+			rng.Low = &low.Float64
+		}
+		if high.Valid {
+			rng.High = &high.Float64
+		}
+		ranges[code] = append(ranges[code], rng)
+	}
+
+	return ranges, rows.Err()
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+// UpsertMedications inserts or updates nomenclature products keyed by their
+// marketing authorization code
+func (r *ReferenceRepository) UpsertMedications(ctx context.Context, products []domain.MedicationReference) (*domain.MedicationImportSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	summary := &domain.MedicationImportSummary{}
+
+	for _, p := range products {
+		if p.AuthorizationCode == "" {
+			summary.Skipped++
+			continue
+		}
+
+		// xmax = 0 only for freshly inserted rows
+		var inserted bool
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO medications (
+				name, active_substance, form, dosage, manufacturer,
+				authorization_code, atc_code, package, prescription_status, max_daily_dose_mg, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW())
+			ON CONFLICT (authorization_code) DO UPDATE
+			SET name = EXCLUDED.name,
+			    active_substance = EXCLUDED.active_substance,
+			    form = EXCLUDED.form,
+			    dosage = EXCLUDED.dosage,
+			    manufacturer = EXCLUDED.manufacturer,
+			    atc_code = EXCLUDED.atc_code,
+			    package = EXCLUDED.package,
+			    prescription_status = EXCLUDED.prescription_status,
+			    max_daily_dose_mg = COALESCE(EXCLUDED.max_daily_dose_mg, medications.max_daily_dose_mg),
+			    updated_at = NOW()
+			RETURNING (xmax = 0)
+		`,
+			p.Name,
+			p.ActiveSubstance,
+			p.Form,
+			p.Dosage,
+			p.Manufacturer,
+			p.AuthorizationCode,
+			nullIfEmpty(p.ATCCode),
+			p.Package,
+			p.PrescriptionStatus,
+			p.MaxDailyDoseMg,
+		).Scan(&inserted)
+		if err != nil {
+			return nil, err
+		}
+
+		if inserted {
+			summary.Added++
+		} else {
+			summary.Updated++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullIfZero(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type PatientRepository struct {
+	db *sql.DB
+}
+
+func NewPatientRepository(db *sql.DB) *PatientRepository {
+	return &PatientRepository{db: db}
+}
+
+const patientColumns = `
+	id, hospital_id, cnp, first_name, last_name, birth_date, sex,
+	phone, address, merged_into_id, created_at, updated_at
+`
+
+func (r *PatientRepository) Create(ctx context.Context, patient *domain.Patient) error {
+	query := `
+		INSERT INTO patients (
+			id, hospital_id, cnp, first_name, last_name, search_name,
+			birth_date, sex, phone, address, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		patient.ID,
+		patient.HospitalID,
+		patient.CNP,
+		patient.FirstName,
+		patient.LastName,
+		domain.PatientSearchName(patient.FirstName, patient.LastName),
+		patient.BirthDate,
+		nullIfEmpty(string(patient.Sex)),
+		nullIfEmpty(patient.Phone),
+		nullIfEmpty(patient.Address),
+		patient.CreatedAt,
+		patient.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrPatientAlreadyExists
+		}
+		if isForeignKeyViolation(err) {
+			return domain.ErrHospitalNotFound
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	return nil
+}
+
+// Delete removes a patient that nothing references yet. It undoes a
+// registration made for a request that failed.
+func (r *PatientRepository) Delete(ctx context.Context, hospitalID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM patients WHERE hospital_id = $1 AND id = $2`, hospitalID, id)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrPatientNotFound
+	}
+
+	return nil
+}
+
+func (r *PatientRepository) GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.Patient, error) {
+	query := `SELECT ` + patientColumns + ` FROM patients WHERE hospital_id = $1 AND id = $2`
+
+	patient, err := scanPatient(r.db.QueryRowContext(ctx, query, hospitalID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPatientNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return patient, nil
+}
+
+func (r *PatientRepository) GetByCNP(ctx context.Context, hospitalID uuid.UUID, cnp string) (*domain.Patient, error) {
+	query := `SELECT ` + patientColumns + ` FROM patients WHERE hospital_id = $1 AND cnp = $2`
+
+	patient, err := scanPatient(r.db.QueryRowContext(ctx, query, hospitalID, cnp))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPatientNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return patient, nil
+}
+
+func (r *PatientRepository) Update(ctx context.Context, patient *domain.Patient) error {
+	query := `
+		UPDATE patients
+		SET cnp = $3, first_name = $4, last_name = $5, search_name = $6,
+		    birth_date = $7, sex = $8, phone = $9, address = $10, updated_at = $11
+		WHERE hospital_id = $1 AND id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		patient.HospitalID,
+		patient.ID,
+		patient.CNP,
+		patient.FirstName,
+		patient.LastName,
+		domain.PatientSearchName(patient.FirstName, patient.LastName),
+		patient.BirthDate,
+		nullIfEmpty(string(patient.Sex)),
+		nullIfEmpty(patient.Phone),
+		nullIfEmpty(patient.Address),
+		patient.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrPatientAlreadyExists
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrPatientNotFound
+	}
+	return nil
+}
+
+// Search matches a CNP prefix and/or a name prefix of either the last or the
+// first name, ignoring diacritics. Merged records are left out.
+func (r *PatientRepository) Search(ctx context.Context, hospitalID uuid.UUID, search domain.PatientSearch, limit, offset int) ([]*domain.Patient, error) {
+	query := `SELECT ` + patientColumns + ` FROM patients WHERE hospital_id = $1 AND merged_into_id IS NULL`
+	args := []interface{}{hospitalID}
+
+	if search.CNP != "" {
+		args = append(args, search.CNP+"%")
+		query += ` AND cnp LIKE $` + strconv.Itoa(len(args))
+	}
+	if name := domain.FoldText(search.Name); name != "" {
+		args = append(args, name+"%", "% "+name+"%")
+		query += ` AND (search_name LIKE $` + strconv.Itoa(len(args)-1) + ` OR search_name LIKE $` + strconv.Itoa(len(args)) + `)`
+	}
+
+	args = append(args, limit, offset)
+	query += ` ORDER BY last_name, first_name LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	return r.queryPatients(ctx, query, args...)
+}
+
+// ListActive returns every patient of the hospital that was not merged away
+func (r *PatientRepository) ListActive(ctx context.Context, hospitalID uuid.UUID) ([]*domain.Patient, error) {
+	query := `SELECT ` + patientColumns + ` FROM patients WHERE hospital_id = $1 AND merged_into_id IS NULL ORDER BY created_at`
+	return r.queryPatients(ctx, query, hospitalID)
+}
+
+// ListHospitalIDs returns the hospitals that have registered patients
+func (r *PatientRepository) ListHospitalIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT hospital_id FROM patients`)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *PatientRepository) queryPatients(ctx context.Context, query string, args ...interface{}) ([]*domain.Patient, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	patients := []*domain.Patient{}
+	for rows.Next() {
+		patient, err := scanPatient(rows)
+		if err != nil {
+			return nil, err
+		}
+		patients = append(patients, patient)
+	}
+
+	return patients, rows.Err()
+}
+
+func scanPatient(row interface{ Scan(...interface{}) error }) (*domain.Patient, error) {
+	var patient domain.Patient
+	var birthDate sql.NullTime
+	var sex, phone, address sql.NullString
+	var mergedInto uuid.NullUUID
+
+	err := row.Scan(
+		&patient.ID,
+		&patient.HospitalID,
+		&patient.CNP,
+		&patient.FirstName,
+		&patient.LastName,
+		&birthDate,
+		&sex,
+		&phone,
+		&address,
+		&mergedInto,
+		&patient.CreatedAt,
+		&patient.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	patient.Sex = domain.Sex(sex.String)
+	patient.Phone = phone.String
+	patient.Address = address.String
+	if birthDate.Valid {
+		patient.BirthDate = &birthDate.Time
+	}
+	if mergedInto.Valid {
+		patient.MergedIntoID = &mergedInto.UUID
+	}
+
+	// Patients registered from pre-registry reports only have a CNP
+	if patient.Sex == domain.SexUnknown {
+		patient.Sex = domain.SexFromCNP(patient.CNP)
+	}
+	if patient.BirthDate == nil {
+		if d, ok := domain.BirthDateFromCNP(patient.CNP); ok {
+			patient.BirthDate = &d
+		}
+	}
+
+	return &patient, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// violatedConstraint is the name of the constraint a statement violated
+func violatedConstraint(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
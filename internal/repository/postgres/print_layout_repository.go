@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type PrintLayoutRepository struct {
+	db *sql.DB
+}
+
+func NewPrintLayoutRepository(db *sql.DB) *PrintLayoutRepository {
+	return &PrintLayoutRepository{db: db}
+}
+
+const printLayoutColumns = `hospital_id, report_type, source, updated_at, updated_by`
+
+func (r *PrintLayoutRepository) Get(ctx context.Context, hospitalID uuid.UUID, reportType domain.ReportType) (*domain.PrintLayout, error) {
+	query := `SELECT ` + printLayoutColumns + ` FROM print_layouts WHERE hospital_id = $1 AND report_type = $2`
+
+	layout, err := scanPrintLayout(r.db.QueryRowContext(ctx, query, hospitalID, reportType))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPrintLayoutNotFound
+		}
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+func (r *PrintLayoutRepository) List(ctx context.Context, hospitalID uuid.UUID) ([]*domain.PrintLayout, error) {
+	query := `SELECT ` + printLayoutColumns + ` FROM print_layouts WHERE hospital_id = $1 ORDER BY report_type`
+
+	rows, err := r.db.QueryContext(ctx, query, hospitalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var layouts []*domain.PrintLayout
+	for rows.Next() {
+		layout, err := scanPrintLayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, layout)
+	}
+
+	return layouts, rows.Err()
+}
+
+// Save stores the layout, replacing the hospital's layout for the report type
+func (r *PrintLayoutRepository) Save(ctx context.Context, layout *domain.PrintLayout) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO print_layouts (`+printLayoutColumns+`)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (hospital_id, report_type) DO UPDATE
+		SET source = EXCLUDED.source, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by
+	`, layout.HospitalID, layout.ReportType, layout.Source, layout.UpdatedAt, layout.UpdatedBy)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrHospitalNotFound
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	return nil
+}
+
+func (r *PrintLayoutRepository) Delete(ctx context.Context, hospitalID uuid.UUID, reportType domain.ReportType) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM print_layouts WHERE hospital_id = $1 AND report_type = $2`, hospitalID, reportType)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrPrintLayoutNotFound
+	}
+
+	return nil
+}
+
+func scanPrintLayout(row interface{ Scan(...interface{}) error }) (*domain.PrintLayout, error) {
+	var layout domain.PrintLayout
+	var updatedBy uuid.NullUUID
+	if err := row.Scan(&layout.HospitalID, &layout.ReportType, &layout.Source, &layout.UpdatedAt, &updatedBy); err != nil {
+		return nil, err
+	}
+	if updatedBy.Valid {
+		layout.UpdatedBy = &updatedBy.UUID
+	}
+	return &layout, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+func (r *ReferenceRepository) SearchProcedures(ctx context.Context, query string, limit int) ([]domain.ProcedureReference, error) {
+	sqlQuery := `
+		SELECT code, block, title_ro, active
+		FROM procedure_codes
+		WHERE active
+		  AND (search_vector @@ plainto_tsquery('romanian', $1)
+		   OR title_ro ILIKE $2
+		   OR code ILIKE $2)
+		ORDER BY 
+			CASE 
+				WHEN code ILIKE $2 OR title_ro ILIKE $2 THEN 0
+				ELSE 1
+			END,
+			code
+		LIMIT $3
+	`
+
+	searchPattern := query + "%"
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query, searchPattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []domain.ProcedureReference
+	for rows.Next() {
+		ref, err := scanProcedure(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *ref)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *ReferenceRepository) GetProcedureByCode(ctx context.Context, code string) (*domain.ProcedureReference, error) {
+	query := `
+		SELECT code, block, title_ro, active
+		FROM procedure_codes
+		WHERE code = $1
+	`
+
+	ref, err := scanProcedure(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReferenceNotFound
+		}
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// UpsertProcedures inserts or replaces classification entries. Codes missing
+// from a release are left untouched; releases retire codes explicitly.
+func (r *ReferenceRepository) UpsertProcedures(ctx context.Context, procedures []domain.ProcedureReference) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, p := range procedures {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO procedure_codes (code, block, title_ro, active)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (code) DO UPDATE
+			SET block = EXCLUDED.block, title_ro = EXCLUDED.title_ro, active = EXCLUDED.active
+		`, p.Code, nullIfEmpty(p.Block), p.TitleRO, p.Active)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func scanProcedure(row interface{ Scan(...interface{}) error }) (*domain.ProcedureReference, error) {
+	var ref domain.ProcedureReference
+	var block sql.NullString
+	if err := row.Scan(&ref.Code, &block, &ref.TitleRO, &ref.Active); err != nil {
+		return nil, err
+	}
+	ref.Block = block.String
+	return &ref, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/tudormiron/medical-reports/internal/domain"
 )
 
@@ -16,12 +17,19 @@ func NewReferenceRepository(db *sql.DB) *ReferenceRepository {
 	return &ReferenceRepository{db: db}
 }
 
-func (r *ReferenceRepository) SearchICD10(ctx context.Context, query string, limit int) ([]domain.ICD10Reference, error) {
+const icd10Columns = `
+	code, description_ro, category, level, parent_code, block_code, chapter_code, active,
+	NOT EXISTS (SELECT 1 FROM icd10_codes c WHERE c.parent_code = icd10_codes.code AND c.active) AS billable
+`
+
+func (r *ReferenceRepository) SearchICD10(ctx context.Context, query, chapterCode string, limit int) ([]domain.ICD10Reference, error) {
 	sqlQuery := `
-		SELECT code, description_ro, category
+		SELECT ` + icd10Columns + `
 		FROM icd10_codes
-		WHERE search_vector @@ plainto_tsquery('romanian', $1)
-		   OR code ILIKE $2
+		WHERE (search_vector @@ plainto_tsquery('romanian', $1)
+		   OR code ILIKE $2)
+		  AND active
+		  AND ($4 = '' OR chapter_code = $4)
 		ORDER BY 
 			CASE 
 				WHEN code ILIKE $2 THEN 0
@@ -32,7 +40,7 @@ func (r *ReferenceRepository) SearchICD10(ctx context.Context, query string, lim
 	`
 	
 	searchPattern := query + "%"
-	rows, err := r.db.QueryContext(ctx, sqlQuery, query, searchPattern, limit)
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query, searchPattern, limit, chapterCode)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +48,11 @@ func (r *ReferenceRepository) SearchICD10(ctx context.Context, query string, lim
 	
 	var results []domain.ICD10Reference
 	for rows.Next() {
-		var ref domain.ICD10Reference
-		if err := rows.Scan(&ref.Code, &ref.DescriptionRO, &ref.Category); err != nil {
+		ref, err := scanICD10(rows)
+		if err != nil {
 			return nil, err
 		}
-		results = append(results, ref)
+		results = append(results, *ref)
 	}
 	
 	return results, nil
@@ -52,34 +60,61 @@ func (r *ReferenceRepository) SearchICD10(ctx context.Context, query string, lim
 
 func (r *ReferenceRepository) GetICD10ByCode(ctx context.Context, code string) (*domain.ICD10Reference, error) {
 	query := `
-		SELECT code, description_ro, category
+		SELECT ` + icd10Columns + `
 		FROM icd10_codes
 		WHERE code = $1
 	`
 	
+	ref, err := scanICD10(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrReferenceNotFound
+		}
+		return nil, err
+	}
+	
+	return ref, nil
+}
+
+func scanICD10(row interface{ Scan(...interface{}) error }) (*domain.ICD10Reference, error) {
 	var ref domain.ICD10Reference
-	err := r.db.QueryRowContext(ctx, query, code).Scan(
+	var category, parentCode, blockCode, chapterCode sql.NullString
+	
+	err := row.Scan(
 		&ref.Code,
 		&ref.DescriptionRO,
-		&ref.Category,
+		&category,
+		&ref.Level,
+		&parentCode,
+		&blockCode,
+		&chapterCode,
+		&ref.Active,
+		&ref.Billable,
 	)
-	
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, domain.ErrReportNotFound
-		}
 		return nil, err
 	}
 	
+	ref.Category = category.String
+	ref.ParentCode = parentCode.String
+	ref.BlockCode = blockCode.String
+	ref.ChapterCode = chapterCode.String
+	
 	return &ref, nil
 }
 
-func (r *ReferenceRepository) SearchMedications(ctx context.Context, query string, limit int) ([]domain.MedicationReference, error) {
+const medicationColumns = `
+	id, name, active_substance, form, dosage, manufacturer,
+	authorization_code, atc_code, package, prescription_status, max_daily_dose_mg
+`
+
+func (r *ReferenceRepository) SearchMedications(ctx context.Context, search domain.MedicationSearch, limit int) ([]domain.MedicationReference, error) {
 	sqlQuery := `
-		SELECT id, name, active_substance, form, dosage, manufacturer
+		SELECT ` + medicationColumns + `
 		FROM medications
-		WHERE search_vector @@ plainto_tsquery('romanian', $1)
-		   OR name ILIKE $2
+		WHERE ($1 = '' OR search_vector @@ plainto_tsquery('romanian', $1) OR name ILIKE $2)
+		  AND ($4 = '' OR atc_code ILIKE $4 || '%')
+		  AND ($5 = '' OR active_substance ILIKE '%' || $5 || '%')
 		ORDER BY 
 			CASE 
 				WHEN name ILIKE $2 THEN 0
@@ -89,8 +124,8 @@ func (r *ReferenceRepository) SearchMedications(ctx context.Context, query strin
 		LIMIT $3
 	`
 	
-	searchPattern := query + "%"
-	rows, err := r.db.QueryContext(ctx, sqlQuery, query, searchPattern, limit)
+	searchPattern := search.Query + "%"
+	rows, err := r.db.QueryContext(ctx, sqlQuery, search.Query, searchPattern, limit, search.ATCCode, search.ActiveSubstance)
 	if err != nil {
 		return nil, err
 	}
@@ -98,31 +133,11 @@ func (r *ReferenceRepository) SearchMedications(ctx context.Context, query strin
 	
 	var results []domain.MedicationReference
 	for rows.Next() {
-		var ref domain.MedicationReference
-		var form, dosage, manufacturer sql.NullString
-		
-		if err := rows.Scan(
-			&ref.ID,
-			&ref.Name,
-			&ref.ActiveSubstance,
-			&form,
-			&dosage,
-			&manufacturer,
-		); err != nil {
+		ref, err := scanMedication(rows)
+		if err != nil {
 			return nil, err
 		}
-		
-		if form.Valid {
-			ref.Form = form.String
-		}
-		if dosage.Valid {
-			ref.Dosage = dosage.String
-		}
-		if manufacturer.Valid {
-			ref.Manufacturer = manufacturer.String
-		}
-		
-		results = append(results, ref)
+		results = append(results, *ref)
 	}
 	
 	return results, nil
@@ -130,39 +145,122 @@ func (r *ReferenceRepository) SearchMedications(ctx context.Context, query strin
 
 func (r *ReferenceRepository) GetMedicationByID(ctx context.Context, id uuid.UUID) (*domain.MedicationReference, error) {
 	query := `
-		SELECT id, name, active_substance, form, dosage, manufacturer
+		SELECT ` + medicationColumns + `
 		FROM medications
 		WHERE id = $1
 	`
 	
+	ref, err := scanMedication(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrReportNotFound
+		}
+		return nil, err
+	}
+	
+	return ref, nil
+}
+
+// FindMedicationByName returns the catalog product whose trade name or active
+// substance matches name exactly, ignoring case and diacritics
+func (r *ReferenceRepository) FindMedicationByName(ctx context.Context, name string) (*domain.MedicationReference, error) {
+	query := `
+		SELECT ` + medicationColumns + `
+		FROM medications
+		WHERE search_name = $1 OR search_substance = $1
+		ORDER BY CASE WHEN search_name = $1 THEN 0 ELSE 1 END, name
+		LIMIT 1
+	`
+	
+	ref, err := scanMedication(r.db.QueryRowContext(ctx, query, domain.FoldText(name)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrReferenceNotFound
+		}
+		return nil, err
+	}
+	
+	return ref, nil
+}
+
+// FindMedicationsByNames looks up several names at once, as
+// FindMedicationByName does. The products found are keyed by folded name;
+// names not in the catalog are left out.
+func (r *ReferenceRepository) FindMedicationsByNames(ctx context.Context, names []string) (map[string]*domain.MedicationReference, error) {
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		if key := domain.FoldText(name); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	found := make(map[string]*domain.MedicationReference)
+	if len(keys) == 0 {
+		return found, nil
+	}
+	
+	query := `
+		SELECT DISTINCT ON (k.key) k.key, ` + medicationColumns + `
+		FROM unnest($1::text[]) AS k(key)
+		JOIN medications ON search_name = k.key OR search_substance = k.key
+		ORDER BY k.key, CASE WHEN search_name = k.key THEN 0 ELSE 1 END, name
+	`
+	
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(keys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	for rows.Next() {
+		var key string
+		ref, err := scanMedication(keyedRow{rows, &key})
+		if err != nil {
+			return nil, err
+		}
+		found[key] = ref
+	}
+	
+	return found, rows.Err()
+}
+
+// keyedRow scans the lookup key selected ahead of a row's columns
+type keyedRow struct {
+	rows *sql.Rows
+	key  *string
+}
+
+func (r keyedRow) Scan(dest ...interface{}) error {
+	return r.rows.Scan(append([]interface{}{r.key}, dest...)...)
+}
+
+func scanMedication(row interface{ Scan(...interface{}) error }) (*domain.MedicationReference, error) {
 	var ref domain.MedicationReference
-	var form, dosage, manufacturer sql.NullString
+	var form, dosage, manufacturer, authorizationCode, atcCode, pkg, prescriptionStatus sql.NullString
 	
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&ref.ID,
 		&ref.Name,
 		&ref.ActiveSubstance,
 		&form,
 		&dosage,
 		&manufacturer,
+		&authorizationCode,
+		&atcCode,
+		&pkg,
+		&prescriptionStatus,
+		&ref.MaxDailyDoseMg,
 	)
-	
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, domain.ErrReportNotFound
-		}
 		return nil, err
 	}
 	
-	if form.Valid {
-		ref.Form = form.String
-	}
-	if dosage.Valid {
-		ref.Dosage = dosage.String
-	}
-	if manufacturer.Valid {
-		ref.Manufacturer = manufacturer.String
-	}
+	ref.Form = form.String
+	ref.Dosage = dosage.String
+	ref.Manufacturer = manufacturer.String
+	ref.AuthorizationCode = authorizationCode.String
+	ref.ATCCode = atcCode.String
+	ref.Package = pkg.String
+	ref.PrescriptionStatus = prescriptionStatus.String
 	
 	return &ref, nil
 }
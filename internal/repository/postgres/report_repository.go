@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tudormiron/medical-reports/internal/domain"
@@ -19,17 +20,37 @@ func NewReportRepository(db *sql.DB) *ReportRepository {
 	return &ReportRepository{db: db}
 }
 
+// reportSelect loads reports together with the content of their latest version
+const reportSelect = `
+		SELECT 
+			r.id, r.hospital_id, r.patient_id, r.encounter_id, r.patient_cnp, r.patient_first_name, r.patient_last_name,
+			r.specialty, r.report_type, r.status, r.created_by, r.created_at, 
+			r.last_modified, r.finalized_at, r.template_id, r.template_version, r.drg,
+			r.signed_by, r.content_hash, r.verification_token, v.content
+		FROM reports r
+		LEFT JOIN LATERAL (
+			SELECT content 
+			FROM report_versions 
+			WHERE report_id = r.id 
+			ORDER BY version_number DESC 
+			LIMIT 1
+		) v ON true
+`
+
 func (r *ReportRepository) Create(ctx context.Context, report *domain.Report) error {
 	query := `
 		INSERT INTO reports (
-			id, hospital_id, patient_cnp, patient_first_name, patient_last_name,
-			specialty, report_type, status, created_by, created_at, last_modified
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			id, hospital_id, patient_id, encounter_id, patient_cnp, patient_first_name, patient_last_name,
+			specialty, report_type, status, created_by, created_at, last_modified,
+			template_id, template_version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 	
 	_, err := r.db.ExecContext(ctx, query,
 		report.ID,
 		report.HospitalID,
+		report.PatientID,
+		report.EncounterID,
 		report.PatientCNP,
 		report.PatientFirstName,
 		report.PatientLastName,
@@ -39,9 +60,14 @@ func (r *ReportRepository) Create(ctx context.Context, report *domain.Report) er
 		report.CreatedBy,
 		report.CreatedAt,
 		report.LastModified,
+		report.TemplateID,
+		nullIfZero(report.TemplateVersion),
 	)
 	
 	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicateDischargeSummary
+		}
 		return domain.ErrDatabaseQuery
 	}
 	
@@ -51,42 +77,9 @@ func (r *ReportRepository) Create(ctx context.Context, report *domain.Report) er
 }
 
 func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Report, error) {
-	query := `
-		SELECT 
-			r.id, r.hospital_id, r.patient_cnp, r.patient_first_name, r.patient_last_name,
-			r.specialty, r.report_type, r.status, r.created_by, r.created_at, 
-			r.last_modified, r.finalized_at, v.content
-		FROM reports r
-		LEFT JOIN LATERAL (
-			SELECT content 
-			FROM report_versions 
-			WHERE report_id = r.id 
-			ORDER BY version_number DESC 
-			LIMIT 1
-		) v ON true
-		WHERE r.id = $1
-	`
-	
-	var report domain.Report
-	var contentJSON []byte
-	var finalizedAt sql.NullTime
-	
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&report.ID,
-		&report.HospitalID,
-		&report.PatientCNP,
-		&report.PatientFirstName,
-		&report.PatientLastName,
-		&report.Specialty,
-		&report.ReportType,
-		&report.Status,
-		&report.CreatedBy,
-		&report.CreatedAt,
-		&report.LastModified,
-		&finalizedAt,
-		&contentJSON,
-	)
+	query := reportSelect + ` WHERE r.id = $1`
 	
+	report, err := scanReport(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrReportNotFound
@@ -94,30 +87,56 @@ func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.R
 		return nil, domain.ErrDatabaseQuery
 	}
 	
-	if finalizedAt.Valid {
-		report.FinalizedAt = &finalizedAt.Time
-	}
+	return report, nil
+}
+
+// GetByVerificationToken finds the signed report printed with the token
+func (r *ReportRepository) GetByVerificationToken(ctx context.Context, token string) (*domain.Report, error) {
+	query := reportSelect + ` WHERE r.verification_token = $1`
 	
-	if contentJSON != nil {
-		if err := json.Unmarshal(contentJSON, &report.Content); err != nil {
-			return nil, err
+	report, err := scanReport(r.db.QueryRowContext(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrVerificationNotFound
 		}
+		return nil, domain.ErrDatabaseQuery
 	}
 	
-	return &report, nil
+	return report, nil
 }
 
 func (r *ReportRepository) Update(ctx context.Context, report *domain.Report) error {
 	query := `
 		UPDATE reports 
-		SET status = $1, last_modified = $2, finalized_at = $3
-		WHERE id = $4
+		SET status = $1, last_modified = $2, finalized_at = $3, drg = $4,
+			signed_by = $5, content_hash = $6, verification_token = $7
+		WHERE id = $8
 	`
 	
+	var drgJSON []byte
+	if report.DRG != nil {
+		var err error
+		if drgJSON, err = json.Marshal(report.DRG); err != nil {
+			return err
+		}
+	}
+	
+	var signedBy uuid.NullUUID
+	var contentHash, token sql.NullString
+	if sig := report.Signature; sig != nil {
+		signedBy = uuid.NullUUID{UUID: sig.SignedBy, Valid: true}
+		contentHash = sql.NullString{String: sig.ContentHash, Valid: true}
+		token = sql.NullString{String: sig.Token, Valid: true}
+	}
+	
 	_, err := r.db.ExecContext(ctx, query,
 		report.Status,
 		report.LastModified,
 		report.FinalizedAt,
+		drgJSON,
+		signedBy,
+		contentHash,
+		token,
 		report.ID,
 	)
 	
@@ -135,21 +154,7 @@ func (r *ReportRepository) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 func (r *ReportRepository) List(ctx context.Context, doctorID uuid.UUID, status domain.Status, limit, offset int) ([]*domain.Report, error) {
-	query := `
-		SELECT 
-			r.id, r.hospital_id, r.patient_cnp, r.patient_first_name, r.patient_last_name,
-			r.specialty, r.report_type, r.status, r.created_by, r.created_at, 
-			r.last_modified, r.finalized_at, v.content
-		FROM reports r
-		LEFT JOIN LATERAL (
-			SELECT content 
-			FROM report_versions 
-			WHERE report_id = r.id 
-			ORDER BY version_number DESC 
-			LIMIT 1
-		) v ON true
-		WHERE r.created_by = $1
-	`
+	query := reportSelect + ` WHERE r.created_by = $1`
 	
 	args := []interface{}{doctorID}
 	argCount := 1
@@ -163,6 +168,39 @@ func (r *ReportRepository) List(ctx context.Context, doctorID uuid.UUID, status
 	query += ` ORDER BY r.last_modified DESC LIMIT $` + strconv.Itoa(argCount+1) + ` OFFSET $` + strconv.Itoa(argCount+2)
 	args = append(args, limit, offset)
 	
+	return r.queryReports(ctx, query, args...)
+}
+
+// ListByPatient returns all reports of a patient, newest first
+func (r *ReportRepository) ListByPatient(ctx context.Context, patientID uuid.UUID) ([]*domain.Report, error) {
+	query := reportSelect + ` WHERE r.patient_id = $1 ORDER BY r.created_at DESC`
+	return r.queryReports(ctx, query, patientID)
+}
+
+// ListByEncounter returns the reports of an encounter in creation order
+func (r *ReportRepository) ListByEncounter(ctx context.Context, encounterID uuid.UUID) ([]*domain.Report, error) {
+	query := reportSelect + ` WHERE r.encounter_id = $1 ORDER BY r.created_at`
+	return r.queryReports(ctx, query, encounterID)
+}
+
+// UpdatePatientIdentity propagates registry changes to the identity columns
+// of the patient's reports. Signed and cancelled reports keep the identity
+// they were finalized with.
+func (r *ReportRepository) UpdatePatientIdentity(ctx context.Context, patient *domain.Patient) error {
+	query := `
+		UPDATE reports
+		SET patient_cnp = $2, patient_first_name = $3, patient_last_name = $4
+		WHERE patient_id = $1 AND status NOT IN ('signed', 'cancelled')
+	`
+	
+	_, err := r.db.ExecContext(ctx, query, patient.ID, patient.CNP, patient.FirstName, patient.LastName)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	return nil
+}
+
+func (r *ReportRepository) queryReports(ctx context.Context, query string, args ...interface{}) ([]*domain.Report, error) {
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, domain.ErrDatabaseQuery
@@ -171,44 +209,122 @@ func (r *ReportRepository) List(ctx context.Context, doctorID uuid.UUID, status
 	
 	var reports []*domain.Report
 	for rows.Next() {
-		var report domain.Report
-		var contentJSON []byte
-		var finalizedAt sql.NullTime
-		
-		err := rows.Scan(
-			&report.ID,
-			&report.HospitalID,
-			&report.PatientCNP,
-			&report.PatientFirstName,
-			&report.PatientLastName,
-			&report.Specialty,
-			&report.ReportType,
-			&report.Status,
-			&report.CreatedBy,
-			&report.CreatedAt,
-			&report.LastModified,
-			&finalizedAt,
-			&contentJSON,
-		)
-		
+		report, err := scanReport(rows)
 		if err != nil {
 			return nil, err
 		}
-		
-		if finalizedAt.Valid {
-			report.FinalizedAt = &finalizedAt.Time
+		reports = append(reports, report)
+	}
+	
+	return reports, rows.Err()
+}
+
+func scanReport(row interface{ Scan(...interface{}) error }) (*domain.Report, error) {
+	var report domain.Report
+	var contentJSON []byte
+	var finalizedAt sql.NullTime
+	var templateID uuid.NullUUID
+	var templateVersion sql.NullInt64
+	var drgJSON []byte
+	var signedBy uuid.NullUUID
+	var contentHash, token sql.NullString
+	
+	err := row.Scan(
+		&report.ID,
+		&report.HospitalID,
+		&report.PatientID,
+		&report.EncounterID,
+		&report.PatientCNP,
+		&report.PatientFirstName,
+		&report.PatientLastName,
+		&report.Specialty,
+		&report.ReportType,
+		&report.Status,
+		&report.CreatedBy,
+		&report.CreatedAt,
+		&report.LastModified,
+		&finalizedAt,
+		&templateID,
+		&templateVersion,
+		&drgJSON,
+		&signedBy,
+		&contentHash,
+		&token,
+		&contentJSON,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	if finalizedAt.Valid {
+		report.FinalizedAt = &finalizedAt.Time
+	}
+	if templateID.Valid {
+		report.TemplateID = &templateID.UUID
+		report.TemplateVersion = int(templateVersion.Int64)
+	}
+	if drgJSON != nil {
+		if err := json.Unmarshal(drgJSON, &report.DRG); err != nil {
+			return nil, err
 		}
-		
-		if contentJSON != nil {
-			if err := json.Unmarshal(contentJSON, &report.Content); err != nil {
-				return nil, err
-			}
+	}
+	// The signing time is the report's finalization time
+	if token.Valid && finalizedAt.Valid {
+		report.Signature = &domain.Signature{
+			SignedBy:    signedBy.UUID,
+			SignedAt:    finalizedAt.Time,
+			ContentHash: contentHash.String,
+			Token:       token.String,
 		}
-		
-		reports = append(reports, &report)
 	}
 	
-	return reports, nil
+	if contentJSON != nil {
+		if err := json.Unmarshal(contentJSON, &report.Content); err != nil {
+			return nil, err
+		}
+	}
+	
+	return &report, nil
+}
+
+// CountPrimaryDiagnosesByBlock rolls up signed reports by the ICD-10 block of their primary diagnosis
+func (r *ReportRepository) CountPrimaryDiagnosesByBlock(ctx context.Context, hospitalID uuid.UUID, from, to *time.Time) ([]domain.ICD10BlockStatistic, error) {
+	query := `
+		SELECT b.code, b.title_ro, b.chapter_code, COUNT(*)
+		FROM reports r
+		JOIN LATERAL (
+			SELECT content 
+			FROM report_versions 
+			WHERE report_id = r.id 
+			ORDER BY version_number DESC 
+			LIMIT 1
+		) v ON true
+		JOIN icd10_codes c ON c.code = v.content->'diagnosis'->'primary_diagnosis'->>'code'
+		JOIN icd10_blocks b ON b.code = c.block_code
+		WHERE r.hospital_id = $1
+		  AND r.status = 'signed'
+		  AND ($2::timestamptz IS NULL OR r.finalized_at >= $2)
+		  AND ($3::timestamptz IS NULL OR r.finalized_at < $3)
+		GROUP BY b.code, b.title_ro, b.chapter_code
+		ORDER BY COUNT(*) DESC, b.code
+	`
+	
+	rows, err := r.db.QueryContext(ctx, query, hospitalID, from, to)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+	
+	stats := []domain.ICD10BlockStatistic{}
+	for rows.Next() {
+		var stat domain.ICD10BlockStatistic
+		if err := rows.Scan(&stat.BlockCode, &stat.BlockTitle, &stat.ChapterCode, &stat.ReportCount); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	
+	return stats, rows.Err()
 }
 
 func (r *ReportRepository) SaveVersion(ctx context.Context, version *domain.ReportVersion) error {
@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/tudormiron/medical-reports/internal/domain"
+)
+
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// templateSelect joins templates with the definition of a version; the
+// caller picks the version in the join condition
+const templateSelect = `
+	SELECT t.id, t.hospital_id, t.active, t.created_at, v.created_at,
+	       v.version, v.name, v.report_type, v.specialty, v.department_id,
+	       v.defaults, v.required_sections, v.checklist, v.created_by
+	FROM report_templates t
+	JOIN report_template_versions v ON v.template_id = t.id
+`
+
+// Create saves a new template with its first version
+func (r *TemplateRepository) Create(ctx context.Context, template *domain.ReportTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO report_templates (
+			id, hospital_id, name, report_type, specialty, department_id,
+			current_version, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		template.ID,
+		template.HospitalID,
+		template.Name,
+		template.ReportType,
+		nullIfEmpty(string(template.Specialty)),
+		template.DepartmentID,
+		template.Version,
+		template.Active,
+		template.CreatedAt,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrDepartmentNotFound
+		}
+		return domain.ErrDatabaseQuery
+	}
+
+	if err := saveTemplateVersion(ctx, tx, template); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update saves a new version of the template and makes it current
+func (r *TemplateRepository) Update(ctx context.Context, template *domain.ReportTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE report_templates
+		SET name = $3, report_type = $4, specialty = $5, department_id = $6,
+		    current_version = $7, active = $8, updated_at = $9
+		WHERE hospital_id = $1 AND id = $2
+	`,
+		template.HospitalID,
+		template.ID,
+		template.Name,
+		template.ReportType,
+		nullIfEmpty(string(template.Specialty)),
+		template.DepartmentID,
+		template.Version,
+		template.Active,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrDepartmentNotFound
+		}
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrTemplateNotFound
+	}
+
+	if err := saveTemplateVersion(ctx, tx, template); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SetActive enables or retires a template without creating a version
+func (r *TemplateRepository) SetActive(ctx context.Context, hospitalID, id uuid.UUID, active bool) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE report_templates SET active = $3, updated_at = NOW()
+		WHERE hospital_id = $1 AND id = $2
+	`, hospitalID, id, active)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// GetByID returns the current version of a template
+func (r *TemplateRepository) GetByID(ctx context.Context, hospitalID, id uuid.UUID) (*domain.ReportTemplate, error) {
+	query := templateSelect + ` AND v.version = t.current_version WHERE t.hospital_id = $1 AND t.id = $2`
+
+	template, err := scanTemplate(r.db.QueryRowContext(ctx, query, hospitalID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return template, nil
+}
+
+// GetVersion returns one version of a template of the hospital, as applied
+// to a report
+func (r *TemplateRepository) GetVersion(ctx context.Context, hospitalID, id uuid.UUID, version int) (*domain.ReportTemplate, error) {
+	query := templateSelect + ` AND v.version = $3 WHERE t.hospital_id = $1 AND t.id = $2`
+
+	template, err := scanTemplate(r.db.QueryRowContext(ctx, query, hospitalID, id, version))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, domain.ErrDatabaseQuery
+	}
+
+	return template, nil
+}
+
+// List returns the current version of the hospital's templates; only active
+// ones unless includeInactive
+func (r *TemplateRepository) List(ctx context.Context, hospitalID uuid.UUID, includeInactive bool) ([]*domain.ReportTemplate, error) {
+	query := templateSelect + ` AND v.version = t.current_version
+		WHERE t.hospital_id = $1 AND (t.active OR $2)
+		ORDER BY v.report_type, v.name
+	`
+	return r.queryTemplates(ctx, query, hospitalID, includeInactive)
+}
+
+// ListVersions returns every version of a template, newest first
+func (r *TemplateRepository) ListVersions(ctx context.Context, hospitalID, id uuid.UUID) ([]*domain.ReportTemplate, error) {
+	query := templateSelect + ` WHERE t.hospital_id = $1 AND t.id = $2 ORDER BY v.version DESC`
+	return r.queryTemplates(ctx, query, hospitalID, id)
+}
+
+func (r *TemplateRepository) queryTemplates(ctx context.Context, query string, args ...interface{}) ([]*domain.ReportTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domain.ErrDatabaseQuery
+	}
+	defer rows.Close()
+
+	templates := []*domain.ReportTemplate{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+func saveTemplateVersion(ctx context.Context, tx *sql.Tx, template *domain.ReportTemplate) error {
+	defaults, err := json.Marshal(template.Defaults)
+	if err != nil {
+		return err
+	}
+	checklist, err := json.Marshal(template.Checklist)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO report_template_versions (
+			template_id, version, name, report_type, specialty, department_id,
+			defaults, required_sections, checklist, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		template.ID,
+		template.Version,
+		template.Name,
+		template.ReportType,
+		nullIfEmpty(string(template.Specialty)),
+		template.DepartmentID,
+		defaults,
+		pq.StringArray(template.RequiredSections),
+		checklist,
+		template.VersionCreatedBy,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseQuery
+	}
+
+	return nil
+}
+
+func scanTemplate(row interface{ Scan(...interface{}) error }) (*domain.ReportTemplate, error) {
+	var t domain.ReportTemplate
+	var specialty sql.NullString
+	var departmentID uuid.NullUUID
+	var defaults, checklist []byte
+	var required pq.StringArray
+
+	err := row.Scan(
+		&t.ID,
+		&t.HospitalID,
+		&t.Active,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+		&t.Version,
+		&t.Name,
+		&t.ReportType,
+		&specialty,
+		&departmentID,
+		&defaults,
+		&required,
+		&checklist,
+		&t.VersionCreatedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Specialty = domain.Specialty(specialty.String)
+	if departmentID.Valid {
+		t.DepartmentID = &departmentID.UUID
+	}
+	t.RequiredSections = []string(required)
+	if err := json.Unmarshal(defaults, &t.Defaults); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(checklist, &t.Checklist); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -37,6 +37,9 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
+	if isForeignKeyViolation(err) {
+		return domain.ErrHospitalNotFound
+	}
 
 	return err
 }
@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/tudormiron/medical-reports/internal/domain"
 	"github.com/tudormiron/medical-reports/internal/repository/postgres"
 	"golang.org/x/crypto/bcrypt"
@@ -30,6 +32,11 @@ func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest)
 		return nil, errors.New("email already registered")
 	}
 
+	hospitalID, err := uuid.Parse(req.HospitalID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrHospitalNotFound, req.HospitalID)
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -42,7 +49,7 @@ func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest)
 		string(hashedPassword),
 		req.FirstName,
 		req.LastName,
-		req.HospitalID,
+		hospitalID,
 		req.Specialty,
 	)
 
@@ -87,11 +94,16 @@ func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest) (*doma
 }
 
 func (s *AuthService) generateToken(user *domain.User) (string, error) {
+	hospitalID := ""
+	if user.HospitalID != nil {
+		hospitalID = user.HospitalID.String()
+	}
+
 	claims := jwt.MapClaims{
 		"user_id":     user.ID.String(),
 		"email":       user.Email,
 		"role":        user.Role,
-		"hospital_id": user.HospitalID,
+		"hospital_id": hospitalID,
 		"exp":         time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
 		"iat":         time.Now().Unix(),
 	}
@@ -99,3 +111,37 @@ func (s *AuthService) generateToken(user *domain.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.jwtSecret))
 }
+
+// ParseToken verifies a token issued by Login or Register and returns its claims
+func (s *AuthService) ParseToken(tokenString string) (*domain.TokenClaims, error) {
+	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, domain.ErrInvalidToken
+		}
+		return []byte(s.jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(fmt.Sprint(claims["user_id"]))
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	role, _ := claims["role"].(string)
+	email, _ := claims["email"].(string)
+	hospitalID, _ := claims["hospital_id"].(string)
+
+	return &domain.TokenClaims{
+		UserID:     userID,
+		Email:      email,
+		Role:       role,
+		HospitalID: hospitalID,
+	}, nil
+}
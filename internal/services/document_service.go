@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/fhir"
+	"github.com/tudormiron/medical-reports/internal/render"
+	"github.com/tudormiron/medical-reports/internal/repository"
+	"github.com/tudormiron/medical-reports/internal/repository/postgres"
+)
+
+// DocumentService assembles printable documents from reports
+type DocumentService struct {
+	reportRepo   repository.ReportRepository
+	hospitalRepo repository.HospitalRepository
+	userRepo     *postgres.UserRepository
+	layoutRepo   repository.PrintLayoutRepository
+	// verificationURL is the public page linked from the QR code of signed reports
+	verificationURL string
+}
+
+func NewDocumentService(reportRepo repository.ReportRepository, hospitalRepo repository.HospitalRepository, userRepo *postgres.UserRepository, layoutRepo repository.PrintLayoutRepository, verificationURL string) *DocumentService {
+	return &DocumentService{
+		reportRepo:      reportRepo,
+		hospitalRepo:    hospitalRepo,
+		userRepo:        userRepo,
+		layoutRepo:      layoutRepo,
+		verificationURL: strings.TrimSuffix(verificationURL, "/"),
+	}
+}
+
+// GetDocument loads a report together with its hospital letterhead and the
+// doctor who wrote it
+func (s *DocumentService) GetDocument(ctx context.Context, reportID uuid.UUID) (*render.Document, error) {
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	hospital, err := s.hospitalRepo.GetByID(ctx, report.HospitalID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &render.Document{Report: report, Hospital: hospital}
+	if err := s.loadLogo(ctx, doc); err != nil {
+		return nil, err
+	}
+	if report.Signature != nil && s.verificationURL != "" {
+		doc.VerificationURL = s.verificationURL + "/" + report.Signature.Token
+	}
+
+	// A report keeps its author after the account is removed; the
+	// signature block is then left for the handwritten name
+	if doctor, err := s.userRepo.GetByID(ctx, report.CreatedBy); err == nil {
+		doc.Doctor = doctor
+	}
+
+	return doc, nil
+}
+
+func (s *DocumentService) loadLogo(ctx context.Context, doc *render.Document) error {
+	if !doc.Hospital.HasLogo {
+		return nil
+	}
+	var err error
+	doc.Logo, doc.LogoType, err = s.hospitalRepo.GetLogo(ctx, doc.Hospital.ID)
+	if err != nil && !errors.Is(err, domain.ErrLogoNotFound) {
+		return err
+	}
+	return nil
+}
+
+// RenderHTML renders a report as an HTML page with its hospital's print
+// layout for the report type
+func (s *DocumentService) RenderHTML(ctx context.Context, reportID uuid.UUID) ([]byte, error) {
+	doc, err := s.GetDocument(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := s.GetLayout(ctx, doc.Report.HospitalID, doc.Report.ReportType)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := render.HTML(&buf, doc, layout.Source); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetLayout returns the hospital's print layout for the report type, or the
+// built-in layout when the hospital has not stored one
+func (s *DocumentService) GetLayout(ctx context.Context, hospitalID uuid.UUID, reportType domain.ReportType) (*domain.PrintLayout, error) {
+	if !reportType.IsValid() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidReportType, reportType)
+	}
+
+	layout, err := s.layoutRepo.Get(ctx, hospitalID, reportType)
+	if errors.Is(err, domain.ErrPrintLayoutNotFound) {
+		return &domain.PrintLayout{
+			HospitalID: hospitalID,
+			ReportType: reportType,
+			Source:     render.DefaultLayout(),
+			Default:    true,
+		}, nil
+	}
+	return layout, err
+}
+
+// ListLayouts returns the print layouts a hospital has stored
+func (s *DocumentService) ListLayouts(ctx context.Context, hospitalID uuid.UUID) ([]*domain.PrintLayout, error) {
+	if _, err := s.hospitalRepo.GetByID(ctx, hospitalID); err != nil {
+		return nil, err
+	}
+	return s.layoutRepo.List(ctx, hospitalID)
+}
+
+// SaveLayout stores a hospital's print layout for the report type after
+// rendering it against a sample report
+func (s *DocumentService) SaveLayout(ctx context.Context, layout *domain.PrintLayout, userID uuid.UUID) error {
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+
+	hospital, err := s.hospitalRepo.GetByID(ctx, layout.HospitalID)
+	if err != nil {
+		return err
+	}
+	if err := render.CheckLayout(layout.Source, hospital, layout.ReportType); err != nil {
+		return err
+	}
+
+	layout.Default = false
+	layout.UpdatedAt = time.Now()
+	layout.UpdatedBy = &userID
+	return s.layoutRepo.Save(ctx, layout)
+}
+
+// DeleteLayout removes a hospital's print layout, restoring the built-in one
+func (s *DocumentService) DeleteLayout(ctx context.Context, hospitalID uuid.UUID, reportType domain.ReportType) error {
+	return s.layoutRepo.Delete(ctx, hospitalID, reportType)
+}
+
+// PreviewLayout renders an unsaved layout, either with one of the hospital's
+// reports or, without a report ID, with a sample report
+func (s *DocumentService) PreviewLayout(ctx context.Context, layout *domain.PrintLayout, reportID *uuid.UUID) ([]byte, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
+	var doc *render.Document
+	if reportID != nil {
+		var err error
+		doc, err = s.GetDocument(ctx, *reportID)
+		if err != nil {
+			return nil, err
+		}
+		// Administrators preview with their own hospital's reports only
+		if doc.Report.HospitalID != layout.HospitalID {
+			return nil, domain.ErrReportNotFound
+		}
+		if doc.Report.ReportType != layout.ReportType {
+			return nil, fmt.Errorf("%w: the report is a %s", domain.ErrInvalidReportType, doc.Report.ReportType)
+		}
+	} else {
+		hospital, err := s.hospitalRepo.GetByID(ctx, layout.HospitalID)
+		if err != nil {
+			return nil, err
+		}
+		doc = render.SampleDocument(hospital, layout.ReportType)
+		if err := s.loadLogo(ctx, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := render.HTML(&buf, doc, layout.Source); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportFHIR maps a report to a FHIR R4 document bundle. The bundle is
+// validated before it leaves the service, so a mapping gap surfaces as an
+// error instead of an invalid document.
+func (s *DocumentService) ExportFHIR(ctx context.Context, reportID uuid.UUID) (*fhir.Bundle, error) {
+	doc, err := s.GetDocument(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := fhir.DocumentBundle(doc)
+	if err := bundle.Validate(); err != nil {
+		return nil, fmt.Errorf("export report %s: %w", reportID, err)
+	}
+	return bundle, nil
+}
+
+// Verify looks up the signed report printed with a verification token. Only
+// the report's identity and signature are returned, never its content.
+func (s *DocumentService) Verify(ctx context.Context, token string) (*domain.ReportVerification, error) {
+	token = domain.NormalizeVerificationToken(token)
+	if token == "" {
+		return nil, domain.ErrVerificationNotFound
+	}
+
+	report, err := s.reportRepo.GetByVerificationToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != domain.StatusSigned || report.Signature == nil {
+		return nil, domain.ErrVerificationNotFound
+	}
+
+	verification := &domain.ReportVerification{
+		ReportID:    report.ID,
+		ReportType:  report.ReportType,
+		SignedAt:    report.Signature.SignedAt,
+		ContentHash: report.Signature.ContentHash,
+	}
+
+	hospital, err := s.hospitalRepo.GetByID(ctx, report.HospitalID)
+	if err != nil {
+		return nil, err
+	}
+	verification.Hospital = hospital.Name
+
+	if signer, err := s.userRepo.GetByID(ctx, report.Signature.SignedBy); err == nil {
+		verification.Signer = render.DoctorName(signer)
+	}
+
+	return verification, nil
+}
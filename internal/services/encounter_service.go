@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/repository"
+)
+
+// EncounterService manages admission episodes: admission, transfers between
+// departments and discharge
+type EncounterService struct {
+	encounterRepo  repository.EncounterRepository
+	patientRepo    repository.PatientRepository
+	reportRepo     repository.ReportRepository
+	departmentRepo repository.DepartmentRepository
+	handoffRepo    repository.HandoffRepository
+}
+
+func NewEncounterService(encounterRepo repository.EncounterRepository, patientRepo repository.PatientRepository, reportRepo repository.ReportRepository, departmentRepo repository.DepartmentRepository, handoffRepo repository.HandoffRepository) *EncounterService {
+	return &EncounterService{
+		encounterRepo:  encounterRepo,
+		patientRepo:    patientRepo,
+		reportRepo:     reportRepo,
+		departmentRepo: departmentRepo,
+		handoffRepo:    handoffRepo,
+	}
+}
+
+// AdmitPatient opens an encounter for a registered patient. The department is
+// matched against the hospital's departments when it has defined them.
+func (s *EncounterService) AdmitPatient(ctx context.Context, hospitalID, patientID uuid.UUID, admissionNumber string, admittedAt time.Time, location domain.BedLocation) (*domain.Encounter, error) {
+	return s.admit(ctx, hospitalID, patientID, admissionNumber, admittedAt, location, "")
+}
+
+func (s *EncounterService) admit(ctx context.Context, hospitalID, patientID uuid.UUID, admissionNumber string, admittedAt time.Time, location domain.BedLocation, specialty domain.Specialty) (*domain.Encounter, error) {
+	patient, err := s.patientRepo.GetByID(ctx, hospitalID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if patient, err = followMerges(ctx, s.patientRepo, patient); err != nil {
+		return nil, err
+	}
+
+	location, err = s.resolveLocation(ctx, hospitalID, location, specialty)
+	if err != nil {
+		return nil, err
+	}
+
+	encounter, err := domain.NewEncounter(patient, admissionNumber, admittedAt, location)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.encounterRepo.Create(ctx, encounter); err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+// FindOrAdmitPatient returns the patient's most recent active encounter,
+// admitting them at the given location when there is none; admitted reports
+// whether the encounter was opened. Used by clients that create reports
+// without an encounter; without a department, the patient is admitted to the
+// department of the report's specialty.
+func (s *EncounterService) FindOrAdmitPatient(ctx context.Context, hospitalID, patientID uuid.UUID, specialty domain.Specialty, location domain.BedLocation) (*domain.Encounter, bool, error) {
+	return s.FindOrAdmitPatientAt(ctx, hospitalID, patientID, specialty, "", time.Now(), location)
+}
+
+// FindOrAdmitPatientAt is FindOrAdmitPatient for an admission recorded in
+// the hospital information system, with its admission number and time. With
+// an admission number, the encounter recorded under it is returned; an active
+// encounter under another number is a conflict rather than the same stay.
+func (s *EncounterService) FindOrAdmitPatientAt(ctx context.Context, hospitalID, patientID uuid.UUID, specialty domain.Specialty, admissionNumber string, admittedAt time.Time, location domain.BedLocation) (*domain.Encounter, bool, error) {
+	patient, err := s.patientRepo.GetByID(ctx, hospitalID, patientID)
+	if err != nil {
+		return nil, false, err
+	}
+	if patient, err = followMerges(ctx, s.patientRepo, patient); err != nil {
+		return nil, false, err
+	}
+
+	encounters, err := s.encounterRepo.ListByPatient(ctx, hospitalID, patient.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	admissionNumber = strings.TrimSpace(admissionNumber)
+	if admissionNumber != "" {
+		for _, encounter := range encounters {
+			if encounter.AdmissionNumber == admissionNumber {
+				return encounter, false, nil
+			}
+		}
+	}
+	for _, encounter := range encounters {
+		if encounter.Status != domain.EncounterActive {
+			continue
+		}
+		if admissionNumber != "" {
+			return nil, false, fmt.Errorf("%w: admission %s was not found and the patient has another active encounter", domain.ErrAdmissionConflict, admissionNumber)
+		}
+		return encounter, false, nil
+	}
+
+	encounter, err := s.admit(ctx, hospitalID, patient.ID, admissionNumber, admittedAt, location, specialty)
+	return encounter, err == nil, err
+}
+
+// CancelAdmission removes an encounter opened by FindOrAdmitPatient for a
+// report that could not be created. Encounters with reports are kept.
+func (s *EncounterService) CancelAdmission(ctx context.Context, hospitalID, encounterID uuid.UUID) error {
+	return s.encounterRepo.Delete(ctx, hospitalID, encounterID)
+}
+
+// GetEncounter retrieves an encounter of the hospital
+func (s *EncounterService) GetEncounter(ctx context.Context, hospitalID, encounterID uuid.UUID) (*domain.Encounter, error) {
+	return s.encounterRepo.GetByID(ctx, hospitalID, encounterID)
+}
+
+// ListPatientEncounters returns the patient's encounters, most recent first
+func (s *EncounterService) ListPatientEncounters(ctx context.Context, hospitalID, patientID uuid.UUID) ([]*domain.Encounter, error) {
+	if _, err := s.patientRepo.GetByID(ctx, hospitalID, patientID); err != nil {
+		return nil, err
+	}
+
+	return s.encounterRepo.ListByPatient(ctx, hospitalID, patientID)
+}
+
+// TransferPatient moves the patient to another department, ward or bed
+func (s *EncounterService) TransferPatient(ctx context.Context, hospitalID, encounterID uuid.UUID, location domain.BedLocation, at time.Time) (*domain.Encounter, error) {
+	encounter, err := s.encounterRepo.GetByID(ctx, hospitalID, encounterID)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err = s.resolveLocation(ctx, hospitalID, location, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encounter.Transfer(location, at); err != nil {
+		return nil, err
+	}
+
+	if err := s.encounterRepo.Update(ctx, encounter); err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+// DischargePatient closes the encounter. Signing its discharge summary does
+// the same with the discharge date of the summary.
+func (s *EncounterService) DischargePatient(ctx context.Context, hospitalID, encounterID uuid.UUID, at time.Time) (*domain.Encounter, error) {
+	encounter, err := s.encounterRepo.GetByID(ctx, hospitalID, encounterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encounter.Discharge(at); err != nil {
+		return nil, err
+	}
+
+	if err := s.encounterRepo.Update(ctx, encounter); err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+// ListEncounterReports returns the reports written during the encounter
+func (s *EncounterService) ListEncounterReports(ctx context.Context, hospitalID, encounterID uuid.UUID) ([]*domain.Report, error) {
+	if _, err := s.encounterRepo.GetByID(ctx, hospitalID, encounterID); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.ListByEncounter(ctx, encounterID)
+}
+
+// resolveLocation links the location to one of the hospital's departments
+func (s *EncounterService) resolveLocation(ctx context.Context, hospitalID uuid.UUID, location domain.BedLocation, specialty domain.Specialty) (domain.BedLocation, error) {
+	departments, err := s.departmentRepo.ListByHospital(ctx, hospitalID)
+	if err != nil {
+		return location, err
+	}
+
+	return domain.ResolveDepartment(departments, location, specialty)
+}
+
+// ListHandoffs returns a page of the transfers queued for a department,
+// oldest first, and the size of the whole queue
+func (s *EncounterService) ListHandoffs(ctx context.Context, hospitalID, departmentID uuid.UUID, status domain.HandoffStatus, limit, offset int) ([]*domain.Handoff, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	if _, err := s.departmentRepo.GetByID(ctx, hospitalID, departmentID); err != nil {
+		return nil, 0, err
+	}
+
+	handoffs, err := s.handoffRepo.ListByDepartment(ctx, hospitalID, departmentID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.handoffRepo.CountByDepartment(ctx, hospitalID, departmentID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return handoffs, total, nil
+}
+
+// AcknowledgeHandoff records that the receiving department took over the
+// patient
+func (s *EncounterService) AcknowledgeHandoff(ctx context.Context, hospitalID, departmentID, id, userID uuid.UUID) (*domain.Handoff, error) {
+	handoff, err := s.handoffRepo.GetByID(ctx, hospitalID, id)
+	if err != nil {
+		return nil, err
+	}
+	if handoff.DepartmentID != departmentID {
+		return nil, domain.ErrHandoffNotFound
+	}
+
+	if err := handoff.Acknowledge(userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.handoffRepo.Update(ctx, handoff); err != nil {
+		return nil, err
+	}
+
+	return handoff, nil
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/repository"
+)
+
+// HospitalService manages hospital master data: departments and report
+// templates
+type HospitalService struct {
+	hospitalRepo   repository.HospitalRepository
+	departmentRepo repository.DepartmentRepository
+	templateRepo   repository.TemplateRepository
+}
+
+func NewHospitalService(hospitalRepo repository.HospitalRepository, departmentRepo repository.DepartmentRepository, templateRepo repository.TemplateRepository) *HospitalService {
+	return &HospitalService{
+		hospitalRepo:   hospitalRepo,
+		departmentRepo: departmentRepo,
+		templateRepo:   templateRepo,
+	}
+}
+
+func (s *HospitalService) CreateHospital(ctx context.Context, input domain.HospitalInput) (*domain.Hospital, error) {
+	hospital := domain.NewHospital(input)
+	if err := hospital.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.hospitalRepo.Create(ctx, hospital); err != nil {
+		return nil, err
+	}
+
+	return hospital, nil
+}
+
+func (s *HospitalService) GetHospital(ctx context.Context, id uuid.UUID) (*domain.Hospital, error) {
+	return s.hospitalRepo.GetByID(ctx, id)
+}
+
+func (s *HospitalService) ListHospitals(ctx context.Context) ([]*domain.Hospital, error) {
+	return s.hospitalRepo.List(ctx)
+}
+
+func (s *HospitalService) UpdateHospital(ctx context.Context, id uuid.UUID, input domain.HospitalInput) (*domain.Hospital, error) {
+	hospital, err := s.hospitalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	hospital.Apply(input)
+	if err := hospital.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.hospitalRepo.Update(ctx, hospital); err != nil {
+		return nil, err
+	}
+
+	return hospital, nil
+}
+
+// DeleteHospital removes a hospital nothing references yet, with its
+// departments
+func (s *HospitalService) DeleteHospital(ctx context.Context, id uuid.UUID) error {
+	return s.hospitalRepo.Delete(ctx, id)
+}
+
+// GetLogo returns the letterhead logo and its content type
+func (s *HospitalService) GetLogo(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
+	return s.hospitalRepo.GetLogo(ctx, id)
+}
+
+// SetLogo stores a PNG or JPEG letterhead logo. The format is detected from
+// the image itself rather than trusted from the upload, and the image is
+// re-encoded so that the PDF writer can embed it whatever the encoder options
+// of the upload (interlacing, 16-bit channels, progressive JPEG).
+func (s *HospitalService) SetLogo(ctx context.Context, id uuid.UUID, logo []byte) error {
+	if len(logo) == 0 {
+		return fmt.Errorf("%w: empty image", domain.ErrInvalidLogo)
+	}
+	if len(logo) > domain.MaxLogoSize {
+		return fmt.Errorf("%w: larger than %d KB", domain.ErrInvalidLogo, domain.MaxLogoSize/1024)
+	}
+
+	contentType := http.DetectContentType(logo)
+	if !domain.LogoContentTypes[contentType] {
+		return fmt.Errorf("%w: %s is not a PNG or JPEG image", domain.ErrInvalidLogo, contentType)
+	}
+
+	logo, err := reencodeLogo(logo, contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidLogo, err)
+	}
+
+	return s.hospitalRepo.SetLogo(ctx, id, logo, contentType)
+}
+
+// reencodeLogo decodes the logo and writes it back as a baseline JPEG or a
+// non-interlaced 8-bit PNG
+func reencodeLogo(logo []byte, contentType string) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(logo))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95})
+	} else {
+		rgba := image.NewNRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+		err = png.Encode(&buf, rgba)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *HospitalService) RemoveLogo(ctx context.Context, id uuid.UUID) error {
+	return s.hospitalRepo.SetLogo(ctx, id, nil, "")
+}
+
+func (s *HospitalService) CreateDepartment(ctx context.Context, hospitalID uuid.UUID, input domain.DepartmentInput) (*domain.Department, error) {
+	department := domain.NewDepartment(hospitalID, input)
+	if err := department.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.departmentRepo.Create(ctx, department); err != nil {
+		return nil, err
+	}
+
+	return department, nil
+}
+
+// ListDepartments returns the hospital's departments by name
+func (s *HospitalService) ListDepartments(ctx context.Context, hospitalID uuid.UUID) ([]*domain.Department, error) {
+	if _, err := s.hospitalRepo.GetByID(ctx, hospitalID); err != nil {
+		return nil, err
+	}
+
+	return s.departmentRepo.ListByHospital(ctx, hospitalID)
+}
+
+// UpdateDepartment changes a department. Encounter stays keep the department
+// name they were recorded with.
+func (s *HospitalService) UpdateDepartment(ctx context.Context, hospitalID, id uuid.UUID, input domain.DepartmentInput) (*domain.Department, error) {
+	department, err := s.departmentRepo.GetByID(ctx, hospitalID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	department.Apply(input)
+	if err := department.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.departmentRepo.Update(ctx, department); err != nil {
+		return nil, err
+	}
+
+	return department, nil
+}
+
+// DeleteDepartment removes a department no encounter stay references
+func (s *HospitalService) DeleteDepartment(ctx context.Context, hospitalID, id uuid.UUID) error {
+	return s.departmentRepo.Delete(ctx, hospitalID, id)
+}
+
+func (s *HospitalService) CreateTemplate(ctx context.Context, hospitalID uuid.UUID, input domain.TemplateInput, userID uuid.UUID) (*domain.ReportTemplate, error) {
+	template := domain.NewReportTemplate(hospitalID, input, userID)
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *HospitalService) GetTemplate(ctx context.Context, hospitalID, id uuid.UUID) (*domain.ReportTemplate, error) {
+	return s.templateRepo.GetByID(ctx, hospitalID, id)
+}
+
+// ListTemplates returns the current version of the hospital's templates
+func (s *HospitalService) ListTemplates(ctx context.Context, hospitalID uuid.UUID, includeInactive bool) ([]*domain.ReportTemplate, error) {
+	return s.templateRepo.List(ctx, hospitalID, includeInactive)
+}
+
+// ListTemplateVersions returns the template's history, newest first
+func (s *HospitalService) ListTemplateVersions(ctx context.Context, hospitalID, id uuid.UUID) ([]*domain.ReportTemplate, error) {
+	versions, err := s.templateRepo.ListVersions(ctx, hospitalID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, domain.ErrTemplateNotFound
+	}
+
+	return versions, nil
+}
+
+// UpdateTemplate saves a new version of the template. Existing reports keep
+// the version they were created from.
+func (s *HospitalService) UpdateTemplate(ctx context.Context, hospitalID, id uuid.UUID, input domain.TemplateInput, userID uuid.UUID) (*domain.ReportTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, hospitalID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Revise(input, userID)
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// SetTemplateActive enables or retires a template for new reports
+func (s *HospitalService) SetTemplateActive(ctx context.Context, hospitalID, id uuid.UUID, active bool) (*domain.ReportTemplate, error) {
+	if err := s.templateRepo.SetActive(ctx, hospitalID, id, active); err != nil {
+		return nil, err
+	}
+
+	return s.templateRepo.GetByID(ctx, hospitalID, id)
+}
+
+// validateTemplate checks the definition and that its department belongs to
+// the hospital
+func (s *HospitalService) validateTemplate(ctx context.Context, template *domain.ReportTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	if template.DepartmentID != nil {
+		if _, err := s.departmentRepo.GetByID(ctx, template.HospitalID, *template.DepartmentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/repository"
+)
+
+// MedicationSafetyService runs clinical safety checks over the medications
+// prescribed in a report and reports its findings as validation warnings
+type MedicationSafetyService struct {
+	refRepo      repository.ReferenceRepository
+	interactions *domain.InteractionKnowledgeBase
+}
+
+func NewMedicationSafetyService(refRepo repository.ReferenceRepository, interactions *domain.InteractionKnowledgeBase) *MedicationSafetyService {
+	return &MedicationSafetyService{
+		refRepo:      refRepo,
+		interactions: interactions,
+	}
+}
+
+// resolvedMedication is a prescribed medication mapped to its active substances
+type resolvedMedication struct {
+	Name       string
+	Field      string
+	Substances []string
+	Catalog    *domain.MedicationReference
+	Dose       *domain.Dose
+}
+
+// Check returns severity-graded warnings for the report's medications
+func (s *MedicationSafetyService) Check(ctx context.Context, content domain.ReportContent) ([]domain.ValidationWarning, error) {
+	// Every name the checks look up in the catalog is fetched in one query
+	cache, err := s.prefetch(ctx, catalogNames(content))
+	if err != nil {
+		return nil, err
+	}
+
+	meds, err := s.resolveMedications(ctx, cache, content)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := s.checkInteractions(meds)
+	warnings = append(warnings, checkDailyDoses(meds)...)
+
+	allergyWarnings, err := s.checkAllergies(ctx, cache, content.Anamnesis.AllergyList, meds)
+	if err != nil {
+		return nil, err
+	}
+	return append(warnings, allergyWarnings...), nil
+}
+
+// checkDailyDoses compares structured doses with the catalog maximum daily
+// dose. Doses in tablets, vials or millilitres are converted with the
+// product strength; doses that cannot be converted are skipped.
+func checkDailyDoses(meds []resolvedMedication) []domain.ValidationWarning {
+	var warnings []domain.ValidationWarning
+
+	for _, med := range meds {
+		if med.Dose == nil || med.Catalog == nil || med.Catalog.MaxDailyDoseMg == nil {
+			continue
+		}
+
+		var strength *domain.Strength
+		if st, ok := domain.ParseStrength(med.Catalog.Dosage); ok {
+			strength = &st
+		}
+		// A strength in the prescribed name ("Enalapril 20mg") wins over the catalog product's
+		if fields := strings.Fields(med.Name); len(fields) > 1 {
+			if st, ok := domain.ParseStrength(strings.Join(fields[1:], " ")); ok {
+				strength = &st
+			}
+		}
+
+		daily, ok := med.Dose.DailyMilligrams(strength)
+		if !ok || daily <= *med.Catalog.MaxDailyDoseMg {
+			continue
+		}
+
+		warnings = append(warnings, domain.ValidationWarning{
+			Code:     domain.WarningMaxDailyDose,
+			Field:    med.Field + ".dose",
+			Severity: string(domain.InteractionMajor),
+			Message: fmt.Sprintf("%s: %s înseamnă %g mg/zi, peste doza maximă zilnică de %g mg",
+				med.Name, med.Dose, daily, *med.Catalog.MaxDailyDoseMg),
+		})
+	}
+
+	return warnings
+}
+
+// checkAllergies cross-references the structured allergies against the
+// substances and drug classes of every prescribed medication. A direct
+// substance match is reported as major; a class match (cross-reactivity,
+// e.g. amoxicillin allergy and an augmentin prescription) follows the
+// recorded reaction severity.
+func (s *MedicationSafetyService) checkAllergies(ctx context.Context, cache map[string]*domain.MedicationReference, allergies []domain.Allergy, meds []resolvedMedication) ([]domain.ValidationWarning, error) {
+	if len(allergies) == 0 || len(meds) == 0 {
+		return nil, nil
+	}
+
+	var warnings []domain.ValidationWarning
+
+	for _, allergy := range allergies {
+		substance := domain.FoldText(allergy.Substance)
+		classes, err := s.allergyClasses(ctx, cache, allergy)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, med := range meds {
+			if substance != "" && medicationContains(med, substance) {
+				warnings = append(warnings, domain.ValidationWarning{
+					Code:     domain.WarningAllergyConflict,
+					Field:    med.Field,
+					Severity: string(domain.InteractionMajor),
+					Message:  fmt.Sprintf("%s conține %s, la care pacientul este alergic%s", med.Name, allergy.Label(), reactionSuffix(allergy)),
+				})
+				continue
+			}
+
+			if med.Catalog == nil {
+				continue
+			}
+			for _, class := range domain.DrugClassesForATC(med.Catalog.ATCCode) {
+				if !classes[class.Code] {
+					continue
+				}
+				warnings = append(warnings, domain.ValidationWarning{
+					Code:     domain.WarningAllergyConflict,
+					Field:    med.Field,
+					Severity: string(allergySeverity(allergy)),
+					Message:  fmt.Sprintf("%s aparține clasei %s; alergie cunoscută la %s%s", med.Name, class.Name, allergy.Label(), reactionSuffix(allergy)),
+				})
+				break
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+// allergyClasses collects the drug classes an allergy entry implies: the
+// class named explicitly, a substance that is itself a class name
+// ("penicilină") and the classes of the allergen's catalog ATC code
+func (s *MedicationSafetyService) allergyClasses(ctx context.Context, cache map[string]*domain.MedicationReference, allergy domain.Allergy) (map[string]bool, error) {
+	classes := make(map[string]bool)
+
+	for _, name := range []string{allergy.DrugClass, allergy.Substance} {
+		if class, ok := domain.LookupDrugClass(name); ok {
+			classes[class.Code] = true
+		}
+	}
+
+	if strings.TrimSpace(allergy.Substance) != "" {
+		ref, err := s.lookup(ctx, cache, allergy.Substance)
+		if err != nil {
+			return nil, err
+		}
+		if ref != nil {
+			for _, class := range domain.DrugClassesForATC(ref.ATCCode) {
+				classes[class.Code] = true
+			}
+		}
+	}
+
+	return classes, nil
+}
+
+// medicationContains matches an allergen against the medication's substances
+// and name, tolerating declined forms ("amoxicilina" / "amoxicilinei")
+func medicationContains(med resolvedMedication, allergen string) bool {
+	candidates := append([]string{domain.FoldText(med.Name)}, med.Substances...)
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		if candidate == allergen || strings.HasPrefix(candidate, allergen+" ") {
+			return true
+		}
+		if sameStem(candidate, allergen) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameStem treats words differing only in a short Romanian inflection as equal
+func sameStem(a, b string) bool {
+	shorter := len(a)
+	if len(b) < shorter {
+		shorter = len(b)
+	}
+	if shorter < 6 {
+		return false
+	}
+	n := 0
+	for n < shorter && a[n] == b[n] {
+		n++
+	}
+	return n >= shorter-2 && len(a)-n <= 4 && len(b)-n <= 4
+}
+
+// allergySeverity maps the recorded reaction to the warning severity scale
+func allergySeverity(a domain.Allergy) domain.InteractionSeverity {
+	switch a.Severity {
+	case domain.AllergySevere, domain.AllergyAnaphylaxis:
+		return domain.InteractionMajor
+	case domain.AllergyMild:
+		return domain.InteractionMinor
+	default:
+		return domain.InteractionModerate
+	}
+}
+
+func reactionSuffix(a domain.Allergy) string {
+	if a.Reaction == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (reacție: %s)", a.Reaction)
+}
+
+// checkInteractions looks up every pair of substances prescribed together
+func (s *MedicationSafetyService) checkInteractions(meds []resolvedMedication) []domain.ValidationWarning {
+	var warnings []domain.ValidationWarning
+	reported := make(map[[2]string]bool)
+
+	for i := 0; i < len(meds); i++ {
+		for j := i + 1; j < len(meds); j++ {
+			for _, a := range meds[i].Substances {
+				for _, b := range meds[j].Substances {
+					if a == b {
+						continue
+					}
+					in, ok := s.interactions.Lookup(a, b)
+					if !ok {
+						continue
+					}
+
+					key := [2]string{domain.FoldText(in.SubstanceA), domain.FoldText(in.SubstanceB)}
+					if reported[key] {
+						continue
+					}
+					reported[key] = true
+
+					warnings = append(warnings, domain.ValidationWarning{
+						Code:     domain.WarningDrugInteraction,
+						Field:    meds[j].Field,
+						Severity: string(in.Severity),
+						Message:  fmt.Sprintf("%s + %s: %s", meds[i].Name, meds[j].Name, in.Description),
+					})
+				}
+			}
+		}
+	}
+
+	return warnings
+}
+
+// resolveMedications collects the structured in-hospital and discharge
+// medications and the medications mentioned in the free-text recommendations
+func (s *MedicationSafetyService) resolveMedications(ctx context.Context, cache map[string]*domain.MedicationReference, content domain.ReportContent) ([]resolvedMedication, error) {
+	var meds []resolvedMedication
+
+	lists := []struct {
+		field string
+		meds  []domain.Medication
+	}{
+		{"treatment.medications", content.Treatment.Medications},
+		{"medication_reconciliation.discharge", content.MedicationReconciliation.Discharge},
+	}
+	for _, list := range lists {
+		for i, m := range list.meds {
+			if strings.TrimSpace(m.Name) == "" {
+				continue
+			}
+			med, err := s.resolve(ctx, cache, m.Name)
+			if err != nil {
+				return nil, err
+			}
+			med.Field = fmt.Sprintf("%s[%d]", list.field, i)
+			med.Dose = m.Dose
+			meds = append(meds, med)
+		}
+	}
+
+	text := content.Recommendations.Medications
+	if strings.TrimSpace(text) == "" {
+		return meds, nil
+	}
+
+	field := "recommendations.medications"
+	found := make(map[string]bool)
+
+	// Trade names and substances found in the catalog
+	for _, word := range recommendationWords(text) {
+		ref, err := s.lookup(ctx, cache, word)
+		if err != nil {
+			return nil, err
+		}
+		if ref != nil {
+			substances := splitSubstances(ref.ActiveSubstance)
+			for _, substance := range substances {
+				found[substance] = true
+			}
+			meds = append(meds, resolvedMedication{
+				Name:       ref.Name,
+				Field:      field,
+				Substances: substances,
+				Catalog:    ref,
+			})
+		}
+	}
+
+	// Substances the knowledge base knows about but the catalog does not list
+	for _, substance := range s.interactions.SubstancesIn(text) {
+		if found[substance] {
+			continue
+		}
+		found[substance] = true
+		meds = append(meds, resolvedMedication{
+			Name:       substance,
+			Field:      field,
+			Substances: []string{substance},
+		})
+	}
+
+	return meds, nil
+}
+
+// catalogNames lists the names the checks of the content look up in the
+// catalog: prescribed names and their first word, the words of the
+// recommendations and the allergens
+func catalogNames(content domain.ReportContent) []string {
+	var names []string
+	for _, list := range [][]domain.Medication{content.Treatment.Medications, content.MedicationReconciliation.Discharge} {
+		for _, m := range list {
+			names = append(names, m.Name)
+			if fields := strings.Fields(m.Name); len(fields) > 1 {
+				names = append(names, fields[0])
+			}
+		}
+	}
+	names = append(names, recommendationWords(content.Recommendations.Medications)...)
+	for _, allergy := range content.Anamnesis.AllergyList {
+		names = append(names, allergy.Substance)
+	}
+	return names
+}
+
+// recommendationWords are the distinct words of free text long enough to
+// name a medication
+func recommendationWords(text string) []string {
+	var words []string
+	checked := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		key := domain.FoldText(word)
+		if len([]rune(word)) < 4 || checked[key] {
+			continue
+		}
+		checked[key] = true
+		words = append(words, word)
+	}
+	return words
+}
+
+// prefetch looks up the names in the catalog at once and returns a lookup
+// cache holding them, with names not in the catalog cached as missing
+func (s *MedicationSafetyService) prefetch(ctx context.Context, names []string) (map[string]*domain.MedicationReference, error) {
+	cache := make(map[string]*domain.MedicationReference)
+	var missing []string
+	for _, name := range names {
+		key := domain.FoldText(name)
+		if _, ok := cache[key]; ok || key == "" {
+			continue
+		}
+		cache[key] = nil
+		missing = append(missing, name)
+	}
+	if len(missing) == 0 {
+		return cache, nil
+	}
+
+	found, err := s.refRepo.FindMedicationsByNames(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, ref := range found {
+		cache[key] = ref
+	}
+	return cache, nil
+}
+
+// resolve maps a prescribed medication name to its substances, falling back
+// to the first word ("Enalapril 10mg" → "Enalapril") and finally the name itself
+func (s *MedicationSafetyService) resolve(ctx context.Context, cache map[string]*domain.MedicationReference, name string) (resolvedMedication, error) {
+	med := resolvedMedication{Name: name}
+
+	candidates := []string{name}
+	if fields := strings.Fields(name); len(fields) > 1 {
+		candidates = append(candidates, fields[0])
+	}
+
+	for _, candidate := range candidates {
+		ref, err := s.lookup(ctx, cache, candidate)
+		if err != nil {
+			return med, err
+		}
+		if ref != nil {
+			med.Catalog = ref
+			med.Substances = splitSubstances(ref.ActiveSubstance)
+			return med, nil
+		}
+	}
+
+	med.Substances = s.interactions.SubstancesIn(name)
+	if len(med.Substances) == 0 {
+		med.Substances = []string{domain.FoldText(candidates[len(candidates)-1])}
+	}
+	return med, nil
+}
+
+func (s *MedicationSafetyService) lookup(ctx context.Context, cache map[string]*domain.MedicationReference, name string) (*domain.MedicationReference, error) {
+	key := domain.FoldText(name)
+	if ref, ok := cache[key]; ok {
+		return ref, nil
+	}
+
+	ref, err := s.refRepo.FindMedicationByName(ctx, name)
+	if err != nil && !errors.Is(err, domain.ErrReferenceNotFound) {
+		return nil, err
+	}
+
+	cache[key] = ref
+	return ref, nil
+}
+
+// splitSubstances splits combination products ("Amoxicilină + Acid Clavulanic")
+func splitSubstances(activeSubstance string) []string {
+	var substances []string
+	for _, part := range strings.FieldsFunc(activeSubstance, func(r rune) bool { return r == '+' || r == '/' || r == ',' }) {
+		if folded := domain.FoldText(part); folded != "" {
+			substances = append(substances, folded)
+		}
+	}
+	return substances
+}
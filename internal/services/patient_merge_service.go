@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/repository"
+)
+
+// PatientMergeService detects duplicate registry entries and lets
+// administrators merge them and revert merges
+type PatientMergeService struct {
+	patientRepo   repository.PatientRepository
+	reportRepo    repository.ReportRepository
+	duplicateRepo repository.DuplicateRepository
+	auditRepo     repository.AuditRepository
+}
+
+func NewPatientMergeService(patientRepo repository.PatientRepository, reportRepo repository.ReportRepository, duplicateRepo repository.DuplicateRepository, auditRepo repository.AuditRepository) *PatientMergeService {
+	return &PatientMergeService{
+		patientRepo:   patientRepo,
+		reportRepo:    reportRepo,
+		duplicateRepo: duplicateRepo,
+		auditRepo:     auditRepo,
+	}
+}
+
+// DuplicateScanResult summarizes one detection run
+type DuplicateScanResult struct {
+	HospitalID uuid.UUID `json:"hospital_id"`
+	Patients   int       `json:"patients"`
+	Found      int       `json:"found"`
+	Queued     int       `json:"queued"`
+}
+
+// DetectDuplicates scans the active patients of a hospital and queues the
+// probable duplicates for review. Dismissed and merged pairs are not requeued.
+func (s *PatientMergeService) DetectDuplicates(ctx context.Context, hospitalID uuid.UUID) (*DuplicateScanResult, error) {
+	patients, err := s.patientRepo.ListActive(ctx, hospitalID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := domain.FindDuplicates(patients)
+	queued, err := s.duplicateRepo.SaveCandidates(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DuplicateScanResult{
+		HospitalID: hospitalID,
+		Patients:   len(patients),
+		Found:      len(candidates),
+		Queued:     queued,
+	}, nil
+}
+
+// DetectAllDuplicates runs the detection for every hospital with patients.
+// A failing hospital is logged and does not stop the others.
+func (s *PatientMergeService) DetectAllDuplicates(ctx context.Context) ([]*DuplicateScanResult, error) {
+	hospitalIDs, err := s.patientRepo.ListHospitalIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*DuplicateScanResult
+	for _, hospitalID := range hospitalIDs {
+		result, err := s.DetectDuplicates(ctx, hospitalID)
+		if err != nil {
+			log.Printf("Duplicate scan failed for hospital %s: %v", hospitalID, err)
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// DuplicateReview is a queued candidate together with both patients
+type DuplicateReview struct {
+	Candidate *domain.DuplicateCandidate
+	PatientA  *domain.Patient
+	PatientB  *domain.Patient
+}
+
+// ListDuplicates returns the review queue, by default the pending candidates
+func (s *PatientMergeService) ListDuplicates(ctx context.Context, hospitalID uuid.UUID, status domain.DuplicateStatus, limit, offset int) ([]DuplicateReview, error) {
+	if status == "" {
+		status = domain.DuplicatePending
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	candidates, err := s.duplicateRepo.ListCandidates(ctx, hospitalID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	patients := make(map[uuid.UUID]*domain.Patient)
+	load := func(id uuid.UUID) (*domain.Patient, error) {
+		if p, ok := patients[id]; ok {
+			return p, nil
+		}
+		p, err := s.patientRepo.GetByID(ctx, hospitalID, id)
+		if err != nil {
+			return nil, err
+		}
+		patients[id] = p
+		return p, nil
+	}
+
+	reviews := make([]DuplicateReview, 0, len(candidates))
+	for _, c := range candidates {
+		a, err := load(c.PatientAID)
+		if err != nil {
+			return nil, err
+		}
+		b, err := load(c.PatientBID)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, DuplicateReview{Candidate: c, PatientA: a, PatientB: b})
+	}
+
+	return reviews, nil
+}
+
+// DismissDuplicate marks a candidate as not being the same person
+func (s *PatientMergeService) DismissDuplicate(ctx context.Context, hospitalID, candidateID, userID uuid.UUID) (*domain.DuplicateCandidate, error) {
+	candidate, err := s.duplicateRepo.GetCandidate(ctx, hospitalID, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	if candidate.Status != domain.DuplicatePending {
+		return nil, fmt.Errorf("%w: candidate is already %s", domain.ErrInvalidMerge, candidate.Status)
+	}
+
+	now := time.Now()
+	candidate.Status = domain.DuplicateDismissed
+	candidate.ReviewedBy = &userID
+	candidate.ReviewedAt = &now
+
+	if err := s.duplicateRepo.UpdateCandidateStatus(ctx, candidate); err != nil {
+		return nil, err
+	}
+	return candidate, nil
+}
+
+// MergePatients moves every report of mergedID to survivorID and records the
+// merge in the audit log. When the merge resolves a queued candidate, its ID
+// must match the two patients.
+func (s *PatientMergeService) MergePatients(ctx context.Context, hospitalID, survivorID, mergedID uuid.UUID, candidateID *uuid.UUID, userID uuid.UUID) (*domain.PatientMerge, error) {
+	if candidateID != nil {
+		candidate, err := s.duplicateRepo.GetCandidate(ctx, hospitalID, *candidateID)
+		if err != nil {
+			return nil, err
+		}
+		if !candidate.Involves(survivorID, mergedID) {
+			return nil, fmt.Errorf("%w: candidate does not pair these patients", domain.ErrInvalidMerge)
+		}
+		if candidate.Status != domain.DuplicatePending {
+			return nil, fmt.Errorf("%w: candidate is already %s", domain.ErrInvalidMerge, candidate.Status)
+		}
+	}
+
+	merge, err := domain.NewPatientMerge(hospitalID, survivorID, mergedID, candidateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.duplicateRepo.Merge(ctx, merge); err != nil {
+		return nil, err
+	}
+
+	survivor, err := s.patientRepo.GetByID(ctx, hospitalID, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	// Unsigned reports of the merged record now carry the survivor's identity
+	if err := propagatePatientIdentity(ctx, s.reportRepo, survivor, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditMerge(ctx, domain.AuditPatientMerged, merge, userID); err != nil {
+		return nil, err
+	}
+
+	return merge, nil
+}
+
+// UnmergePatients reverts a merge: the moved reports go back to the merged
+// patient, which becomes active again
+func (s *PatientMergeService) UnmergePatients(ctx context.Context, hospitalID, mergeID, userID uuid.UUID) (*domain.PatientMerge, error) {
+	merge, err := s.duplicateRepo.GetMerge(ctx, hospitalID, mergeID)
+	if err != nil {
+		return nil, err
+	}
+	if merge.IsReverted() {
+		return nil, domain.ErrMergeAlreadyReverted
+	}
+
+	now := time.Now()
+	merge.RevertedBy = &userID
+	merge.RevertedAt = &now
+
+	if err := s.duplicateRepo.Unmerge(ctx, merge); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.patientRepo.GetByID(ctx, hospitalID, merge.MergedID)
+	if err != nil {
+		return nil, err
+	}
+	if err := propagatePatientIdentity(ctx, s.reportRepo, restored, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditMerge(ctx, domain.AuditPatientUnmerged, merge, userID); err != nil {
+		return nil, err
+	}
+
+	return merge, nil
+}
+
+// ListMerges returns the hospital's merges, newest first
+func (s *PatientMergeService) ListMerges(ctx context.Context, hospitalID uuid.UUID, limit, offset int) ([]*domain.PatientMerge, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	return s.duplicateRepo.ListMerges(ctx, hospitalID, limit, offset)
+}
+
+// auditMerge logs the merge on the surviving patient and on every moved
+// report, so that each report's trail shows where it came from
+func (s *PatientMergeService) auditMerge(ctx context.Context, eventType string, merge *domain.PatientMerge, userID uuid.UUID) error {
+	data := map[string]interface{}{
+		"merge_id":    merge.ID.String(),
+		"survivor_id": merge.SurvivorID.String(),
+		"merged_id":   merge.MergedID.String(),
+		"report_ids":  merge.ReportIDs,
+	}
+	if merge.CandidateID != nil {
+		data["candidate_id"] = merge.CandidateID.String()
+	}
+
+	if err := s.auditRepo.Log(ctx, domain.NewPatientAuditEntry(merge.SurvivorID, uuid.Nil, eventType, data, userID)); err != nil {
+		return err
+	}
+
+	// Moved reports belong to the survivor after a merge and to the restored
+	// patient after a revert
+	owner := merge.SurvivorID
+	if eventType == domain.AuditPatientUnmerged {
+		owner = merge.MergedID
+	}
+	for _, reportID := range merge.ReportIDs {
+		entry := domain.NewPatientAuditEntry(owner, reportID, eventType, map[string]interface{}{
+			"merge_id":    merge.ID.String(),
+			"survivor_id": merge.SurvivorID.String(),
+			"merged_id":   merge.MergedID.String(),
+		}, userID)
+		if err := s.auditRepo.Log(ctx, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// followMerges resolves a merged record to the patient that absorbed it
+func followMerges(ctx context.Context, patientRepo repository.PatientRepository, patient *domain.Patient) (*domain.Patient, error) {
+	// Merges cannot form cycles; the bound only guards against corrupt data
+	for i := 0; i < 10 && patient.MergedIntoID != nil; i++ {
+		next, err := patientRepo.GetByID(ctx, patient.HospitalID, *patient.MergedIntoID)
+		if err != nil {
+			return nil, err
+		}
+		patient = next
+	}
+	return patient, nil
+}
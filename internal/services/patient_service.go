@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/repository"
+)
+
+// PatientService manages the per-hospital patient registry
+type PatientService struct {
+	patientRepo repository.PatientRepository
+	reportRepo  repository.ReportRepository
+}
+
+func NewPatientService(patientRepo repository.PatientRepository, reportRepo repository.ReportRepository) *PatientService {
+	return &PatientService{
+		patientRepo: patientRepo,
+		reportRepo:  reportRepo,
+	}
+}
+
+// RegisterPatient creates a patient, failing if the CNP is already registered
+// with the hospital
+func (s *PatientService) RegisterPatient(ctx context.Context, hospitalID uuid.UUID, cnp, firstName, lastName, phone, address string) (*domain.Patient, error) {
+	patient := domain.NewPatient(hospitalID, cnp, firstName, lastName)
+	patient.Phone = phone
+	patient.Address = address
+
+	if err := patient.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.patientRepo.Create(ctx, patient); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// FindOrRegisterPatient returns the patient registered under the CNP, creating
+// it when missing; registered reports whether it was created. Used by clients
+// that still create reports by CNP and name.
+func (s *PatientService) FindOrRegisterPatient(ctx context.Context, hospitalID uuid.UUID, cnp, firstName, lastName string) (*domain.Patient, bool, error) {
+	patient, err := s.patientRepo.GetByCNP(ctx, hospitalID, cnp)
+	if err == nil {
+		// A CNP of a merged record keeps resolving to the survivor
+		patient, err = followMerges(ctx, s.patientRepo, patient)
+		return patient, false, err
+	}
+	if !errors.Is(err, domain.ErrPatientNotFound) {
+		return nil, false, err
+	}
+
+	patient, err = s.RegisterPatient(ctx, hospitalID, cnp, firstName, lastName, "", "")
+	if errors.Is(err, domain.ErrPatientAlreadyExists) {
+		// Registered concurrently
+		patient, err = s.patientRepo.GetByCNP(ctx, hospitalID, cnp)
+		return patient, false, err
+	}
+	return patient, err == nil, err
+}
+
+// CancelRegistration removes a patient registered by FindOrRegisterPatient
+// for a report that could not be created. Patients already admitted or
+// referenced are kept.
+func (s *PatientService) CancelRegistration(ctx context.Context, hospitalID, patientID uuid.UUID) error {
+	return s.patientRepo.Delete(ctx, hospitalID, patientID)
+}
+
+// GetPatient retrieves a patient of the hospital. Merged records are returned
+// as stored, with MergedIntoID pointing to the survivor.
+func (s *PatientService) GetPatient(ctx context.Context, hospitalID, patientID uuid.UUID) (*domain.Patient, error) {
+	return s.patientRepo.GetByID(ctx, hospitalID, patientID)
+}
+
+// UpdatePatient applies demographic changes and propagates the identity to
+// the patient's reports that are not yet signed, saving their new versions
+// as made by userID
+func (s *PatientService) UpdatePatient(ctx context.Context, hospitalID, patientID uuid.UUID, update domain.PatientUpdate, userID uuid.UUID) (*domain.Patient, error) {
+	patient, err := s.patientRepo.GetByID(ctx, hospitalID, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	patient.Apply(update)
+	if err := patient.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.patientRepo.Update(ctx, patient); err != nil {
+		return nil, err
+	}
+
+	if err := propagatePatientIdentity(ctx, s.reportRepo, patient, userID); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// SearchPatients finds patients by CNP or name
+func (s *PatientService) SearchPatients(ctx context.Context, hospitalID uuid.UUID, search domain.PatientSearch, limit, offset int) ([]*domain.Patient, error) {
+	if search.IsEmpty() {
+		return []*domain.Patient{}, nil
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	return s.patientRepo.Search(ctx, hospitalID, search, limit, offset)
+}
+
+// PatientTimeline returns the patient registered under the CNP together with
+// the chronological events of all of their signed reports in the hospital
+func (s *PatientService) PatientTimeline(ctx context.Context, hospitalID uuid.UUID, cnp string) (*domain.Patient, []domain.TimelineEvent, error) {
+	if len(cnp) != 13 {
+		return nil, nil, domain.ErrInvalidCNP
+	}
+
+	patient, err := s.patientRepo.GetByCNP(ctx, hospitalID, cnp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if patient, err = followMerges(ctx, s.patientRepo, patient); err != nil {
+		return nil, nil, err
+	}
+
+	reports, err := s.reportRepo.ListByPatient(ctx, patient.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return patient, domain.BuildTimeline(reports), nil
+}
+
+// ListPatientReports returns every report of the patient, newest first
+func (s *PatientService) ListPatientReports(ctx context.Context, hospitalID, patientID uuid.UUID) ([]*domain.Report, error) {
+	if _, err := s.patientRepo.GetByID(ctx, hospitalID, patientID); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.ListByPatient(ctx, patientID)
+}
+
+// propagatePatientIdentity copies the registry identity onto the patient's
+// reports that are not yet signed: the report columns, and the patient data
+// of their content through a new version, so that prints and exports show it.
+// Without a user the version is attributed to the report's author.
+func propagatePatientIdentity(ctx context.Context, reportRepo repository.ReportRepository, patient *domain.Patient, userID uuid.UUID) error {
+	if err := reportRepo.UpdatePatientIdentity(ctx, patient); err != nil {
+		return err
+	}
+
+	reports, err := reportRepo.ListByPatient(ctx, patient.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		if report.Status == domain.StatusSigned || report.Status == domain.StatusCancelled {
+			continue
+		}
+
+		before := report.Content.PatientData
+		report.SetPatient(patient)
+		after := report.Content.PatientData
+		if after.CNP == before.CNP && after.FirstName == before.FirstName &&
+			after.LastName == before.LastName && after.BirthDate.Equal(before.BirthDate) {
+			continue
+		}
+
+		savedBy := userID
+		if savedBy == uuid.Nil {
+			savedBy = report.CreatedBy
+		}
+
+		versions, err := reportRepo.GetVersions(ctx, report.ID)
+		if err != nil {
+			return err
+		}
+
+		version := domain.NewReportVersion(report.ID, len(versions)+1, report.Content, savedBy, "Patient identity updated from the registry")
+		if err := reportRepo.SaveVersion(ctx, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
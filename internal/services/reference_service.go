@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/tudormiron/medical-reports/internal/domain"
@@ -18,13 +19,28 @@ func NewReferenceService(refRepo repository.ReferenceRepository) *ReferenceServi
 	}
 }
 
-// SearchICD10 searches for ICD-10 codes
-func (s *ReferenceService) SearchICD10(ctx context.Context, query string) ([]domain.ICD10Reference, error) {
+// SearchICD10 searches for ICD-10 codes, optionally restricted to a chapter
+func (s *ReferenceService) SearchICD10(ctx context.Context, query, chapterCode string) ([]domain.ICD10Reference, error) {
 	if query == "" {
 		return []domain.ICD10Reference{}, nil
 	}
 	
-	return s.refRepo.SearchICD10(ctx, query, 10)
+	return s.refRepo.SearchICD10(ctx, query, chapterCode, 10)
+}
+
+// ListICD10Chapters returns the top level of the ICD-10 tree
+func (s *ReferenceService) ListICD10Chapters(ctx context.Context) ([]domain.ICD10Node, error) {
+	return s.refRepo.ListICD10Chapters(ctx)
+}
+
+// GetICD10Children returns the direct children of a chapter, block or code
+func (s *ReferenceService) GetICD10Children(ctx context.Context, code string) ([]domain.ICD10Node, error) {
+	return s.refRepo.GetICD10Children(ctx, code)
+}
+
+// GetICD10Ancestors returns the path from the chapter to the parent of a code
+func (s *ReferenceService) GetICD10Ancestors(ctx context.Context, code string) ([]domain.ICD10Node, error) {
+	return s.refRepo.GetICD10Ancestors(ctx, code)
 }
 
 // GetICD10ByCode retrieves a specific ICD-10 code
@@ -32,16 +48,51 @@ func (s *ReferenceService) GetICD10ByCode(ctx context.Context, code string) (*do
 	return s.refRepo.GetICD10ByCode(ctx, code)
 }
 
-// SearchMedications searches for medications
-func (s *ReferenceService) SearchMedications(ctx context.Context, query string) ([]domain.MedicationReference, error) {
-	if query == "" {
+// SearchMedications searches for medications by name, ATC code prefix or active substance
+func (s *ReferenceService) SearchMedications(ctx context.Context, search domain.MedicationSearch) ([]domain.MedicationReference, error) {
+	if search.IsEmpty() {
 		return []domain.MedicationReference{}, nil
 	}
 	
-	return s.refRepo.SearchMedications(ctx, query, 10)
+	search.ATCCode = strings.ToUpper(strings.TrimSpace(search.ATCCode))
+	search.ActiveSubstance = strings.TrimSpace(search.ActiveSubstance)
+	
+	return s.refRepo.SearchMedications(ctx, search, 10)
 }
 
 // GetMedicationByID retrieves a specific medication
 func (s *ReferenceService) GetMedicationByID(ctx context.Context, id uuid.UUID) (*domain.MedicationReference, error) {
 	return s.refRepo.GetMedicationByID(ctx, id)
 }
+
+// SearchLabTests searches the LOINC lab test catalog
+func (s *ReferenceService) SearchLabTests(ctx context.Context, query string) ([]domain.LabTestReference, error) {
+	if query == "" {
+		return []domain.LabTestReference{}, nil
+	}
+	
+	return s.refRepo.SearchLabTests(ctx, query, 10)
+}
+
+// GetLabTestByCode retrieves a lab test by its LOINC code
+func (s *ReferenceService) GetLabTestByCode(ctx context.Context, loincCode string) (*domain.LabTestReference, error) {
+	return s.refRepo.GetLabTestByCode(ctx, loincCode)
+}
+
+// SearchProcedures searches the ACHI procedure classification
+func (s *ReferenceService) SearchProcedures(ctx context.Context, query string) ([]domain.ProcedureReference, error) {
+	if query == "" {
+		return []domain.ProcedureReference{}, nil
+	}
+	
+	return s.refRepo.SearchProcedures(ctx, query, 10)
+}
+
+// GetProcedureByCode retrieves a procedure by its ACHI code
+func (s *ReferenceService) GetProcedureByCode(ctx context.Context, code string) (*domain.ProcedureReference, error) {
+	code, ok := domain.NormalizeProcedureCode(code)
+	if !ok {
+		return nil, domain.ErrReferenceNotFound
+	}
+	return s.refRepo.GetProcedureByCode(ctx, code)
+}
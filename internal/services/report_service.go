@@ -2,31 +2,140 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/fhir"
 	"github.com/tudormiron/medical-reports/internal/repository"
 )
 
 type ReportService struct {
-	reportRepo repository.ReportRepository
+	reportRepo     repository.ReportRepository
+	refRepo        repository.ReferenceRepository
+	auditRepo      repository.AuditRepository
+	patientRepo    repository.PatientRepository
+	encounterRepo  repository.EncounterRepository
+	templateRepo   repository.TemplateRepository
+	departmentRepo repository.DepartmentRepository
+	handoffRepo    repository.HandoffRepository
+	safety         *MedicationSafetyService
+	grouper        domain.DRGGrouper
 }
 
-func NewReportService(reportRepo repository.ReportRepository) *ReportService {
+func NewReportService(reportRepo repository.ReportRepository, refRepo repository.ReferenceRepository, auditRepo repository.AuditRepository, patientRepo repository.PatientRepository, encounterRepo repository.EncounterRepository, templateRepo repository.TemplateRepository, departmentRepo repository.DepartmentRepository, handoffRepo repository.HandoffRepository, safety *MedicationSafetyService, grouper domain.DRGGrouper) *ReportService {
 	return &ReportService{
-		reportRepo: reportRepo,
+		reportRepo:     reportRepo,
+		refRepo:        refRepo,
+		auditRepo:      auditRepo,
+		patientRepo:    patientRepo,
+		encounterRepo:  encounterRepo,
+		templateRepo:   templateRepo,
+		departmentRepo: departmentRepo,
+		handoffRepo:    handoffRepo,
+		safety:         safety,
+		grouper:        grouper,
 	}
 }
 
-// CreateReport creates a new report
-func (s *ReportService) CreateReport(ctx context.Context, hospitalID uuid.UUID, patientCNP, firstName, lastName string, specialty domain.Specialty, reportType domain.ReportType, doctorID uuid.UUID) (*domain.Report, error) {
-	// Validate CNP
-	if len(patientCNP) != 13 {
-		return nil, domain.ErrInvalidCNP
+// CreateReport creates a new report for a patient registered with the hospital
+// within one of their encounters
+func (s *ReportService) CreateReport(ctx context.Context, hospitalID, patientID, encounterID uuid.UUID, specialty domain.Specialty, reportType domain.ReportType, doctorID uuid.UUID) (*domain.Report, error) {
+	return s.createReport(ctx, hospitalID, patientID, encounterID, specialty, reportType, doctorID, nil, nil)
+}
+
+// CreateReportFromPrevious creates a draft prefilled with the given sections
+// of the patient's most recent signed report. Without sections, every section
+// is carried forward.
+func (s *ReportService) CreateReportFromPrevious(ctx context.Context, hospitalID, patientID, encounterID uuid.UUID, specialty domain.Specialty, reportType domain.ReportType, doctorID uuid.UUID, sections []domain.CarryForwardSection) (*domain.Report, error) {
+	if len(sections) == 0 {
+		sections = domain.CarryForwardSections
+	}
+	return s.createReport(ctx, hospitalID, patientID, encounterID, specialty, reportType, doctorID, sections, nil)
+}
+
+// CreateReportFromAdmission creates a draft prefilled with the initial
+// diagnoses and chronic medications of an admission imported from FHIR
+func (s *ReportService) CreateReportFromAdmission(ctx context.Context, hospitalID, patientID, encounterID uuid.UUID, specialty domain.Specialty, doctorID uuid.UUID, admission *fhir.Admission) (*domain.Report, error) {
+	return s.createReport(ctx, hospitalID, patientID, encounterID, specialty, admission.ReportType, doctorID, nil, admission)
+}
+
+// CheckNewReport validates the parameters of a new report. Clients that
+// register or admit the patient for the report check them first.
+func (s *ReportService) CheckNewReport(specialty domain.Specialty, reportType domain.ReportType, carry []domain.CarryForwardSection) error {
+	if !specialty.IsValid() {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidSpecialty, specialty)
+	}
+	if !reportType.IsValid() {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidReportType, reportType)
+	}
+	for _, section := range carry {
+		if !section.AppliesTo(reportType) {
+			return fmt.Errorf("%w: %s reports have no %s", domain.ErrInvalidCarryForward, reportType, section)
+		}
+	}
+	return nil
+}
+
+func (s *ReportService) createReport(ctx context.Context, hospitalID, patientID, encounterID uuid.UUID, specialty domain.Specialty, reportType domain.ReportType, doctorID uuid.UUID, carry []domain.CarryForwardSection, admission *fhir.Admission) (*domain.Report, error) {
+	if err := s.CheckNewReport(specialty, reportType, carry); err != nil {
+		return nil, err
+	}
+	
+	patient, err := s.patientRepo.GetByID(ctx, hospitalID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if patient, err = followMerges(ctx, s.patientRepo, patient); err != nil {
+		return nil, err
+	}
+	
+	encounter, err := s.encounterRepo.GetByID(ctx, hospitalID, encounterID)
+	if err != nil {
+		return nil, err
+	}
+	if encounter.PatientID != patient.ID {
+		return nil, fmt.Errorf("%w: encounter belongs to another patient", domain.ErrEncounterNotFound)
+	}
+	
+	// Business rule: One discharge summary per encounter
+	if reportType == domain.ReportTypeDischargeSummary {
+		if err := s.checkSingleDischargeSummary(ctx, encounter.ID); err != nil {
+			return nil, err
+		}
+	}
+	
+	report := domain.NewReport(patient, encounter, specialty, reportType, doctorID)
+	
+	// Prefill from the best matching template of the hospital; carried
+	// forward sections are applied on top
+	templates, err := s.templateRepo.List(ctx, hospitalID, false)
+	if err != nil {
+		return nil, err
+	}
+	if template := domain.MatchTemplate(templates, specialty, reportType, encounter.Location().DepartmentID); template != nil {
+		if err := report.ApplyTemplate(template); err != nil {
+			return nil, err
+		}
+	}
+	
+	if admission != nil {
+		admission.Prefill(&report.Content)
 	}
 	
-	report := domain.NewReport(hospitalID, patientCNP, firstName, lastName, specialty, reportType, doctorID)
+	if len(carry) > 0 {
+		source, err := s.latestSignedReport(ctx, patient.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := report.Content.CarryForward(source, carry); err != nil {
+			return nil, err
+		}
+	}
 	
 	if err := s.reportRepo.Create(ctx, report); err != nil {
 		return nil, err
@@ -35,58 +144,208 @@ func (s *ReportService) CreateReport(ctx context.Context, hospitalID uuid.UUID,
 	return report, nil
 }
 
+// checkTemplate checks the report against the template version it was
+// created from
+func (s *ReportService) checkTemplate(ctx context.Context, report *domain.Report) error {
+	if report.TemplateID == nil {
+		return nil
+	}
+	
+	template, err := s.templateRepo.GetVersion(ctx, report.HospitalID, *report.TemplateID, report.TemplateVersion)
+	if err != nil {
+		return err
+	}
+	return template.CheckTemplate(report.Content)
+}
+
+// latestSignedReport returns the patient's most recently signed report
+func (s *ReportService) latestSignedReport(ctx context.Context, patientID uuid.UUID) (*domain.Report, error) {
+	reports, err := s.reportRepo.ListByPatient(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+	
+	var latest *domain.Report
+	for _, r := range reports {
+		if r.Status != domain.StatusSigned || r.FinalizedAt == nil {
+			continue
+		}
+		if latest == nil || r.FinalizedAt.After(*latest.FinalizedAt) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil, domain.ErrNoPreviousReport
+	}
+	
+	return latest, nil
+}
+
 // GetReport retrieves a report by ID
 func (s *ReportService) GetReport(ctx context.Context, id uuid.UUID) (*domain.Report, error) {
 	return s.reportRepo.GetByID(ctx, id)
 }
 
-// UpdateReportContent updates the content of a report
-func (s *ReportService) UpdateReportContent(ctx context.Context, reportID uuid.UUID, content domain.ReportContent, userID uuid.UUID) error {
+// UpdateReportContent updates the content of a report and returns the
+// medication safety warnings for the saved content
+func (s *ReportService) UpdateReportContent(ctx context.Context, reportID uuid.UUID, content domain.ReportContent, userID uuid.UUID) ([]domain.ValidationWarning, error) {
 	report, err := s.reportRepo.GetByID(ctx, reportID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	
 	// Business rule: Can only edit draft reports
 	if report.Status != domain.StatusDraft {
-		return domain.ErrCannotEditNonDraft
+		return nil, domain.ErrCannotEditNonDraft
 	}
 	
+	// Patient identity and admission details in the content always come from
+	// the registry and the encounter
+	patient, err := s.patientRepo.GetByID(ctx, report.HospitalID, report.PatientID)
+	if err != nil {
+		return nil, err
+	}
+	encounter, err := s.encounterRepo.GetByID(ctx, report.HospitalID, report.EncounterID)
+	if err != nil {
+		return nil, err
+	}
+	stored := report.Content
+	report.Content = content
+	report.SetPatient(patient)
+	report.SetEncounter(encounter)
+	content = report.Content
+	
+	if err := s.linkLabTests(ctx, &content); err != nil {
+		return nil, err
+	}
+	if err := s.linkProcedures(ctx, &content); err != nil {
+		return nil, err
+	}
+	
+	doseWarnings, err := structureDoses(&content)
+	if err != nil {
+		return nil, err
+	}
+	content.KeepProvenance(stored)
+	content.KeepChecklist(stored)
+	legacy := content.KeepLegacySections(stored, report.ReportType)
+	content.Normalize(report.ReportType, legacy...)
+	
+	if content.Transfer != nil {
+		if err := s.resolveTransferDestination(ctx, report.HospitalID, &content.Transfer.Destination); err != nil {
+			return nil, err
+		}
+	}
+	
+	warnings, err := s.safety.Check(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(doseWarnings, warnings...)
+	
 	report.Content = content
 	report.LastModified = time.Now()
 	
 	if err := s.reportRepo.Update(ctx, report); err != nil {
-		return err
+		return nil, err
 	}
 	
 	// Get current version count
 	versions, err := s.reportRepo.GetVersions(ctx, reportID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	
 	// Save new version
 	versionNumber := len(versions) + 1
 	version := domain.NewReportVersion(reportID, versionNumber, content, userID, "Auto-save")
 	
-	return s.reportRepo.SaveVersion(ctx, version)
+	if err := s.reportRepo.SaveVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	
+	return warnings, nil
 }
 
-// UpdateReportStatus changes the status of a report
-func (s *ReportService) UpdateReportStatus(ctx context.Context, reportID uuid.UUID, newStatus domain.Status) error {
+// UpdateReportStatus changes the status of a report and returns any
+// non-blocking warnings raised while validating the transition.
+// overrideReason acknowledges contraindicated medication findings.
+func (s *ReportService) UpdateReportStatus(ctx context.Context, reportID uuid.UUID, newStatus domain.Status, userID uuid.UUID, overrideReason string) ([]domain.ValidationWarning, error) {
 	report, err := s.reportRepo.GetByID(ctx, reportID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	
 	// Business rule: Validate status transition
 	if !report.CanTransitionTo(newStatus) {
-		return domain.ErrInvalidStatusTransition
+		return nil, domain.ErrInvalidStatusTransition
 	}
 	
-	// Business rule: Report must be complete before finalizing
-	if newStatus == domain.StatusInReview && !report.Content.IsComplete() {
-		return domain.ErrIncompleteReport
+	if userID == uuid.Nil {
+		userID = report.CreatedBy
+	}
+	
+	var warnings []domain.ValidationWarning
+	var override *domain.AuditEntry
+	normalized := false
+	
+	if newStatus == domain.StatusInReview {
+		// Business rule: Report must be complete for its report type
+		// before finalizing
+		if err := report.Content.ValidateFor(report.ReportType); err != nil {
+			return nil, fmt.Errorf("%w: %w", domain.ErrIncompleteReport, err)
+		}
+		
+		// Business rule: Changed or stopped chronic medications need a reason
+		if err := report.Content.ValidateReconciliation(); err != nil {
+			return nil, err
+		}
+		
+		// Business rule: The template's required sections and checklist
+		// items must be completed
+		if err := s.checkTemplate(ctx, report); err != nil {
+			return nil, err
+		}
+		
+		// Business rule: Diagnoses must use known ICD-10 codes
+		content, diagnosisWarnings, err := s.normalizeDiagnoses(ctx, report)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, diagnosisWarnings...)
+		
+		// The normalized diagnoses are checked below and saved only once
+		// every check has passed
+		normalized = !reflect.DeepEqual(report.Content, content)
+		report.Content = content
+		
+		// Business rule: Contraindicated medications need an explicit override
+		safetyWarnings, err := s.safety.Check(ctx, report.Content)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, safetyWarnings...)
+		
+		if override, err = checkSafetyOverride(report, safetyWarnings, userID, overrideReason); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Signing the discharge summary discharges the patient and groups the
+	// stay for billing
+	var discharged *domain.Encounter
+	if newStatus == domain.StatusSigned && report.ReportType == domain.ReportTypeDischargeSummary {
+		if discharged, err = s.dischargeEncounter(ctx, report); err != nil {
+			return nil, err
+		}
+		report.DRG = s.groupDischarge(report)
+		if report.DRG != nil && report.DRG.IsUngroupable() {
+			warnings = append(warnings, domain.ValidationWarning{
+				Code:    domain.WarningDRGUngroupable,
+				Field:   "drg",
+				Message: report.DRG.UngroupableReason,
+			})
+		}
 	}
 	
 	report.Status = newStatus
@@ -97,7 +356,254 @@ func (s *ReportService) UpdateReportStatus(ctx context.Context, reportID uuid.UU
 		report.FinalizedAt = &now
 	}
 	
-	return s.reportRepo.Update(ctx, report)
+	// The signature makes the printed report verifiable
+	if newStatus == domain.StatusSigned {
+		if report.Signature, err = domain.NewSignature(report.Content, userID, *report.FinalizedAt); err != nil {
+			return nil, err
+		}
+	}
+	
+	// The discharge and the handoff are saved before the signed status: both
+	// are idempotent, so a failed save leaves the report unsigned and signing
+	// again completes them, while a signed report never lacks them
+	if discharged != nil {
+		if err := s.encounterRepo.Update(ctx, discharged); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Signing a transfer summary notifies the receiving department
+	if newStatus == domain.StatusSigned && report.ReportType == domain.ReportTypeTransferSummary {
+		if handoff := domain.NewHandoff(report); handoff != nil {
+			if err := s.handoffRepo.Create(ctx, handoff); err != nil {
+				return nil, err
+			}
+		}
+	}
+	
+	if normalized {
+		if err := s.saveNormalizedDiagnoses(ctx, report, userID); err != nil {
+			return nil, err
+		}
+	}
+	
+	if err := s.reportRepo.Update(ctx, report); err != nil {
+		return nil, err
+	}
+	
+	// The override is recorded once the report it allowed is saved
+	if override != nil {
+		if err := s.auditRepo.Log(ctx, override); err != nil {
+			return nil, err
+		}
+	}
+	
+	return warnings, nil
+}
+
+// groupDischarge assigns the DRG of a discharge summary. Without grouper
+// definitions no DRG is assigned. Grouping is for billing and never blocks
+// signing: a stay that cannot be grouped is recorded as ungroupable.
+func (s *ReportService) groupDischarge(report *domain.Report) *domain.DRGAssignment {
+	if s.grouper == nil {
+		return nil
+	}
+	
+	drgCase, err := domain.NewDRGCase(report.Content)
+	if err != nil {
+		return domain.UngroupableDRG(s.grouper.Version(), err)
+	}
+	drg, err := s.grouper.Group(drgCase)
+	if err != nil {
+		return domain.UngroupableDRG(s.grouper.Version(), err)
+	}
+	return drg
+}
+
+// resolveTransferDestination checks that a destination department on the
+// platform exists and records its name
+func (s *ReportService) resolveTransferDestination(ctx context.Context, hospitalID uuid.UUID, destination *domain.TransferUnit) error {
+	if destination.DepartmentID == nil {
+		return nil
+	}
+	if destination.HospitalID != nil {
+		hospitalID = *destination.HospitalID
+	}
+	
+	department, err := s.departmentRepo.GetByID(ctx, hospitalID, *destination.DepartmentID)
+	if err != nil {
+		return err
+	}
+	destination.Department = department.Name
+	return nil
+}
+
+// checkSingleDischargeSummary rejects a second discharge summary for an
+// encounter; cancelled summaries do not count
+func (s *ReportService) checkSingleDischargeSummary(ctx context.Context, encounterID uuid.UUID) error {
+	reports, err := s.reportRepo.ListByEncounter(ctx, encounterID)
+	if err != nil {
+		return err
+	}
+	for _, r := range reports {
+		if r.ReportType == domain.ReportTypeDischargeSummary && r.Status != domain.StatusCancelled {
+			return fmt.Errorf("%w: report %s", domain.ErrDuplicateDischargeSummary, r.ID)
+		}
+	}
+	return nil
+}
+
+// dischargeEncounter closes the encounter of a discharge summary at the
+// summary's discharge date and returns it for saving. An encounter already
+// discharged is left as is and nil is returned.
+func (s *ReportService) dischargeEncounter(ctx context.Context, report *domain.Report) (*domain.Encounter, error) {
+	encounter, err := s.encounterRepo.GetByID(ctx, report.HospitalID, report.EncounterID)
+	if err != nil {
+		return nil, err
+	}
+	if encounter.Status != domain.EncounterActive {
+		return nil, nil
+	}
+	
+	if err := encounter.Discharge(report.Content.PatientData.DischargeDate); err != nil {
+		return nil, err
+	}
+	return encounter, nil
+}
+
+// validateDiagnoses resolves every diagnosis against the ICD-10 reference table.
+// Unknown codes are rejected; descriptions are replaced with the canonical text
+// and category-level codes that have subdivisions produce a warning.
+func (s *ReportService) validateDiagnoses(ctx context.Context, diagnosis domain.DiagnosisSection) (domain.DiagnosisSection, []domain.ValidationWarning, error) {
+	var warnings []domain.ValidationWarning
+	
+	resolve := func(field string, code domain.ICD10Code) (domain.ICD10Code, error) {
+		normalized, codeWarnings, err := s.resolveDiagnosis(ctx, field, code)
+		warnings = append(warnings, codeWarnings...)
+		return normalized, err
+	}
+	
+	normalized := diagnosis
+	
+	primary, err := resolve("diagnosis.primary_diagnosis", diagnosis.PrimaryDiagnosis)
+	if err != nil {
+		return diagnosis, nil, err
+	}
+	normalized.PrimaryDiagnosis = primary
+	
+	if diagnosis.SecondaryDiagnoses != nil {
+		normalized.SecondaryDiagnoses = make([]domain.ICD10Code, len(diagnosis.SecondaryDiagnoses))
+	}
+	for i, code := range diagnosis.SecondaryDiagnoses {
+		secondary, err := resolve(fmt.Sprintf("diagnosis.secondary_diagnoses[%d]", i), code)
+		if err != nil {
+			return diagnosis, nil, err
+		}
+		normalized.SecondaryDiagnoses[i] = secondary
+	}
+	
+	return normalized, warnings, nil
+}
+
+// normalizeDiagnoses validates the ICD-10 codes of the report's content
+// model and returns the content with the reference descriptions
+func (s *ReportService) normalizeDiagnoses(ctx context.Context, report *domain.Report) (domain.ReportContent, []domain.ValidationWarning, error) {
+	content := report.Content
+	
+	if report.ReportType != domain.ReportTypeOperativeNote {
+		diagnosis, warnings, err := s.validateDiagnoses(ctx, content.Diagnosis)
+		if err != nil {
+			return content, nil, err
+		}
+		content.Diagnosis = diagnosis
+		return content, warnings, nil
+	}
+	
+	note := *content.OperativeNote
+	preOp, warnings, err := s.resolveDiagnosis(ctx, "operative_note.pre_op_diagnosis", note.PreOpDiagnosis)
+	if err != nil {
+		return content, nil, err
+	}
+	postOp, postOpWarnings, err := s.resolveDiagnosis(ctx, "operative_note.post_op_diagnosis", note.PostOpDiagnosis)
+	if err != nil {
+		return content, nil, err
+	}
+	
+	note.PreOpDiagnosis, note.PostOpDiagnosis = preOp, postOp
+	content.OperativeNote = &note
+	return content, append(warnings, postOpWarnings...), nil
+}
+
+// resolveDiagnosis looks up a code in the ICD-10 reference, rejecting unknown
+// and retired codes
+func (s *ReportService) resolveDiagnosis(ctx context.Context, field string, code domain.ICD10Code) (domain.ICD10Code, []domain.ValidationWarning, error) {
+	var warnings []domain.ValidationWarning
+	
+	ref, err := s.refRepo.GetICD10ByCode(ctx, strings.ToUpper(strings.TrimSpace(code.Code)))
+	if err != nil {
+		if errors.Is(err, domain.ErrReferenceNotFound) {
+			return code, nil, fmt.Errorf("%w: %s", domain.ErrUnknownDiagnosisCode, code.Code)
+		}
+		return code, nil, err
+	}
+	if !ref.Active {
+		return code, nil, fmt.Errorf("%w: %s", domain.ErrRetiredDiagnosisCode, ref.Code)
+	}
+	
+	if !ref.Billable {
+		warnings = append(warnings, domain.ValidationWarning{
+			Code:    domain.WarningNonBillableDiagnosis,
+			Field:   field,
+			Message: fmt.Sprintf("%s is a category-level code; use a more specific subcategory for billing", ref.Code),
+		})
+	}
+	if code.Description != ref.DescriptionRO {
+		warnings = append(warnings, domain.ValidationWarning{
+			Code:    domain.WarningDiagnosisNormalized,
+			Field:   field,
+			Message: fmt.Sprintf("Description for %s replaced with the reference text", ref.Code),
+		})
+	}
+	
+	return domain.ICD10Code{Code: ref.Code, Description: ref.DescriptionRO}, warnings, nil
+}
+
+// checkSafetyOverride blocks contraindicated findings unless a reason is
+// given, in which case it returns the audit entry recording the override.
+// The entry is kept on the patient, so that it outlives the draft if it is
+// deleted.
+func checkSafetyOverride(report *domain.Report, warnings []domain.ValidationWarning, userID uuid.UUID, overrideReason string) (*domain.AuditEntry, error) {
+	var blocking []string
+	for _, w := range warnings {
+		if w.Severity == string(domain.InteractionContraindicated) {
+			blocking = append(blocking, w.Message)
+		}
+	}
+	if len(blocking) == 0 {
+		return nil, nil
+	}
+	
+	if strings.TrimSpace(overrideReason) == "" {
+		return nil, domain.ErrContraindicatedInteraction
+	}
+	
+	return domain.NewPatientAuditEntry(report.PatientID, report.ID, domain.AuditInteractionOverride, map[string]interface{}{
+		"report_id": report.ID.String(),
+		"reason":    overrideReason,
+		"findings":  blocking,
+	}, userID), nil
+}
+
+// saveNormalizedDiagnoses stores the content with normalized diagnoses as a
+// new version
+func (s *ReportService) saveNormalizedDiagnoses(ctx context.Context, report *domain.Report, userID uuid.UUID) error {
+	versions, err := s.reportRepo.GetVersions(ctx, report.ID)
+	if err != nil {
+		return err
+	}
+	
+	version := domain.NewReportVersion(report.ID, len(versions)+1, report.Content, userID, "Diagnoses normalized to ICD-10 reference")
+	return s.reportRepo.SaveVersion(ctx, version)
 }
 
 // ListReports lists reports for a doctor with filtering
@@ -112,6 +618,11 @@ func (s *ReportService) ListReports(ctx context.Context, doctorID uuid.UUID, sta
 	return s.reportRepo.List(ctx, doctorID, status, limit, offset)
 }
 
+// DiagnosisStatisticsByBlock counts signed reports per ICD-10 block of the primary diagnosis
+func (s *ReportService) DiagnosisStatisticsByBlock(ctx context.Context, hospitalID uuid.UUID, from, to *time.Time) ([]domain.ICD10BlockStatistic, error) {
+	return s.reportRepo.CountPrimaryDiagnosesByBlock(ctx, hospitalID, from, to)
+}
+
 // DeleteReport deletes a report (only drafts)
 func (s *ReportService) DeleteReport(ctx context.Context, reportID uuid.UUID) error {
 	report, err := s.reportRepo.GetByID(ctx, reportID)
@@ -178,3 +689,155 @@ func (s *ReportService) RestoreVersion(ctx context.Context, reportID uuid.UUID,
 	
 	return s.reportRepo.SaveVersion(ctx, newVersion)
 }
+
+// structureDoses parses the free-text sig of medications without a structured
+// dose. Explicit doses must be valid; sigs that cannot be parsed are kept as
+// written and reported so the doctor can fill in the dose.
+func structureDoses(content *domain.ReportContent) ([]domain.ValidationWarning, error) {
+	var warnings []domain.ValidationWarning
+	
+	lists := []struct {
+		field string
+		meds  []domain.Medication
+	}{
+		{"treatment.medications", content.Treatment.Medications},
+		{"medication_reconciliation.pre_admission", content.MedicationReconciliation.PreAdmission},
+		{"medication_reconciliation.discharge", content.MedicationReconciliation.Discharge},
+	}
+	
+	for _, list := range lists {
+		meds := list.meds
+		for i := range meds {
+			field := fmt.Sprintf("%s[%d]", list.field, i)
+			if meds[i].Dose != nil {
+				if err := meds[i].Dose.Validate(); err != nil {
+					return nil, fmt.Errorf("%s: %w", field, err)
+				}
+				continue
+			}
+			
+			sig := strings.TrimSpace(meds[i].Dosage + " " + meds[i].Frequency)
+			if sig == "" {
+				continue
+			}
+			
+			dose, err := domain.ParseSig(sig)
+			if err != nil {
+				warnings = append(warnings, domain.ValidationWarning{
+					Code:     domain.WarningUnstructuredDose,
+					Field:    field + ".dose",
+					Message:  fmt.Sprintf("Posologia pentru %s nu a putut fi interpretată: %q", meds[i].Name, sig),
+					Severity: string(domain.InteractionMinor),
+				})
+				continue
+			}
+			if route, ok := domain.ParseRoute(meds[i].Route); ok {
+				dose.Route = route
+			}
+			meds[i].Dose = dose
+		}
+	}
+	
+	return warnings, nil
+}
+
+// linkProcedures checks coded procedures against the ACHI classification and
+// takes names and operative descriptions from the catalog
+func (s *ReportService) linkProcedures(ctx context.Context, content *domain.ReportContent) error {
+	procedures := content.Treatment.Procedures
+	for i := range procedures {
+		if procedures[i].Code == "" {
+			continue
+		}
+		
+		ref, err := s.resolveProcedure(ctx, procedures[i].Code)
+		if err != nil {
+			return err
+		}
+		procedures[i].Code = ref.Code
+		if procedures[i].Name == "" {
+			procedures[i].Name = ref.TitleRO
+		}
+	}
+	
+	if content.OperativeNote == nil {
+		return nil
+	}
+	codes := content.OperativeNote.ProcedureCodes
+	for i := range codes {
+		if strings.TrimSpace(codes[i].Code) == "" {
+			continue
+		}
+		
+		ref, err := s.resolveProcedure(ctx, codes[i].Code)
+		if err != nil {
+			return err
+		}
+		codes[i] = domain.ProcedureCode{Code: ref.Code, Description: ref.TitleRO}
+	}
+	
+	return nil
+}
+
+func (s *ReportService) resolveProcedure(ctx context.Context, code string) (*domain.ProcedureReference, error) {
+	normalized, ok := domain.NormalizeProcedureCode(code)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownProcedureCode, code)
+	}
+	
+	ref, err := s.refRepo.GetProcedureByCode(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, domain.ErrReferenceNotFound) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnknownProcedureCode, code)
+		}
+		return nil, err
+	}
+	if !ref.Active {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRetiredProcedureCode, ref.Code)
+	}
+	return ref, nil
+}
+
+// linkLabTests resolves catalog-coded lab tests and fills in missing names,
+// units and the reference range matching the patient's sex and age
+func (s *ReportService) linkLabTests(ctx context.Context, content *domain.ReportContent) error {
+	sex := domain.SexFromCNP(content.PatientData.CNP)
+	birthDate := content.PatientData.BirthDate
+	if birthDate.IsZero() {
+		birthDate, _ = domain.BirthDateFromCNP(content.PatientData.CNP)
+	}
+	
+	tests := content.LabResults.LaboratoryTests
+	for i := range tests {
+		if tests[i].LOINCCode == "" {
+			continue
+		}
+		
+		ref, err := s.refRepo.GetLabTestByCode(ctx, tests[i].LOINCCode)
+		if err != nil {
+			if errors.Is(err, domain.ErrReferenceNotFound) {
+				return fmt.Errorf("%w: %s", domain.ErrUnknownLabTest, tests[i].LOINCCode)
+			}
+			return err
+		}
+		
+		if tests[i].Name == "" {
+			tests[i].Name = ref.NameRO
+		}
+		if tests[i].Unit == "" {
+			tests[i].Unit = ref.DefaultUnit
+		}
+		if tests[i].ReferenceLow == nil && tests[i].ReferenceHigh == nil && !birthDate.IsZero() {
+			takenAt := tests[i].Date
+			if takenAt.IsZero() {
+				takenAt = time.Now()
+			}
+			if rng := ref.RangeFor(sex, domain.AgeAt(birthDate, takenAt)); rng != nil {
+				tests[i].ReferenceLow = rng.Low
+				tests[i].ReferenceHigh = rng.High
+			}
+		}
+	}
+	
+	return nil
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lab_test_reference_ranges;
+DROP TABLE IF EXISTS lab_tests;
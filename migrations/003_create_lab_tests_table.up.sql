@@ -0,0 +1,64 @@
+-- ============================================================================
+-- LOINC-coded laboratory test catalog
+-- ============================================================================
+CREATE TABLE lab_tests (
+    loinc_code VARCHAR(10) PRIMARY KEY,
+    name_ro TEXT NOT NULL,
+    default_unit VARCHAR(30),
+    
+    search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('romanian', name_ro)
+    ) STORED
+);
+
+CREATE INDEX idx_lab_tests_search ON lab_tests USING GIN(search_vector);
+
+-- Default reference ranges; NULL sex or age bounds apply to everyone
+CREATE TABLE lab_test_reference_ranges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    loinc_code VARCHAR(10) NOT NULL REFERENCES lab_tests(loinc_code) ON DELETE CASCADE,
+    sex CHAR(1),
+    age_min_years INT,
+    age_max_years INT,
+    low NUMERIC,
+    high NUMERIC,
+    
+    CONSTRAINT chk_lab_range_sex CHECK (sex IN ('M', 'F'))
+);
+
+CREATE INDEX idx_lab_ranges_code ON lab_test_reference_ranges(loinc_code);
+
+-- Seed common tests
+INSERT INTO lab_tests (loinc_code, name_ro, default_unit) VALUES
+('718-7', 'Hemoglobină', 'g/dL'),
+('6690-2', 'Leucocite', '10^3/µL'),
+('777-3', 'Trombocite', '10^3/µL'),
+('2345-7', 'Glicemie', 'mg/dL'),
+('2160-0', 'Creatinină serică', 'mg/dL'),
+('3094-0', 'Uree serică', 'mg/dL'),
+('2951-2', 'Sodiu seric', 'mmol/L'),
+('2823-3', 'Potasiu seric', 'mmol/L'),
+('1988-5', 'Proteina C reactivă', 'mg/L'),
+('4548-4', 'Hemoglobină glicată (HbA1c)', '%'),
+('2093-3', 'Colesterol total', 'mg/dL'),
+('1742-6', 'ALT (TGP)', 'U/L'),
+('1920-8', 'AST (TGO)', 'U/L');
+
+INSERT INTO lab_test_reference_ranges (loinc_code, sex, age_min_years, age_max_years, low, high) VALUES
+('718-7', 'M', 18, NULL, 13.5, 17.5),
+('718-7', 'F', 18, NULL, 12.0, 15.5),
+('718-7', NULL, NULL, 17, 11.0, 15.5),
+('6690-2', NULL, NULL, NULL, 4.0, 10.0),
+('777-3', NULL, NULL, NULL, 150, 400),
+('2345-7', NULL, NULL, NULL, 70, 100),
+('2160-0', 'M', 18, NULL, 0.7, 1.3),
+('2160-0', 'F', 18, NULL, 0.6, 1.1),
+('3094-0', NULL, NULL, NULL, 15, 45),
+('2951-2', NULL, NULL, NULL, 135, 145),
+('2823-3', NULL, NULL, NULL, 3.5, 5.1),
+('1988-5', NULL, NULL, NULL, 0, 5),
+('4548-4', NULL, NULL, NULL, 4.0, 5.6),
+('2093-3', NULL, NULL, NULL, NULL, 200),
+('1742-6', 'M', 18, NULL, NULL, 41),
+('1742-6', 'F', 18, NULL, NULL, 33),
+('1920-8', NULL, NULL, NULL, NULL, 40);
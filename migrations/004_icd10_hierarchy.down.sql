@@ -0,0 +1,13 @@
+-- Drop the hierarchy first: the subcategories seeded by 001 reference the
+-- categories below through parent_code
+ALTER TABLE icd10_codes
+    DROP CONSTRAINT IF EXISTS chk_icd10_level,
+    DROP COLUMN IF EXISTS level,
+    DROP COLUMN IF EXISTS parent_code,
+    DROP COLUMN IF EXISTS block_code,
+    DROP COLUMN IF EXISTS chapter_code;
+
+DELETE FROM icd10_codes WHERE code IN ('J15', 'J18', 'I25', 'I50', 'E78', 'K21', 'K29', 'M54', 'M79', 'R06', 'R50');
+
+DROP TABLE IF EXISTS icd10_blocks;
+DROP TABLE IF EXISTS icd10_chapters;
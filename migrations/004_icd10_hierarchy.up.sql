@@ -0,0 +1,76 @@
+-- ============================================================================
+-- ICD-10 hierarchy: chapter → block → category → subcategory
+-- ============================================================================
+CREATE TABLE icd10_chapters (
+    code VARCHAR(5) PRIMARY KEY,
+    title_ro TEXT NOT NULL,
+    range_start VARCHAR(3) NOT NULL,
+    range_end VARCHAR(3) NOT NULL
+);
+
+CREATE TABLE icd10_blocks (
+    code VARCHAR(10) PRIMARY KEY,
+    chapter_code VARCHAR(5) NOT NULL REFERENCES icd10_chapters(code),
+    title_ro TEXT NOT NULL,
+    range_start VARCHAR(3) NOT NULL,
+    range_end VARCHAR(3) NOT NULL
+);
+
+CREATE INDEX idx_icd10_blocks_chapter ON icd10_blocks(chapter_code, range_start);
+
+ALTER TABLE icd10_codes
+    ADD COLUMN chapter_code VARCHAR(5) REFERENCES icd10_chapters(code),
+    ADD COLUMN block_code VARCHAR(10) REFERENCES icd10_blocks(code),
+    ADD COLUMN parent_code VARCHAR(10) REFERENCES icd10_codes(code),
+    ADD COLUMN level VARCHAR(12) NOT NULL DEFAULT 'category',
+    ADD CONSTRAINT chk_icd10_level CHECK (level IN ('category', 'subcategory'));
+
+CREATE INDEX idx_icd10_parent ON icd10_codes(parent_code);
+CREATE INDEX idx_icd10_block ON icd10_codes(block_code);
+CREATE INDEX idx_icd10_chapter ON icd10_codes(chapter_code);
+
+-- Seed the chapters and blocks covering the seeded codes
+INSERT INTO icd10_chapters (code, title_ro, range_start, range_end) VALUES
+('IV', 'Boli endocrine, de nutriție și metabolism', 'E00', 'E90'),
+('IX', 'Boli ale aparatului circulator', 'I00', 'I99'),
+('X', 'Boli ale aparatului respirator', 'J00', 'J99'),
+('XI', 'Boli ale aparatului digestiv', 'K00', 'K93'),
+('XIII', 'Boli ale sistemului osteo-articular, ale mușchilor și țesutului conjunctiv', 'M00', 'M99'),
+('XVIII', 'Simptome, semne și rezultate anormale ale investigațiilor clinice și de laborator', 'R00', 'R99');
+
+INSERT INTO icd10_blocks (code, chapter_code, title_ro, range_start, range_end) VALUES
+('E10-E14', 'IV', 'Diabetul zaharat', 'E10', 'E14'),
+('E70-E90', 'IV', 'Tulburări metabolice', 'E70', 'E90'),
+('I10-I15', 'IX', 'Boli hipertensive', 'I10', 'I15'),
+('I20-I25', 'IX', 'Cardiopatii ischemice', 'I20', 'I25'),
+('I30-I52', 'IX', 'Alte forme de boli cardiace', 'I30', 'I52'),
+('J09-J18', 'X', 'Gripa și pneumonia', 'J09', 'J18'),
+('K20-K31', 'XI', 'Boli ale esofagului, stomacului și duodenului', 'K20', 'K31'),
+('M50-M54', 'XIII', 'Alte dorsopatii', 'M50', 'M54'),
+('M60-M79', 'XIII', 'Afecțiuni ale țesuturilor moi', 'M60', 'M79'),
+('R00-R09', 'XVIII', 'Simptome și semne privind aparatele circulator și respirator', 'R00', 'R09'),
+('R50-R69', 'XVIII', 'Simptome și semne generale', 'R50', 'R69');
+
+-- Categories that only existed implicitly through their subcategories
+INSERT INTO icd10_codes (code, description_ro, category) VALUES
+('J15', 'Pneumonie bacteriană, neclasificată altundeva', 'Respiratory'),
+('J18', 'Pneumonie cu germeni nespecificați', 'Respiratory'),
+('I25', 'Cardiopatie ischemică cronică', 'Cardiovascular'),
+('I50', 'Insuficiență cardiacă', 'Cardiovascular'),
+('E78', 'Tulburări ale metabolismului lipoproteinelor și alte lipidemii', 'Endocrine'),
+('K21', 'Boala de reflux gastro-esofagian', 'Digestive'),
+('K29', 'Gastrită și duodenită', 'Digestive'),
+('M54', 'Dorsalgie', 'Musculoskeletal'),
+('M79', 'Alte afecțiuni ale țesuturilor moi, neclasificate altundeva', 'Musculoskeletal'),
+('R06', 'Anomalii ale respirației', 'Symptoms'),
+('R50', 'Febră de origine necunoscută', 'Symptoms')
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE icd10_codes
+SET level = 'subcategory', parent_code = split_part(code, '.', 1)
+WHERE code LIKE '%.%';
+
+UPDATE icd10_codes c
+SET block_code = b.code, chapter_code = b.chapter_code
+FROM icd10_blocks b
+WHERE left(c.code, 3) BETWEEN b.range_start AND b.range_end;
@@ -0,0 +1,6 @@
+ALTER TABLE icd10_codes
+    DROP COLUMN IF EXISTS retired_in,
+    DROP COLUMN IF EXISTS release_version,
+    DROP COLUMN IF EXISTS active;
+
+DROP TABLE IF EXISTS icd10_releases;
@@ -0,0 +1,19 @@
+-- ============================================================================
+-- ICD-10 release tracking for bulk imports
+-- ============================================================================
+CREATE TABLE icd10_releases (
+    version VARCHAR(30) PRIMARY KEY,
+    source_format VARCHAR(10) NOT NULL,
+    imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    added_count INT NOT NULL DEFAULT 0,
+    changed_count INT NOT NULL DEFAULT 0,
+    retired_count INT NOT NULL DEFAULT 0
+);
+
+-- Codes dropped from a release are kept for existing reports but marked inactive
+ALTER TABLE icd10_codes
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN release_version VARCHAR(30) REFERENCES icd10_releases(version),
+    ADD COLUMN retired_in VARCHAR(30) REFERENCES icd10_releases(version);
+
+CREATE INDEX idx_icd10_active ON icd10_codes(active);
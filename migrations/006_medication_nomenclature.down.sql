@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_medications_substance;
+DROP INDEX IF EXISTS idx_medications_atc;
+DROP INDEX IF EXISTS idx_medications_search;
+
+ALTER TABLE medications DROP COLUMN search_vector;
+ALTER TABLE medications ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('romanian', name || ' ' || active_substance)
+) STORED;
+CREATE INDEX idx_medications_search ON medications USING GIN(search_vector);
+
+ALTER TABLE medications
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS prescription_status,
+    DROP COLUMN IF EXISTS package,
+    DROP COLUMN IF EXISTS atc_code,
+    DROP COLUMN IF EXISTS authorization_code;
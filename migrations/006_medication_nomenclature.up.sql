@@ -0,0 +1,33 @@
+-- ============================================================================
+-- National medicines nomenclature fields
+-- ============================================================================
+ALTER TABLE medications
+    ALTER COLUMN form TYPE TEXT,
+    ADD COLUMN authorization_code VARCHAR(30) UNIQUE,
+    ADD COLUMN atc_code VARCHAR(10),
+    ADD COLUMN package TEXT,
+    ADD COLUMN prescription_status VARCHAR(20),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Rebuild the search vector so ATC codes are searchable as well
+DROP INDEX IF EXISTS idx_medications_search;
+ALTER TABLE medications DROP COLUMN search_vector;
+ALTER TABLE medications ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('romanian', name || ' ' || active_substance || ' ' || coalesce(atc_code, ''))
+) STORED;
+
+CREATE INDEX idx_medications_search ON medications USING GIN(search_vector);
+CREATE INDEX idx_medications_atc ON medications(atc_code varchar_pattern_ops);
+CREATE INDEX idx_medications_substance ON medications(lower(active_substance));
+
+-- ATC codes for the seeded products
+UPDATE medications SET atc_code = 'J01CR02' WHERE name = 'Augmentin';
+UPDATE medications SET atc_code = 'J01CA04' WHERE name = 'Amoxicilină';
+UPDATE medications SET atc_code = 'N02BE01' WHERE name = 'Paracetamol';
+UPDATE medications SET atc_code = 'M01AE01' WHERE name = 'Ibuprofen';
+UPDATE medications SET atc_code = 'A10BA02' WHERE name = 'Metformin';
+UPDATE medications SET atc_code = 'C09AA02' WHERE name = 'Enalapril';
+UPDATE medications SET atc_code = 'C10AA05' WHERE name = 'Atorvastatină';
+UPDATE medications SET atc_code = 'A02BC01' WHERE name = 'Omeprazol';
+UPDATE medications SET atc_code = 'C03CA01' WHERE name = 'Furosemid';
+UPDATE medications SET atc_code = 'B01AC06' WHERE name = 'Aspirină';
@@ -0,0 +1 @@
+ALTER TABLE medications DROP COLUMN IF EXISTS max_daily_dose_mg;
@@ -0,0 +1,17 @@
+-- ============================================================================
+-- Maximum daily dose per product, used by the dose checks on prescriptions.
+-- For combination products the limit refers to the first active substance.
+-- ============================================================================
+ALTER TABLE medications ADD COLUMN max_daily_dose_mg NUMERIC(10,3);
+
+-- Adult limits for the seeded products
+UPDATE medications SET max_daily_dose_mg = 4000 WHERE name = 'Augmentin';
+UPDATE medications SET max_daily_dose_mg = 6000 WHERE name = 'Amoxicilină';
+UPDATE medications SET max_daily_dose_mg = 4000 WHERE name = 'Paracetamol';
+UPDATE medications SET max_daily_dose_mg = 2400 WHERE name = 'Ibuprofen';
+UPDATE medications SET max_daily_dose_mg = 3000 WHERE name = 'Metformin';
+UPDATE medications SET max_daily_dose_mg = 40 WHERE name = 'Enalapril';
+UPDATE medications SET max_daily_dose_mg = 80 WHERE name = 'Atorvastatină';
+UPDATE medications SET max_daily_dose_mg = 120 WHERE name = 'Omeprazol';
+UPDATE medications SET max_daily_dose_mg = 600 WHERE name = 'Furosemid';
+UPDATE medications SET max_daily_dose_mg = 4000 WHERE name = 'Aspirină';
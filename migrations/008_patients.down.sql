@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reports_patient;
+ALTER TABLE reports DROP COLUMN IF EXISTS patient_id;
+DROP TABLE IF EXISTS patients;
@@ -0,0 +1,45 @@
+-- ============================================================================
+-- Patient registry
+-- ============================================================================
+CREATE TABLE patients (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hospital_id UUID NOT NULL,
+    cnp CHAR(13) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    -- lower-cased, diacritics-free "last first", maintained by the application
+    search_name TEXT NOT NULL,
+    birth_date DATE,
+    sex CHAR(1),
+    phone VARCHAR(30),
+    address TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (hospital_id, cnp)
+);
+
+CREATE INDEX idx_patients_search_name ON patients(hospital_id, search_name text_pattern_ops);
+CREATE INDEX idx_patients_cnp ON patients(hospital_id, cnp bpchar_pattern_ops);
+
+-- Register the patients of existing reports, keeping the most recent spelling
+INSERT INTO patients (hospital_id, cnp, first_name, last_name, search_name)
+SELECT DISTINCT ON (hospital_id, patient_cnp)
+    hospital_id,
+    patient_cnp,
+    patient_first_name,
+    patient_last_name,
+    lower(translate(patient_last_name || ' ' || patient_first_name, 'ĂÂÎȘŞȚŢăâîșşțţ', 'AAISSTTaaisstt'))
+FROM reports
+ORDER BY hospital_id, patient_cnp, created_at DESC;
+
+ALTER TABLE reports ADD COLUMN patient_id UUID REFERENCES patients(id);
+
+UPDATE reports r
+SET patient_id = p.id
+FROM patients p
+WHERE p.hospital_id = r.hospital_id AND p.cnp = r.patient_cnp;
+
+ALTER TABLE reports ALTER COLUMN patient_id SET NOT NULL;
+
+CREATE INDEX idx_reports_patient ON reports(patient_id, created_at DESC);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_audit_patient;
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_subject;
+ALTER TABLE audit_log DROP COLUMN IF EXISTS patient_id;
+DELETE FROM audit_log WHERE report_id IS NULL;
+ALTER TABLE audit_log ALTER COLUMN report_id SET NOT NULL;
+
+DROP TABLE IF EXISTS patient_merges;
+DROP TABLE IF EXISTS patient_duplicate_candidates;
+
+DROP INDEX IF EXISTS idx_patients_merged_into;
+ALTER TABLE patients DROP COLUMN IF EXISTS merged_into_id;
@@ -0,0 +1,49 @@
+-- ============================================================================
+-- Duplicate patient review queue and merges
+-- ============================================================================
+ALTER TABLE patients ADD COLUMN merged_into_id UUID REFERENCES patients(id);
+
+CREATE INDEX idx_patients_merged_into ON patients(merged_into_id) WHERE merged_into_id IS NOT NULL;
+
+CREATE TABLE patient_duplicate_candidates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hospital_id UUID NOT NULL,
+    -- canonical order, so a rescan finds the existing candidate
+    patient_a_id UUID NOT NULL REFERENCES patients(id),
+    patient_b_id UUID NOT NULL REFERENCES patients(id),
+    reason VARCHAR(30) NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    suggested_survivor_id UUID NOT NULL REFERENCES patients(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_by UUID,
+    reviewed_at TIMESTAMPTZ,
+
+    UNIQUE (patient_a_id, patient_b_id),
+    CHECK (patient_a_id <> patient_b_id)
+);
+
+CREATE INDEX idx_duplicate_candidates_queue ON patient_duplicate_candidates(hospital_id, status, detected_at DESC);
+
+CREATE TABLE patient_merges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hospital_id UUID NOT NULL,
+    survivor_id UUID NOT NULL REFERENCES patients(id),
+    merged_id UUID NOT NULL REFERENCES patients(id),
+    candidate_id UUID REFERENCES patient_duplicate_candidates(id),
+    -- reports moved from merged_id, restored on revert
+    report_ids UUID[] NOT NULL DEFAULT '{}',
+    merged_by UUID NOT NULL,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reverted_by UUID,
+    reverted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_patient_merges_hospital ON patient_merges(hospital_id, merged_at DESC);
+
+-- Patient-level audit events (merges) are not tied to a single report
+ALTER TABLE audit_log ALTER COLUMN report_id DROP NOT NULL;
+ALTER TABLE audit_log ADD COLUMN patient_id UUID REFERENCES patients(id);
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_subject CHECK (report_id IS NOT NULL OR patient_id IS NOT NULL);
+
+CREATE INDEX idx_audit_patient ON audit_log(patient_id, timestamp DESC) WHERE patient_id IS NOT NULL;
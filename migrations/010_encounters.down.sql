@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_reports_one_discharge_summary;
+DROP INDEX IF EXISTS idx_reports_encounter;
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_encounter_id_fkey;
+ALTER TABLE reports DROP COLUMN IF EXISTS encounter_id;
+
+DROP TABLE IF EXISTS encounter_stays;
+DROP TABLE IF EXISTS encounters;
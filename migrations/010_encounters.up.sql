@@ -0,0 +1,84 @@
+-- ============================================================================
+-- Encounters (admission episodes) with department, ward and bed stays
+-- ============================================================================
+CREATE TABLE encounters (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hospital_id UUID NOT NULL,
+    patient_id UUID NOT NULL REFERENCES patients(id),
+    -- hospital's admission register number (foaie de observatie)
+    admission_number VARCHAR(50),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    admitted_at TIMESTAMPTZ NOT NULL,
+    discharged_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (hospital_id, admission_number),
+    CHECK (discharged_at IS NULL OR discharged_at >= admitted_at)
+);
+
+CREATE INDEX idx_encounters_patient ON encounters(patient_id, admitted_at DESC);
+
+CREATE TABLE encounter_stays (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    encounter_id UUID NOT NULL REFERENCES encounters(id) ON DELETE CASCADE,
+    department VARCHAR(100) NOT NULL,
+    ward VARCHAR(20),
+    bed VARCHAR(20),
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_encounter_stays_encounter ON encounter_stays(encounter_id, started_at);
+
+-- Every existing report becomes its own encounter, built from the admission
+-- fields of its latest content
+ALTER TABLE reports ADD COLUMN encounter_id UUID;
+UPDATE reports SET encounter_id = uuid_generate_v4();
+
+CREATE TEMP TABLE report_admissions AS
+SELECT
+    r.encounter_id,
+    r.hospital_id,
+    r.patient_id,
+    r.created_at,
+    r.last_modified,
+    COALESCE(NULLIF(pd->>'admission_date', '0001-01-01T00:00:00Z')::timestamptz, r.created_at) AS admitted_at,
+    NULLIF(pd->>'discharge_date', '0001-01-01T00:00:00Z')::timestamptz AS discharged_at,
+    COALESCE(NULLIF(pd->>'department', ''), 'Nespecificat') AS department,
+    NULLIF(pd->>'ward', '') AS ward,
+    NULLIF(pd->>'bed', '') AS bed
+FROM reports r
+LEFT JOIN LATERAL (
+    SELECT content->'patient_data' AS pd
+    FROM report_versions
+    WHERE report_id = r.id
+    ORDER BY version_number DESC
+    LIMIT 1
+) v ON true;
+
+-- Discharge dates entered before the admission date are dropped
+UPDATE report_admissions SET discharged_at = NULL WHERE discharged_at < admitted_at;
+
+INSERT INTO encounters (id, hospital_id, patient_id, status, admitted_at, discharged_at, created_at, updated_at)
+SELECT
+    encounter_id, hospital_id, patient_id,
+    CASE WHEN discharged_at IS NULL THEN 'active' ELSE 'discharged' END,
+    admitted_at, discharged_at, created_at, last_modified
+FROM report_admissions;
+
+INSERT INTO encounter_stays (encounter_id, department, ward, bed, started_at, ended_at)
+SELECT encounter_id, department, ward, bed, admitted_at, discharged_at
+FROM report_admissions;
+
+DROP TABLE report_admissions;
+
+ALTER TABLE reports ALTER COLUMN encounter_id SET NOT NULL;
+ALTER TABLE reports ADD CONSTRAINT reports_encounter_id_fkey FOREIGN KEY (encounter_id) REFERENCES encounters(id);
+
+CREATE INDEX idx_reports_encounter ON reports(encounter_id, created_at);
+
+-- At most one discharge summary per encounter; transfer summaries and
+-- operative notes are not limited
+CREATE UNIQUE INDEX idx_reports_one_discharge_summary ON reports(encounter_id)
+    WHERE report_type = 'discharge_summary' AND status <> 'cancelled';
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_encounter_stays_department;
+ALTER TABLE encounter_stays DROP COLUMN IF EXISTS department_id;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_hospital_id_fkey;
+ALTER TABLE users ALTER COLUMN hospital_id TYPE VARCHAR(50) USING hospital_id::text;
+UPDATE users SET hospital_id = '' WHERE hospital_id IS NULL;
+ALTER TABLE users ALTER COLUMN hospital_id SET NOT NULL;
+
+ALTER TABLE encounters DROP CONSTRAINT IF EXISTS encounters_hospital_id_fkey;
+ALTER TABLE patients DROP CONSTRAINT IF EXISTS patients_hospital_id_fkey;
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_hospital_id_fkey;
+
+DROP TABLE IF EXISTS departments;
+DROP TABLE IF EXISTS hospitals;
@@ -0,0 +1,67 @@
+-- ============================================================================
+-- Hospital and department master data
+-- ============================================================================
+CREATE TABLE hospitals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    -- fiscal code without the RO prefix, and SIRUTA code of the locality
+    cui VARCHAR(10),
+    siruta VARCHAR(6),
+    street VARCHAR(200),
+    city VARCHAR(100),
+    county VARCHAR(50),
+    postal_code CHAR(6),
+    phone VARCHAR(30),
+    email VARCHAR(255),
+    -- letterhead logo (PNG or JPEG)
+    logo BYTEA,
+    logo_content_type VARCHAR(50),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (cui)
+);
+
+CREATE TABLE departments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hospital_id UUID NOT NULL REFERENCES hospitals(id) ON DELETE CASCADE,
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    specialty VARCHAR(50),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (hospital_id, code)
+);
+
+-- The demo hospital used by the test data
+INSERT INTO hospitals (id, name, city, county)
+VALUES ('550e8400-e29b-41d4-a716-446655440000', 'Spitalul Clinic Demo', 'București', 'București');
+
+-- Register every hospital already referenced, to be completed by an admin
+INSERT INTO hospitals (id, name)
+SELECT DISTINCT hospital_id, 'Spital ' || left(hospital_id::text, 8)
+FROM (
+    SELECT hospital_id FROM reports
+    UNION SELECT hospital_id FROM patients
+    UNION SELECT hospital_id FROM encounters
+    UNION SELECT hospital_id::uuid FROM users
+    WHERE hospital_id ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
+) referenced
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE reports ADD CONSTRAINT reports_hospital_id_fkey FOREIGN KEY (hospital_id) REFERENCES hospitals(id);
+ALTER TABLE patients ADD CONSTRAINT patients_hospital_id_fkey FOREIGN KEY (hospital_id) REFERENCES hospitals(id);
+ALTER TABLE encounters ADD CONSTRAINT encounters_hospital_id_fkey FOREIGN KEY (hospital_id) REFERENCES hospitals(id);
+
+-- Users registered with a free-text hospital code are left without a hospital
+ALTER TABLE users ALTER COLUMN hospital_id DROP NOT NULL;
+UPDATE users SET hospital_id = NULL
+WHERE hospital_id !~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$';
+ALTER TABLE users ALTER COLUMN hospital_id TYPE UUID USING hospital_id::uuid;
+ALTER TABLE users ADD CONSTRAINT users_hospital_id_fkey FOREIGN KEY (hospital_id) REFERENCES hospitals(id);
+
+-- Stays reference a department once the hospital has defined them; the name
+-- is kept as printed at the time
+ALTER TABLE encounter_stays ADD COLUMN department_id UUID REFERENCES departments(id);
+CREATE INDEX idx_encounter_stays_department ON encounter_stays(department_id);
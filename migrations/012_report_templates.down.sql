@@ -0,0 +1,6 @@
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_template_version_fkey;
+ALTER TABLE reports DROP COLUMN IF EXISTS template_version;
+ALTER TABLE reports DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS report_template_versions;
+DROP TABLE IF EXISTS report_templates;
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Report templates per report type, specialty and department
+-- ============================================================================
+CREATE TABLE report_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hospital_id UUID NOT NULL REFERENCES hospitals(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    report_type VARCHAR(50) NOT NULL,
+    specialty VARCHAR(50),
+    department_id UUID REFERENCES departments(id) ON DELETE CASCADE,
+    current_version INT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_templates_match ON report_templates(hospital_id, report_type) WHERE active;
+
+-- Immutable definition of each template version
+CREATE TABLE report_template_versions (
+    template_id UUID NOT NULL REFERENCES report_templates(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    report_type VARCHAR(50) NOT NULL,
+    specialty VARCHAR(50),
+    department_id UUID,
+    defaults JSONB NOT NULL,
+    required_sections TEXT[] NOT NULL DEFAULT '{}',
+    checklist JSONB NOT NULL DEFAULT '[]',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (template_id, version)
+);
+
+ALTER TABLE reports ADD COLUMN template_id UUID;
+ALTER TABLE reports ADD COLUMN template_version INT;
+ALTER TABLE reports ADD CONSTRAINT reports_template_version_fkey
+    FOREIGN KEY (template_id, template_version) REFERENCES report_template_versions(template_id, version);
@@ -0,0 +1,27 @@
+-- ============================================================================
+-- Receiving department queue for signed transfer summaries
+-- ============================================================================
+CREATE TABLE transfer_handoffs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- the receiving hospital and department
+    hospital_id UUID NOT NULL REFERENCES hospitals(id),
+    department_id UUID NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    report_id UUID NOT NULL UNIQUE REFERENCES reports(id) ON DELETE CASCADE,
+    source_hospital_id UUID NOT NULL REFERENCES hospitals(id),
+    -- the patient as registered by the source hospital
+    patient_id UUID NOT NULL REFERENCES patients(id),
+    patient_cnp VARCHAR(13) NOT NULL,
+    patient_first_name VARCHAR(100) NOT NULL,
+    patient_last_name VARCHAR(100) NOT NULL,
+    source_department VARCHAR(200) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL,
+    current_status TEXT NOT NULL,
+    pending_investigations JSONB NOT NULL DEFAULT '[]',
+    tasks JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    acknowledged_by UUID,
+    acknowledged_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_transfer_handoffs_queue ON transfer_handoffs(hospital_id, department_id, status, created_at DESC);
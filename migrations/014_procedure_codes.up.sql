@@ -0,0 +1,23 @@
+-- ============================================================================
+-- Procedure classification (ACHI, as adopted for DRG coding in Romania)
+-- ============================================================================
+CREATE TABLE procedure_codes (
+    code VARCHAR(10) PRIMARY KEY,
+    block VARCHAR(10),
+    title_ro TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    
+    search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('romanian', title_ro)
+    ) STORED
+);
+
+CREATE INDEX idx_procedure_codes_search ON procedure_codes USING GIN(search_vector);
+
+-- Seed common procedures
+INSERT INTO procedure_codes (code, title_ro) VALUES
+('30443-00', 'Colecistectomie'),
+('30445-00', 'Colecistectomie laparoscopică'),
+('30571-00', 'Apendicectomie'),
+('30572-00', 'Apendicectomie laparoscopică'),
+('13100-00', 'Hemodializă');
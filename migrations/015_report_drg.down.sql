@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reports_drg_code;
+ALTER TABLE reports DROP COLUMN IF EXISTS drg;
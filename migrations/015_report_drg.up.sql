@@ -0,0 +1,6 @@
+-- ============================================================================
+-- DRG assigned to signed discharge summaries
+-- ============================================================================
+ALTER TABLE reports ADD COLUMN drg JSONB;
+
+CREATE INDEX idx_reports_drg_code ON reports ((drg->>'code')) WHERE drg IS NOT NULL;
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Print layouts (html/template source) per hospital and report type
+-- ============================================================================
+CREATE TABLE print_layouts (
+    hospital_id UUID NOT NULL REFERENCES hospitals(id) ON DELETE CASCADE,
+    report_type VARCHAR(50) NOT NULL,
+    source TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_by UUID,
+
+    PRIMARY KEY (hospital_id, report_type)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reports_verification_token;
+ALTER TABLE reports DROP COLUMN IF EXISTS verification_token;
+ALTER TABLE reports DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE reports DROP COLUMN IF EXISTS signed_by;
@@ -0,0 +1,8 @@
+-- ============================================================================
+-- Signer, content hash and verification token of signed reports
+-- ============================================================================
+ALTER TABLE reports ADD COLUMN signed_by UUID;
+ALTER TABLE reports ADD COLUMN content_hash TEXT;
+ALTER TABLE reports ADD COLUMN verification_token TEXT;
+
+CREATE UNIQUE INDEX idx_reports_verification_token ON reports(verification_token) WHERE verification_token IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Platform administrators are marked by their role rather than by a missing
+-- hospital. Users left without a hospital by 011 stay unassigned and have no
+-- admin rights until a hospital is assigned to them.
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('doctor', 'admin', 'platform_admin'));
+ALTER TABLE users ADD CONSTRAINT users_platform_admin_hospital_check
+    CHECK (role <> 'platform_admin' OR hospital_id IS NULL);
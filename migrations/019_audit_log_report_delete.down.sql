@@ -0,0 +1,3 @@
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_report_id_fkey;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_report_id_fkey
+    FOREIGN KEY (report_id) REFERENCES reports(id);
@@ -0,0 +1,9 @@
+-- Deleting a draft keeps its audit trail on the patient: the report
+-- reference is cleared instead of blocking the delete
+UPDATE audit_log a SET patient_id = r.patient_id
+FROM reports r
+WHERE a.report_id = r.id AND a.patient_id IS NULL;
+
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_report_id_fkey;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_report_id_fkey
+    FOREIGN KEY (report_id) REFERENCES reports(id) ON DELETE SET NULL;
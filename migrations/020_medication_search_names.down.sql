@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_medications_search_substance;
+DROP INDEX IF EXISTS idx_medications_search_name;
+ALTER TABLE medications
+    DROP COLUMN IF EXISTS search_substance,
+    DROP COLUMN IF EXISTS search_name;
@@ -0,0 +1,12 @@
+-- Names folded as domain.FoldText does: lower case, without Romanian
+-- diacritics and with single spaces, so that exact lookups ignore diacritics
+ALTER TABLE medications
+    ADD COLUMN search_name TEXT GENERATED ALWAYS AS (
+        regexp_replace(trim(lower(translate(name, 'ĂÂÎȘŞȚŢăâîșşțţ', 'AAISSTTaaisstt'))), '\s+', ' ', 'g')
+    ) STORED,
+    ADD COLUMN search_substance TEXT GENERATED ALWAYS AS (
+        regexp_replace(trim(lower(translate(active_substance, 'ĂÂÎȘŞȚŢăâîșşțţ', 'AAISSTTaaisstt'))), '\s+', ' ', 'g')
+    ) STORED;
+
+CREATE INDEX idx_medications_search_name ON medications(search_name);
+CREATE INDEX idx_medications_search_substance ON medications(search_substance);
@@ -0,0 +1,3 @@
+ALTER TABLE report_templates DROP CONSTRAINT IF EXISTS report_templates_department_id_fkey;
+ALTER TABLE report_templates ADD CONSTRAINT report_templates_department_id_fkey
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+-- Deleting a department no longer deletes its templates: reports keep
+-- referencing the template versions they were created from
+ALTER TABLE report_templates DROP CONSTRAINT report_templates_department_id_fkey;
+ALTER TABLE report_templates ADD CONSTRAINT report_templates_department_id_fkey
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE RESTRICT;
@@ -1,67 +1,320 @@
 package server
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/fhir"
 )
 
 // Request DTOs
 type CreateReportRequest struct {
 	HospitalID        string `json:"hospital_id" binding:"required"`
-	PatientCNP        string `json:"patient_cnp" binding:"required,len=13"`
-	PatientFirstName  string `json:"patient_first_name" binding:"required"`
-	PatientLastName   string `json:"patient_last_name" binding:"required"`
+	// PatientID references the registry; without it the patient is looked
+	// up or registered by CNP and name
+	PatientID         string `json:"patient_id"`
+	PatientCNP        string `json:"patient_cnp" binding:"omitempty,len=13"`
+	PatientFirstName  string `json:"patient_first_name"`
+	PatientLastName   string `json:"patient_last_name"`
+	// EncounterID links the report to an admission; without it the patient's
+	// active encounter is used, or the patient is admitted to Department
+	// (the specialty's department by default)
+	EncounterID       string `json:"encounter_id"`
+	Department        string `json:"department"`
+	Ward              string `json:"ward"`
+	Bed               string `json:"bed"`
 	Specialty         string `json:"specialty" binding:"required"`
 	ReportType        string `json:"report_type" binding:"required"`
 	DoctorID          string `json:"doctor_id" binding:"required"`
 }
 
+// CarryForwardReportRequest creates a report prefilled with Sections of the
+// patient's most recent signed report (all of them when empty)
+type CarryForwardReportRequest struct {
+	CreateReportRequest
+	Sections []string `json:"sections"`
+}
+
+// ImportFHIRReportRequest creates a draft from an admission sent by the
+// hospital information system as a FHIR R4 Bundle
+type ImportFHIRReportRequest struct {
+	HospitalID string          `json:"hospital_id" binding:"required"`
+	Specialty  string          `json:"specialty" binding:"required"`
+	ReportType string          `json:"report_type" binding:"required"`
+	DoctorID   string          `json:"doctor_id" binding:"required"`
+	Bundle     json.RawMessage `json:"bundle" binding:"required"`
+}
+
+type CreatePatientRequest struct {
+	HospitalID string `json:"hospital_id" binding:"required"`
+	CNP        string `json:"cnp" binding:"required,len=13"`
+	FirstName  string `json:"first_name" binding:"required"`
+	LastName   string `json:"last_name" binding:"required"`
+	Phone      string `json:"phone"`
+	Address    string `json:"address"`
+}
+
+// UpdatePatientRequest changes demographics; UserID is the user the
+// resulting report versions are saved by
+type UpdatePatientRequest struct {
+	CNP       *string `json:"cnp" binding:"omitempty,len=13"`
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Phone     *string `json:"phone"`
+	Address   *string `json:"address"`
+	UserID    string  `json:"user_id" binding:"required"`
+}
+
+type MergePatientsRequest struct {
+	HospitalID  string `json:"hospital_id" binding:"required"`
+	SurvivorID  string `json:"survivor_id" binding:"required"`
+	MergedID    string `json:"merged_id" binding:"required"`
+	CandidateID string `json:"candidate_id"`
+}
+
+// CreateEncounterRequest names the department by department_id, or by name
+// or code in department
+type CreateEncounterRequest struct {
+	HospitalID      string     `json:"hospital_id" binding:"required"`
+	PatientID       string     `json:"patient_id" binding:"required"`
+	AdmissionNumber string     `json:"admission_number"`
+	AdmittedAt      *time.Time `json:"admitted_at"`
+	DepartmentID    string     `json:"department_id"`
+	Department      string     `json:"department" binding:"required_without=DepartmentID"`
+	Ward            string     `json:"ward"`
+	Bed             string     `json:"bed"`
+}
+
+type TransferEncounterRequest struct {
+	DepartmentID string     `json:"department_id"`
+	Department   string     `json:"department" binding:"required_without=DepartmentID"`
+	Ward         string     `json:"ward"`
+	Bed          string     `json:"bed"`
+	At           *time.Time `json:"at"`
+}
+
+type DischargeEncounterRequest struct {
+	DischargedAt *time.Time `json:"discharged_at"`
+}
+
+type CreateHospitalRequest struct {
+	Name    string         `json:"name" binding:"required"`
+	CUI     string         `json:"cui"`
+	SIRUTA  string         `json:"siruta"`
+	Address domain.Address `json:"address"`
+	Phone   string         `json:"phone"`
+	Email   string         `json:"email" binding:"omitempty,email"`
+}
+
+type UpdateHospitalRequest struct {
+	Name    *string         `json:"name"`
+	CUI     *string         `json:"cui"`
+	SIRUTA  *string         `json:"siruta"`
+	Address *domain.Address `json:"address"`
+	Phone   *string         `json:"phone"`
+	Email   *string         `json:"email" binding:"omitempty,email"`
+}
+
+type PrintLayoutRequest struct {
+	Source string `json:"source" binding:"required"`
+}
+
+// PreviewPrintLayoutRequest renders the source with the report, or with a
+// sample report when ReportID is empty
+type PreviewPrintLayoutRequest struct {
+	Source   string     `json:"source" binding:"required"`
+	ReportID *uuid.UUID `json:"report_id"`
+}
+
+type CreateDepartmentRequest struct {
+	Code      string `json:"code" binding:"required,max=20"`
+	Name      string `json:"name" binding:"required"`
+	Specialty string `json:"specialty"`
+}
+
+type UpdateDepartmentRequest struct {
+	Code      *string `json:"code" binding:"omitempty,max=20"`
+	Name      *string `json:"name"`
+	Specialty *string `json:"specialty"`
+}
+
+// TemplateRequest is the full definition of a report template; updates
+// replace the definition with a new version
+type TemplateRequest struct {
+	Name             string                 `json:"name" binding:"required"`
+	Specialty        string                 `json:"specialty"`
+	ReportType       string                 `json:"report_type" binding:"required"`
+	DepartmentID     *uuid.UUID             `json:"department_id"`
+	Defaults         domain.ReportContent   `json:"defaults"`
+	RequiredSections []string               `json:"required_sections"`
+	Checklist        []domain.ChecklistItem `json:"checklist"`
+}
+
 type UpdateReportContentRequest struct {
 	Content domain.ReportContent `json:"content" binding:"required"`
 	UserID  string               `json:"user_id" binding:"required"`
 }
 
 type UpdateReportStatusRequest struct {
-	Status string `json:"status" binding:"required"`
+	Status         string `json:"status" binding:"required"`
+	UserID         string `json:"user_id"`
+	OverrideReason string `json:"override_reason"`
 }
 
 // Response DTOs
 type ReportResponse struct {
-	ID               string                `json:"id"`
-	HospitalID       string                `json:"hospital_id"`
-	PatientCNP       string                `json:"patient_cnp"`
-	PatientFirstName string                `json:"patient_first_name"`
-	PatientLastName  string                `json:"patient_last_name"`
-	Specialty        string                `json:"specialty"`
-	ReportType       string                `json:"report_type"`
-	Status           string                `json:"status"`
-	Content          domain.ReportContent  `json:"content"`
-	CreatedBy        string                `json:"created_by"`
-	CreatedAt        time.Time             `json:"created_at"`
-	LastModified     time.Time             `json:"last_modified"`
-	FinalizedAt      *time.Time            `json:"finalized_at,omitempty"`
+	ID                  string                     `json:"id"`
+	HospitalID          string                     `json:"hospital_id"`
+	PatientID           string                     `json:"patient_id"`
+	EncounterID         string                     `json:"encounter_id"`
+	PatientCNP          string                     `json:"patient_cnp"`
+	PatientFirstName    string                     `json:"patient_first_name"`
+	PatientLastName     string                     `json:"patient_last_name"`
+	Specialty           string                     `json:"specialty"`
+	ReportType          string                     `json:"report_type"`
+	Status              string                     `json:"status"`
+	Content             domain.ReportContent       `json:"content"`
+	CreatedBy           string                     `json:"created_by"`
+	CreatedAt           time.Time                  `json:"created_at"`
+	LastModified        time.Time                  `json:"last_modified"`
+	FinalizedAt         *time.Time                 `json:"finalized_at,omitempty"`
+	TemplateID          *uuid.UUID                 `json:"template_id,omitempty"`
+	TemplateVersion     int                        `json:"template_version,omitempty"`
+	DRG                 *domain.DRGAssignment      `json:"drg,omitempty"`
+	Signature           *domain.Signature          `json:"signature,omitempty"`
+	Warnings            []domain.ValidationWarning `json:"warnings,omitempty"`
+	ReconciliationTable []domain.ReconciliationRow `json:"reconciliation_table,omitempty"`
+	Unmapped            []fhir.Unmapped            `json:"unmapped,omitempty"`
 }
 
 func ToReportResponse(report *domain.Report) ReportResponse {
 	return ReportResponse{
-		ID:               report.ID.String(),
-		HospitalID:       report.HospitalID.String(),
-		PatientCNP:       report.PatientCNP,
-		PatientFirstName: report.PatientFirstName,
-		PatientLastName:  report.PatientLastName,
-		Specialty:        string(report.Specialty),
-		ReportType:       string(report.ReportType),
-		Status:           string(report.Status),
-		Content:          report.Content,
-		CreatedBy:        report.CreatedBy.String(),
-		CreatedAt:        report.CreatedAt,
-		LastModified:     report.LastModified,
-		FinalizedAt:      report.FinalizedAt,
+		ID:                  report.ID.String(),
+		HospitalID:          report.HospitalID.String(),
+		PatientID:           report.PatientID.String(),
+		EncounterID:         report.EncounterID.String(),
+		PatientCNP:          report.PatientCNP,
+		PatientFirstName:    report.PatientFirstName,
+		PatientLastName:     report.PatientLastName,
+		Specialty:           string(report.Specialty),
+		ReportType:          string(report.ReportType),
+		Status:              string(report.Status),
+		Content:             report.Content,
+		CreatedBy:           report.CreatedBy.String(),
+		CreatedAt:           report.CreatedAt,
+		LastModified:        report.LastModified,
+		FinalizedAt:         report.FinalizedAt,
+		TemplateID:          report.TemplateID,
+		TemplateVersion:     report.TemplateVersion,
+		DRG:                 report.DRG,
+		Signature:           report.Signature,
+		ReconciliationTable: report.Content.ReconciliationTable(),
+	}
+}
+
+type PatientResponse struct {
+	ID           string     `json:"id"`
+	HospitalID   string     `json:"hospital_id"`
+	CNP          string     `json:"cnp"`
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	BirthDate    *time.Time `json:"birth_date,omitempty"`
+	Sex          string     `json:"sex,omitempty"`
+	Phone        string     `json:"phone,omitempty"`
+	Address      string     `json:"address,omitempty"`
+	MergedIntoID *uuid.UUID `json:"merged_into_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func ToPatientResponse(patient *domain.Patient) PatientResponse {
+	return PatientResponse{
+		ID:           patient.ID.String(),
+		HospitalID:   patient.HospitalID.String(),
+		CNP:          patient.CNP,
+		FirstName:    patient.FirstName,
+		LastName:     patient.LastName,
+		BirthDate:    patient.BirthDate,
+		Sex:          string(patient.Sex),
+		Phone:        patient.Phone,
+		Address:      patient.Address,
+		MergedIntoID: patient.MergedIntoID,
+		CreatedAt:    patient.CreatedAt,
+		UpdatedAt:    patient.UpdatedAt,
 	}
 }
 
+type DuplicateCandidateResponse struct {
+	domain.DuplicateCandidate
+	PatientA PatientResponse `json:"patient_a"`
+	PatientB PatientResponse `json:"patient_b"`
+}
+
+type DuplicateListResponse struct {
+	Candidates []DuplicateCandidateResponse `json:"candidates"`
+	Total      int                          `json:"total"`
+	Limit      int                          `json:"limit"`
+	Offset     int                          `json:"offset"`
+}
+
+type MergeListResponse struct {
+	Merges []*domain.PatientMerge `json:"merges"`
+	Total  int                    `json:"total"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+type HandoffListResponse struct {
+	Handoffs []*domain.Handoff `json:"handoffs"`
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+type PatientListResponse struct {
+	Patients []PatientResponse `json:"patients"`
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+type EncounterResponse struct {
+	ID              string                 `json:"id"`
+	HospitalID      string                 `json:"hospital_id"`
+	PatientID       string                 `json:"patient_id"`
+	AdmissionNumber string                 `json:"admission_number,omitempty"`
+	Status          string                 `json:"status"`
+	AdmittedAt      time.Time              `json:"admitted_at"`
+	DischargedAt    *time.Time             `json:"discharged_at,omitempty"`
+	Location        domain.BedLocation     `json:"location"`
+	Stays           []domain.EncounterStay `json:"stays"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+func ToEncounterResponse(encounter *domain.Encounter) EncounterResponse {
+	return EncounterResponse{
+		ID:              encounter.ID.String(),
+		HospitalID:      encounter.HospitalID.String(),
+		PatientID:       encounter.PatientID.String(),
+		AdmissionNumber: encounter.AdmissionNumber,
+		Status:          string(encounter.Status),
+		AdmittedAt:      encounter.AdmittedAt,
+		DischargedAt:    encounter.DischargedAt,
+		Location:        encounter.Location(),
+		Stays:           encounter.Stays,
+		CreatedAt:       encounter.CreatedAt,
+		UpdatedAt:       encounter.UpdatedAt,
+	}
+}
+
+type PatientTimelineResponse struct {
+	Patient PatientResponse        `json:"patient"`
+	Events  []domain.TimelineEvent `json:"events"`
+}
+
 type ReportListResponse struct {
 	Reports []ReportResponse `json:"reports"`
 	Total   int              `json:"total"`
@@ -95,6 +348,11 @@ type ICD10Response struct {
 	Code         string `json:"code"`
 	Description  string `json:"description"`
 	Category     string `json:"category"`
+	Level        string `json:"level"`
+	ParentCode   string `json:"parent_code,omitempty"`
+	BlockCode    string `json:"block_code,omitempty"`
+	ChapterCode  string `json:"chapter_code,omitempty"`
+	Billable     bool   `json:"billable"`
 }
 
 func ToICD10Response(ref domain.ICD10Reference) ICD10Response {
@@ -102,29 +360,85 @@ func ToICD10Response(ref domain.ICD10Reference) ICD10Response {
 		Code:        ref.Code,
 		Description: ref.DescriptionRO,
 		Category:    ref.Category,
+		Level:       string(ref.Level),
+		ParentCode:  ref.ParentCode,
+		BlockCode:   ref.BlockCode,
+		ChapterCode: ref.ChapterCode,
+		Billable:    ref.Billable,
 	}
 }
 
 type MedicationResponse struct {
-	ID              string `json:"id"`
-	Name            string `json:"name"`
-	ActiveSubstance string `json:"active_substance"`
-	Form            string `json:"form"`
-	Dosage          string `json:"dosage"`
-	Manufacturer    string `json:"manufacturer"`
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	ActiveSubstance    string   `json:"active_substance"`
+	Form               string   `json:"form"`
+	Dosage             string   `json:"dosage"`
+	Manufacturer       string   `json:"manufacturer"`
+	AuthorizationCode  string   `json:"authorization_code,omitempty"`
+	ATCCode            string   `json:"atc_code,omitempty"`
+	Package            string   `json:"package,omitempty"`
+	PrescriptionStatus string   `json:"prescription_status,omitempty"`
+	MaxDailyDoseMg     *float64 `json:"max_daily_dose_mg,omitempty"`
 }
 
 func ToMedicationResponse(ref domain.MedicationReference) MedicationResponse {
 	return MedicationResponse{
-		ID:              ref.ID.String(),
-		Name:            ref.Name,
-		ActiveSubstance: ref.ActiveSubstance,
-		Form:            ref.Form,
-		Dosage:          ref.Dosage,
-		Manufacturer:    ref.Manufacturer,
+		ID:                 ref.ID.String(),
+		Name:               ref.Name,
+		ActiveSubstance:    ref.ActiveSubstance,
+		Form:               ref.Form,
+		Dosage:             ref.Dosage,
+		Manufacturer:       ref.Manufacturer,
+		AuthorizationCode:  ref.AuthorizationCode,
+		ATCCode:            ref.ATCCode,
+		Package:            ref.Package,
+		PrescriptionStatus: ref.PrescriptionStatus,
+		MaxDailyDoseMg:     ref.MaxDailyDoseMg,
 	}
 }
 
+type LabTestResponse struct {
+	LOINCCode       string                     `json:"loinc_code"`
+	Name            string                     `json:"name"`
+	DefaultUnit     string                     `json:"default_unit"`
+	ReferenceRanges []domain.LabReferenceRange `json:"reference_ranges"`
+}
+
+func ToLabTestResponse(ref domain.LabTestReference) LabTestResponse {
+	ranges := ref.ReferenceRanges
+	if ranges == nil {
+		ranges = []domain.LabReferenceRange{}
+	}
+	return LabTestResponse{
+		LOINCCode:       ref.LOINCCode,
+		Name:            ref.NameRO,
+		DefaultUnit:     ref.DefaultUnit,
+		ReferenceRanges: ranges,
+	}
+}
+
+type ProcedureResponse struct {
+	Code   string `json:"code"`
+	Block  string `json:"block,omitempty"`
+	Title  string `json:"title"`
+	Active bool   `json:"active"`
+}
+
+func ToProcedureResponse(ref domain.ProcedureReference) ProcedureResponse {
+	return ProcedureResponse{
+		Code:   ref.Code,
+		Block:  ref.Block,
+		Title:  ref.TitleRO,
+		Active: ref.Active,
+	}
+}
+
+type SigResponse struct {
+	Dose domain.Dose `json:"dose"`
+	Text string      `json:"text"`
+}
+
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
@@ -134,3 +448,15 @@ type ErrorResponse struct {
 func ParseUUID(s string) (uuid.UUID, error) {
 	return uuid.Parse(s)
 }
+
+// ParseOptionalDate parses a YYYY-MM-DD query value; empty yields nil
+func ParseOptionalDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -1,12 +1,22 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/fhir"
+	"github.com/tudormiron/medical-reports/internal/render"
 	"github.com/tudormiron/medical-reports/internal/services"
 )
 
@@ -14,13 +24,23 @@ type Handlers struct {
 	reportService    *services.ReportService
 	referenceService *services.ReferenceService
 	authService      *services.AuthService
+	patientService   *services.PatientService
+	mergeService     *services.PatientMergeService
+	encounterService *services.EncounterService
+	hospitalService  *services.HospitalService
+	documentService  *services.DocumentService
 }
 
-func NewHandlers(reportService *services.ReportService, referenceService *services.ReferenceService, authService *services.AuthService) *Handlers {
+func NewHandlers(reportService *services.ReportService, referenceService *services.ReferenceService, authService *services.AuthService, patientService *services.PatientService, mergeService *services.PatientMergeService, encounterService *services.EncounterService, hospitalService *services.HospitalService, documentService *services.DocumentService) *Handlers {
 	return &Handlers{
 		reportService:    reportService,
 		referenceService: referenceService,
 		authService:      authService,
+		patientService:   patientService,
+		mergeService:     mergeService,
+		encounterService: encounterService,
+		hospitalService:  hospitalService,
+		documentService:  documentService,
 	}
 }
 
@@ -43,6 +63,171 @@ func (h *Handlers) CreateReport(c *gin.Context) {
 		return
 	}
 
+	h.createReport(c, req, false, nil)
+}
+
+// CreateReportFromPrevious creates a report prefilled from the patient's most
+// recent signed report
+func (h *Handlers) CreateReportFromPrevious(c *gin.Context) {
+	var req CarryForwardReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	sections := make([]domain.CarryForwardSection, len(req.Sections))
+	for i, s := range req.Sections {
+		sections[i] = domain.CarryForwardSection(s)
+		if !sections[i].IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_section",
+				Message: "Sections must be past_medical_history, allergies or chronic_medications",
+			})
+			return
+		}
+	}
+
+	h.createReport(c, req.CreateReportRequest, true, sections)
+}
+
+// createReport resolves the patient and encounter of a new report and creates
+// it, carrying forward content from the previous report when asked
+func (h *Handlers) createReport(c *gin.Context, req CreateReportRequest, carryForward bool, sections []domain.CarryForwardSection) {
+	hospitalID, err := ParseUUID(req.HospitalID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	doctorID, err := ParseUUID(req.DoctorID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_doctor_id",
+			Message: "Invalid doctor ID format",
+		})
+		return
+	}
+
+	// Nothing is registered or admitted for a report that cannot be created
+	specialty, reportType := domain.Specialty(req.Specialty), domain.ReportType(req.ReportType)
+	if err := h.reportService.CheckNewReport(specialty, reportType, sections); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	var encounterID uuid.UUID
+	if req.EncounterID != "" {
+		encounterID, err = ParseUUID(req.EncounterID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_encounter_id",
+				Message: "Invalid encounter ID format",
+			})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	var patient *domain.Patient
+	var registered bool
+	var patientID uuid.UUID
+	if req.PatientID != "" {
+		patientID, err = ParseUUID(req.PatientID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_patient_id",
+				Message: "Invalid patient ID format",
+			})
+			return
+		}
+	} else {
+		if req.PatientCNP == "" || req.PatientFirstName == "" || req.PatientLastName == "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: "patient_id or patient_cnp, patient_first_name and patient_last_name are required",
+			})
+			return
+		}
+
+		patient, registered, err = h.patientService.FindOrRegisterPatient(ctx, hospitalID, req.PatientCNP, req.PatientFirstName, req.PatientLastName)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		patientID = patient.ID
+	}
+
+	var admitted *domain.Encounter
+	if req.EncounterID == "" {
+		location := domain.BedLocation{Department: req.Department, Ward: req.Ward, Bed: req.Bed}
+
+		encounter, isNew, err := h.encounterService.FindOrAdmitPatient(ctx, hospitalID, patientID, specialty, location)
+		if err != nil {
+			h.cancelAdmission(ctx, hospitalID, patient, registered, nil)
+			h.handleError(c, err)
+			return
+		}
+		if isNew {
+			admitted = encounter
+		}
+		encounterID = encounter.ID
+		patientID = encounter.PatientID
+	}
+
+	var report *domain.Report
+	if carryForward {
+		report, err = h.reportService.CreateReportFromPrevious(
+			ctx,
+			hospitalID,
+			patientID,
+			encounterID,
+			specialty,
+			reportType,
+			doctorID,
+			sections,
+		)
+	} else {
+		report, err = h.reportService.CreateReport(
+			ctx,
+			hospitalID,
+			patientID,
+			encounterID,
+			specialty,
+			reportType,
+			doctorID,
+		)
+	}
+
+	if err != nil {
+		h.cancelAdmission(ctx, hospitalID, patient, registered, admitted)
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ToReportResponse(report))
+}
+
+// ImportFHIRReport creates a draft from a FHIR bundle of the hospital
+// information system. The patient is looked up or registered by CNP and
+// admitted unless an admission is active, both undone when the draft cannot
+// be created; the response lists the elements of the bundle the draft does
+// not hold.
+func (h *Handlers) ImportFHIRReport(c *gin.Context) {
+	var req ImportFHIRReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	hospitalID, err := ParseUUID(req.HospitalID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -61,23 +246,82 @@ func (h *Handlers) CreateReport(c *gin.Context) {
 		return
 	}
 
-	report, err := h.reportService.CreateReport(
-		c.Request.Context(),
+	admission, err := fhir.ReadAdmission(req.Bundle, domain.ReportType(req.ReportType))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Nothing is registered or admitted for a draft that cannot be created
+	specialty := domain.Specialty(req.Specialty)
+	if err := h.reportService.CheckNewReport(specialty, admission.ReportType, nil); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	patient, registered, err := h.patientService.FindOrRegisterPatient(ctx, hospitalID, admission.CNP, admission.FirstName, admission.LastName)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if !registered {
+		admission.KeepRegisteredName(patient)
+	}
+
+	encounter, admitted, err := h.encounterService.FindOrAdmitPatientAt(
+		ctx,
 		hospitalID,
-		req.PatientCNP,
-		req.PatientFirstName,
-		req.PatientLastName,
-		domain.Specialty(req.Specialty),
-		domain.ReportType(req.ReportType),
-		doctorID,
+		patient.ID,
+		specialty,
+		admission.AdmissionNumber,
+		admission.AdmittedAt,
+		admission.Location,
 	)
+	if err != nil {
+		h.cancelAdmission(ctx, hospitalID, patient, registered, nil)
+		h.handleError(c, err)
+		return
+	}
 
+	report, err := h.reportService.CreateReportFromAdmission(
+		ctx,
+		hospitalID,
+		encounter.PatientID,
+		encounter.ID,
+		specialty,
+		doctorID,
+		admission,
+	)
 	if err != nil {
+		if !admitted {
+			encounter = nil
+		}
+		h.cancelAdmission(ctx, hospitalID, patient, registered, encounter)
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, ToReportResponse(report))
+	response := ToReportResponse(report)
+	response.Unmapped = admission.Unmapped
+	c.JSON(http.StatusCreated, response)
+}
+
+// cancelAdmission undoes the admission of the given encounter, when not nil,
+// and the registration of a patient registered for a report that could not
+// be created
+func (h *Handlers) cancelAdmission(ctx context.Context, hospitalID uuid.UUID, patient *domain.Patient, registered bool, encounter *domain.Encounter) {
+	if encounter != nil {
+		if err := h.encounterService.CancelAdmission(ctx, hospitalID, encounter.ID); err != nil {
+			log.Printf("Cancelling admission %s failed: %v", encounter.ID, err)
+			return
+		}
+	}
+	if registered {
+		if err := h.patientService.CancelRegistration(ctx, hospitalID, patient.ID); err != nil {
+			log.Printf("Cancelling registration of patient %s failed: %v", patient.ID, err)
+		}
+	}
 }
 
 // GetReport retrieves a report by ID
@@ -100,6 +344,100 @@ func (h *Handlers) GetReport(c *gin.Context) {
 	c.JSON(http.StatusOK, ToReportResponse(report))
 }
 
+// GetReportPDF renders a report as a printable PDF letter
+func (h *Handlers) GetReportPDF(c *gin.Context) {
+	reportID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_report_id",
+			Message: "Invalid report ID format",
+		})
+		return
+	}
+
+	doc, err := h.documentService.GetDocument(c.Request.Context(), reportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := render.PDF(&buf, doc); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-%s.pdf"`, doc.Report.ReportType, reportID.String()[:8]))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// GetReportHTML renders a report as an HTML page with its hospital's print
+// layout
+func (h *Handlers) GetReportHTML(c *gin.Context) {
+	reportID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_report_id",
+			Message: "Invalid report ID format",
+		})
+		return
+	}
+
+	page, err := h.documentService.RenderHTML(c.Request.Context(), reportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	writeHTML(c, page)
+}
+
+// GetReportFHIR exports a report as a FHIR R4 document bundle
+func (h *Handlers) GetReportFHIR(c *gin.Context) {
+	reportID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_report_id",
+			Message: "Invalid report ID format",
+		})
+		return
+	}
+
+	bundle, err := h.documentService.ExportFHIR(c.Request.Context(), reportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	body, err := json.Marshal(bundle)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/fhir+json; charset=utf-8", body)
+}
+
+// writeHTML sends a rendered print page. Layouts are edited by hospital
+// administrators, so the page may not run scripts or load anything beyond
+// inline styles and the embedded logo.
+func writeHTML(c *gin.Context, page []byte) {
+	c.Header("Content-Security-Policy", "default-src 'none'; img-src data:; style-src 'unsafe-inline'")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+// VerifyReport tells whoever scanned a printed report's QR code which report
+// was signed, when and by whom, without revealing clinical data
+func (h *Handlers) VerifyReport(c *gin.Context) {
+	verification, err := h.documentService.Verify(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
 // ListReports lists reports with filtering
 func (h *Handlers) ListReports(c *gin.Context) {
 	doctorID, err := ParseUUID(c.Query("doctor_id"))
@@ -134,6 +472,44 @@ func (h *Handlers) ListReports(c *gin.Context) {
 	})
 }
 
+// GetDiagnosisBlockStatistics rolls up signed reports by ICD-10 block
+func (h *Handlers) GetDiagnosisBlockStatistics(c *gin.Context) {
+	hospitalID, err := ParseUUID(c.Query("hospital_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	from, err := ParseOptionalDate(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_date",
+			Message: "Invalid 'from' date, expected YYYY-MM-DD",
+		})
+		return
+	}
+
+	to, err := ParseOptionalDate(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_date",
+			Message: "Invalid 'to' date, expected YYYY-MM-DD",
+		})
+		return
+	}
+
+	stats, err := h.reportService.DiagnosisStatisticsByBlock(c.Request.Context(), hospitalID, from, to)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // UpdateReportContent updates report content
 func (h *Handlers) UpdateReportContent(c *gin.Context) {
 	reportID, err := ParseUUID(c.Param("id"))
@@ -163,7 +539,8 @@ func (h *Handlers) UpdateReportContent(c *gin.Context) {
 		return
 	}
 
-	if err := h.reportService.UpdateReportContent(c.Request.Context(), reportID, req.Content, userID); err != nil {
+	warnings, err := h.reportService.UpdateReportContent(c.Request.Context(), reportID, req.Content, userID)
+	if err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -174,7 +551,9 @@ func (h *Handlers) UpdateReportContent(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, ToReportResponse(report))
+	resp := ToReportResponse(report)
+	resp.Warnings = warnings
+	c.JSON(http.StatusOK, resp)
 }
 
 // UpdateReportStatus updates report status
@@ -197,7 +576,20 @@ func (h *Handlers) UpdateReportStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.reportService.UpdateReportStatus(c.Request.Context(), reportID, domain.Status(req.Status)); err != nil {
+	var userID uuid.UUID
+	if req.UserID != "" {
+		userID, err = ParseUUID(req.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_user_id",
+				Message: "Invalid user ID format",
+			})
+			return
+		}
+	}
+
+	warnings, err := h.reportService.UpdateReportStatus(c.Request.Context(), reportID, domain.Status(req.Status), userID, req.OverrideReason)
+	if err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -208,7 +600,9 @@ func (h *Handlers) UpdateReportStatus(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, ToReportResponse(report))
+	resp := ToReportResponse(report)
+	resp.Warnings = warnings
+	c.JSON(http.StatusOK, resp)
 }
 
 // DeleteReport deletes a report
@@ -266,7 +660,7 @@ func (h *Handlers) SearchICD10(c *gin.Context) {
 		return
 	}
 
-	results, err := h.referenceService.SearchICD10(c.Request.Context(), query)
+	results, err := h.referenceService.SearchICD10(c.Request.Context(), query, c.Query("chapter"))
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -280,18 +674,55 @@ func (h *Handlers) SearchICD10(c *gin.Context) {
 	c.JSON(http.StatusOK, responses)
 }
 
+// ListICD10Chapters returns the ICD-10 chapters
+func (h *Handlers) ListICD10Chapters(c *gin.Context) {
+	chapters, err := h.referenceService.ListICD10Chapters(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, chapters)
+}
+
+// GetICD10Children returns the direct children of an ICD-10 chapter, block or code
+func (h *Handlers) GetICD10Children(c *gin.Context) {
+	children, err := h.referenceService.GetICD10Children(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, children)
+}
+
+// GetICD10Ancestors returns the path from the chapter down to an ICD-10 code
+func (h *Handlers) GetICD10Ancestors(c *gin.Context) {
+	ancestors, err := h.referenceService.GetICD10Ancestors(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ancestors)
+}
+
 // SearchMedications searches for medications
 func (h *Handlers) SearchMedications(c *gin.Context) {
-	query := c.Query("q")
-	if query == "" {
+	search := domain.MedicationSearch{
+		Query:           c.Query("q"),
+		ATCCode:         c.Query("atc"),
+		ActiveSubstance: c.Query("substance"),
+	}
+	if search.IsEmpty() {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "missing_query",
-			Message: "Query parameter 'q' is required",
+			Message: "One of the query parameters 'q', 'atc' or 'substance' is required",
 		})
 		return
 	}
 
-	results, err := h.referenceService.SearchMedications(c.Request.Context(), query)
+	results, err := h.referenceService.SearchMedications(c.Request.Context(), search)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -305,34 +736,1648 @@ func (h *Handlers) SearchMedications(c *gin.Context) {
 	c.JSON(http.StatusOK, responses)
 }
 
-// handleError handles domain errors and converts them to HTTP responses
-func (h *Handlers) handleError(c *gin.Context, err error) {
-	switch {
-	case errors.Is(err, domain.ErrReportNotFound):
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error:   "report_not_found",
-			Message: "Report not found",
-		})
-	case errors.Is(err, domain.ErrCannotEditNonDraft):
-		c.JSON(http.StatusForbidden, ErrorResponse{
-			Error:   "cannot_edit_non_draft",
-			Message: "Can only edit reports in draft status",
-		})
-	case errors.Is(err, domain.ErrInvalidStatusTransition):
+// SearchLabTests searches the LOINC lab test catalog
+func (h *Handlers) SearchLabTests(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_status_transition",
-			Message: "Invalid status transition",
-		})
+			Error:   "missing_query",
+			Message: "Query parameter 'q' is required",
+		})
+		return
+	}
+
+	results, err := h.referenceService.SearchLabTests(c.Request.Context(), query)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]LabTestResponse, len(results))
+	for i, ref := range results {
+		responses[i] = ToLabTestResponse(ref)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetLabTest retrieves a lab test by LOINC code
+func (h *Handlers) GetLabTest(c *gin.Context) {
+	ref, err := h.referenceService.GetLabTestByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToLabTestResponse(*ref))
+}
+
+// SearchProcedures searches the ACHI procedure classification
+func (h *Handlers) SearchProcedures(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "missing_query",
+			Message: "Query parameter 'q' is required",
+		})
+		return
+	}
+
+	results, err := h.referenceService.SearchProcedures(c.Request.Context(), query)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]ProcedureResponse, len(results))
+	for i, ref := range results {
+		responses[i] = ToProcedureResponse(ref)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetProcedure retrieves a procedure by ACHI code
+func (h *Handlers) GetProcedure(c *gin.Context) {
+	ref, err := h.referenceService.GetProcedureByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToProcedureResponse(*ref))
+}
+
+// ParseSig converts free-text dosage instructions into a structured dose
+func (h *Handlers) ParseSig(c *gin.Context) {
+	dose, err := domain.ParseSig(c.Query("text"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SigResponse{Dose: *dose, Text: dose.String()})
+}
+
+// CreatePatient registers a patient with a hospital
+func (h *Handlers) CreatePatient(c *gin.Context) {
+	var req CreatePatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	hospitalID, err := ParseUUID(req.HospitalID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	patient, err := h.patientService.RegisterPatient(c.Request.Context(), hospitalID, req.CNP, req.FirstName, req.LastName, req.Phone, req.Address)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ToPatientResponse(patient))
+}
+
+// SearchPatients finds a hospital's patients by CNP (?cnp=) or name (?q=)
+func (h *Handlers) SearchPatients(c *gin.Context) {
+	hospitalID, err := ParseUUID(c.Query("hospital_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	search := domain.PatientSearch{
+		CNP:  c.Query("cnp"),
+		Name: c.Query("q"),
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	patients, err := h.patientService.SearchPatients(c.Request.Context(), hospitalID, search, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]PatientResponse, len(patients))
+	for i, patient := range patients {
+		responses[i] = ToPatientResponse(patient)
+	}
+
+	c.JSON(http.StatusOK, PatientListResponse{
+		Patients: responses,
+		Total:    len(responses),
+		Limit:    limit,
+		Offset:   offset,
+	})
+}
+
+// GetPatient retrieves a patient of the hospital given by ?hospital_id=
+func (h *Handlers) GetPatient(c *gin.Context) {
+	hospitalID, patientID, ok := h.patientParams(c)
+	if !ok {
+		return
+	}
+
+	patient, err := h.patientService.GetPatient(c.Request.Context(), hospitalID, patientID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToPatientResponse(patient))
+}
+
+// UpdatePatient changes a patient's demographics
+func (h *Handlers) UpdatePatient(c *gin.Context) {
+	hospitalID, patientID, ok := h.patientParams(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID, err := ParseUUID(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	update := domain.PatientUpdate{
+		CNP:       req.CNP,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Phone:     req.Phone,
+		Address:   req.Address,
+	}
+
+	patient, err := h.patientService.UpdatePatient(c.Request.Context(), hospitalID, patientID, update, userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToPatientResponse(patient))
+}
+
+// ListPatientReports lists every report of a patient
+func (h *Handlers) ListPatientReports(c *gin.Context) {
+	hospitalID, patientID, ok := h.patientParams(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.patientService.ListPatientReports(c.Request.Context(), hospitalID, patientID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	reportResponses := make([]ReportResponse, len(reports))
+	for i, report := range reports {
+		reportResponses[i] = ToReportResponse(report)
+	}
+
+	c.JSON(http.StatusOK, ReportListResponse{
+		Reports: reportResponses,
+		Total:   len(reportResponses),
+		Limit:   len(reportResponses),
+		Offset:  0,
+	})
+}
+
+// GetPatientTimeline returns the chronological history of a patient,
+// identified by ?cnp= within ?hospital_id=, built from their signed reports
+func (h *Handlers) GetPatientTimeline(c *gin.Context) {
+	hospitalID, err := ParseUUID(c.Query("hospital_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	patient, events, err := h.patientService.PatientTimeline(c.Request.Context(), hospitalID, c.Query("cnp"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PatientTimelineResponse{
+		Patient: ToPatientResponse(patient),
+		Events:  events,
+	})
+}
+
+// patientParams parses ?hospital_id= and the :id path parameter, writing the
+// error response when either is malformed
+func (h *Handlers) patientParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	hospitalID, err := ParseUUID(c.Query("hospital_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	patientID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_patient_id",
+			Message: "Invalid patient ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return hospitalID, patientID, true
+}
+
+// AdmitPatient opens an encounter for a registered patient
+func (h *Handlers) AdmitPatient(c *gin.Context) {
+	var req CreateEncounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	hospitalID, err := ParseUUID(req.HospitalID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	patientID, err := ParseUUID(req.PatientID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_patient_id",
+			Message: "Invalid patient ID format",
+		})
+		return
+	}
+
+	var admittedAt time.Time
+	if req.AdmittedAt != nil {
+		admittedAt = *req.AdmittedAt
+	}
+	location, ok := h.bedLocation(c, req.DepartmentID, req.Department, req.Ward, req.Bed)
+	if !ok {
+		return
+	}
+
+	encounter, err := h.encounterService.AdmitPatient(c.Request.Context(), hospitalID, patientID, req.AdmissionNumber, admittedAt, location)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ToEncounterResponse(encounter))
+}
+
+// GetEncounter retrieves an encounter of the hospital given by ?hospital_id=
+func (h *Handlers) GetEncounter(c *gin.Context) {
+	hospitalID, encounterID, ok := h.encounterParams(c)
+	if !ok {
+		return
+	}
+
+	encounter, err := h.encounterService.GetEncounter(c.Request.Context(), hospitalID, encounterID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToEncounterResponse(encounter))
+}
+
+// TransferPatient moves the patient of an encounter to another department,
+// ward or bed
+func (h *Handlers) TransferPatient(c *gin.Context) {
+	hospitalID, encounterID, ok := h.encounterParams(c)
+	if !ok {
+		return
+	}
+
+	var req TransferEncounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var at time.Time
+	if req.At != nil {
+		at = *req.At
+	}
+	location, ok := h.bedLocation(c, req.DepartmentID, req.Department, req.Ward, req.Bed)
+	if !ok {
+		return
+	}
+
+	encounter, err := h.encounterService.TransferPatient(c.Request.Context(), hospitalID, encounterID, location, at)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToEncounterResponse(encounter))
+}
+
+// DischargePatient closes an encounter without a signed discharge summary
+func (h *Handlers) DischargePatient(c *gin.Context) {
+	hospitalID, encounterID, ok := h.encounterParams(c)
+	if !ok {
+		return
+	}
+
+	// Every field is optional, so the body may be left out
+	var req DischargeEncounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var at time.Time
+	if req.DischargedAt != nil {
+		at = *req.DischargedAt
+	}
+
+	encounter, err := h.encounterService.DischargePatient(c.Request.Context(), hospitalID, encounterID, at)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToEncounterResponse(encounter))
+}
+
+// ListEncounterReports returns the reports written during an encounter
+func (h *Handlers) ListEncounterReports(c *gin.Context) {
+	hospitalID, encounterID, ok := h.encounterParams(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.encounterService.ListEncounterReports(c.Request.Context(), hospitalID, encounterID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]ReportResponse, len(reports))
+	for i, report := range reports {
+		responses[i] = ToReportResponse(report)
+	}
+
+	c.JSON(http.StatusOK, ReportListResponse{
+		Reports: responses,
+		Total:   len(responses),
+		Limit:   len(responses),
+		Offset:  0,
+	})
+}
+
+// ListPatientEncounters returns a patient's encounters, most recent first
+func (h *Handlers) ListPatientEncounters(c *gin.Context) {
+	hospitalID, patientID, ok := h.patientParams(c)
+	if !ok {
+		return
+	}
+
+	encounters, err := h.encounterService.ListPatientEncounters(c.Request.Context(), hospitalID, patientID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]EncounterResponse, len(encounters))
+	for i, encounter := range encounters {
+		responses[i] = ToEncounterResponse(encounter)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// bedLocation builds the location of an admission or transfer request
+func (h *Handlers) bedLocation(c *gin.Context, departmentID, department, ward, bed string) (domain.BedLocation, bool) {
+	location := domain.BedLocation{Department: department, Ward: ward, Bed: bed}
+	if departmentID != "" {
+		id, err := ParseUUID(departmentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_department_id",
+				Message: "Invalid department ID format",
+			})
+			return location, false
+		}
+		location.DepartmentID = &id
+	}
+	return location, true
+}
+
+// encounterParams parses ?hospital_id= and the encounter ID path parameter
+func (h *Handlers) encounterParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	hospitalID, err := ParseUUID(c.Query("hospital_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	encounterID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_encounter_id",
+			Message: "Invalid encounter ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return hospitalID, encounterID, true
+}
+
+// ListHospitals returns every hospital, by name
+func (h *Handlers) ListHospitals(c *gin.Context) {
+	hospitals, err := h.hospitalService.ListHospitals(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hospitals)
+}
+
+// GetHospital retrieves a hospital's master data
+func (h *Handlers) GetHospital(c *gin.Context) {
+	hospitalID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	hospital, err := h.hospitalService.GetHospital(c.Request.Context(), hospitalID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hospital)
+}
+
+// GetHospitalLogo serves the letterhead logo image
+func (h *Handlers) GetHospitalLogo(c *gin.Context) {
+	hospitalID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	logo, contentType, err := h.hospitalService.GetLogo(c.Request.Context(), hospitalID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, logo)
+}
+
+// ListDepartments returns a hospital's departments
+func (h *Handlers) ListDepartments(c *gin.Context) {
+	hospitalID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return
+	}
+
+	departments, err := h.hospitalService.ListDepartments(c.Request.Context(), hospitalID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, departments)
+}
+
+// CreateHospital registers a hospital. Only platform administrators may
+// create hospitals.
+func (h *Handlers) CreateHospital(c *gin.Context) {
+	if currentClaims(c).Role != domain.RolePlatformAdmin {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "forbidden",
+			Message: "Only platform administrators can create hospitals",
+		})
+		return
+	}
+
+	var req CreateHospitalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	hospital, err := h.hospitalService.CreateHospital(c.Request.Context(), domain.HospitalInput{
+		Name:    &req.Name,
+		CUI:     &req.CUI,
+		SIRUTA:  &req.SIRUTA,
+		Address: &req.Address,
+		Phone:   &req.Phone,
+		Email:   &req.Email,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hospital)
+}
+
+// UpdateHospital changes a hospital's master data
+func (h *Handlers) UpdateHospital(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var req UpdateHospitalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	hospital, err := h.hospitalService.UpdateHospital(c.Request.Context(), hospitalID, domain.HospitalInput{
+		Name:    req.Name,
+		CUI:     req.CUI,
+		SIRUTA:  req.SIRUTA,
+		Address: req.Address,
+		Phone:   req.Phone,
+		Email:   req.Email,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hospital)
+}
+
+// DeleteHospital removes a hospital that has no patients, reports or users
+func (h *Handlers) DeleteHospital(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := h.hospitalService.DeleteHospital(c.Request.Context(), hospitalID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Hospital deleted successfully"})
+}
+
+// UploadHospitalLogo stores the letterhead logo sent as the raw request body
+func (h *Handlers) UploadHospitalLogo(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	logo, err := io.ReadAll(io.LimitReader(c.Request.Body, domain.MaxLogoSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.hospitalService.SetLogo(c.Request.Context(), hospitalID, logo); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logo uploaded successfully"})
+}
+
+// DeleteHospitalLogo removes the letterhead logo
+func (h *Handlers) DeleteHospitalLogo(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := h.hospitalService.RemoveLogo(c.Request.Context(), hospitalID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logo removed successfully"})
+}
+
+// ListPrintLayouts returns the print layouts a hospital has stored
+func (h *Handlers) ListPrintLayouts(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	layouts, err := h.documentService.ListLayouts(c.Request.Context(), hospitalID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"layouts": layouts,
+		"total":   len(layouts),
+	})
+}
+
+// GetPrintLayout returns the print layout used for a report type, the
+// built-in one when the hospital has not stored its own
+func (h *Handlers) GetPrintLayout(c *gin.Context) {
+	hospitalID, reportType, ok := h.printLayoutParams(c)
+	if !ok {
+		return
+	}
+
+	layout, err := h.documentService.GetLayout(c.Request.Context(), hospitalID, reportType)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// SavePrintLayout stores a hospital's print layout for a report type
+func (h *Handlers) SavePrintLayout(c *gin.Context) {
+	hospitalID, reportType, ok := h.printLayoutParams(c)
+	if !ok {
+		return
+	}
+
+	var req PrintLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	layout := &domain.PrintLayout{HospitalID: hospitalID, ReportType: reportType, Source: req.Source}
+	if err := h.documentService.SaveLayout(c.Request.Context(), layout, currentClaims(c).UserID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// DeletePrintLayout restores the built-in print layout for a report type
+func (h *Handlers) DeletePrintLayout(c *gin.Context) {
+	hospitalID, reportType, ok := h.printLayoutParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.documentService.DeleteLayout(c.Request.Context(), hospitalID, reportType); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Print layout removed successfully"})
+}
+
+// PreviewPrintLayout renders an unsaved print layout with one of the
+// hospital's reports or with a sample report
+func (h *Handlers) PreviewPrintLayout(c *gin.Context) {
+	hospitalID, reportType, ok := h.printLayoutParams(c)
+	if !ok {
+		return
+	}
+
+	var req PreviewPrintLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	layout := &domain.PrintLayout{HospitalID: hospitalID, ReportType: reportType, Source: req.Source}
+	page, err := h.documentService.PreviewLayout(c.Request.Context(), layout, req.ReportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	writeHTML(c, page)
+}
+
+func (h *Handlers) printLayoutParams(c *gin.Context) (uuid.UUID, domain.ReportType, bool) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return uuid.Nil, "", false
+	}
+
+	reportType := domain.ReportType(c.Param("report_type"))
+	if !reportType.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_report_type",
+			Message: "Invalid report type",
+		})
+		return uuid.Nil, "", false
+	}
+
+	return hospitalID, reportType, true
+}
+
+// CreateDepartment adds a department to a hospital
+func (h *Handlers) CreateDepartment(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var req CreateDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	specialty := domain.Specialty(req.Specialty)
+	department, err := h.hospitalService.CreateDepartment(c.Request.Context(), hospitalID, domain.DepartmentInput{
+		Code:      &req.Code,
+		Name:      &req.Name,
+		Specialty: &specialty,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, department)
+}
+
+// UpdateDepartment changes a department's code, name or specialty
+func (h *Handlers) UpdateDepartment(c *gin.Context) {
+	hospitalID, departmentID, ok := h.departmentParams(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	input := domain.DepartmentInput{Code: req.Code, Name: req.Name}
+	if req.Specialty != nil {
+		specialty := domain.Specialty(*req.Specialty)
+		input.Specialty = &specialty
+	}
+
+	department, err := h.hospitalService.UpdateDepartment(c.Request.Context(), hospitalID, departmentID, input)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, department)
+}
+
+// DeleteDepartment removes a department no encounter stay references
+func (h *Handlers) DeleteDepartment(c *gin.Context) {
+	hospitalID, departmentID, ok := h.departmentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.hospitalService.DeleteDepartment(c.Request.Context(), hospitalID, departmentID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Department deleted successfully"})
+}
+
+// departmentParams parses the hospital and department path parameters of an
+// admin department operation
+func (h *Handlers) departmentParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	hospitalID, ok := h.adminHospital(c, c.Param("id"))
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	departmentID, err := ParseUUID(c.Param("department_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_department_id",
+			Message: "Invalid department ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return hospitalID, departmentID, true
+}
+
+// ListTemplates returns the hospital's report templates; retired ones only
+// with include_inactive=true
+func (h *Handlers) ListTemplates(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return
+	}
+
+	includeInactive := c.Query("include_inactive") == "true"
+	templates, err := h.hospitalService.ListTemplates(c.Request.Context(), hospitalID, includeInactive)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"total":     len(templates),
+	})
+}
+
+// CreateTemplate creates version 1 of a report template
+func (h *Handlers) CreateTemplate(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	template, err := h.hospitalService.CreateTemplate(c.Request.Context(), hospitalID, templateInput(req), currentClaims(c).UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetTemplate returns the current version of a template
+func (h *Handlers) GetTemplate(c *gin.Context) {
+	hospitalID, templateID, ok := h.templateParams(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.hospitalService.GetTemplate(c.Request.Context(), hospitalID, templateID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate replaces a template's definition with a new version
+func (h *Handlers) UpdateTemplate(c *gin.Context) {
+	hospitalID, templateID, ok := h.templateParams(c)
+	if !ok {
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	template, err := h.hospitalService.UpdateTemplate(c.Request.Context(), hospitalID, templateID, templateInput(req), currentClaims(c).UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// ListTemplateVersions returns every version of a template, newest first
+func (h *Handlers) ListTemplateVersions(c *gin.Context) {
+	hospitalID, templateID, ok := h.templateParams(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.hospitalService.ListTemplateVersions(c.Request.Context(), hospitalID, templateID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+		"total":    len(versions),
+	})
+}
+
+// ActivateTemplate makes a retired template apply to new reports again
+func (h *Handlers) ActivateTemplate(c *gin.Context) {
+	h.setTemplateActive(c, true)
+}
+
+// DeactivateTemplate stops a template from applying to new reports
+func (h *Handlers) DeactivateTemplate(c *gin.Context) {
+	h.setTemplateActive(c, false)
+}
+
+func (h *Handlers) setTemplateActive(c *gin.Context, active bool) {
+	hospitalID, templateID, ok := h.templateParams(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.hospitalService.SetTemplateActive(c.Request.Context(), hospitalID, templateID, active)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// templateParams parses the hospital_id query and the template ID
+func (h *Handlers) templateParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	templateID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_template_id",
+			Message: "Invalid template ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return hospitalID, templateID, true
+}
+
+func templateInput(req TemplateRequest) domain.TemplateInput {
+	return domain.TemplateInput{
+		Name:             req.Name,
+		Specialty:        domain.Specialty(req.Specialty),
+		ReportType:       domain.ReportType(req.ReportType),
+		DepartmentID:     req.DepartmentID,
+		Defaults:         req.Defaults,
+		RequiredSections: req.RequiredSections,
+		Checklist:        req.Checklist,
+	}
+}
+
+// ListDepartmentHandoffs returns the transfers queued for a department
+func (h *Handlers) ListDepartmentHandoffs(c *gin.Context) {
+	hospitalID, departmentID, ok := h.handoffParams(c)
+	if !ok {
+		return
+	}
+
+	status := domain.HandoffStatus(c.DefaultQuery("status", string(domain.HandoffPending)))
+	if !status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be pending or acknowledged",
+		})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	handoffs, total, err := h.encounterService.ListHandoffs(c.Request.Context(), hospitalID, departmentID, status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, HandoffListResponse{
+		Handoffs: handoffs,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	})
+}
+
+// AcknowledgeHandoff marks a transfer as taken over by the receiving
+// department
+func (h *Handlers) AcknowledgeHandoff(c *gin.Context) {
+	hospitalID, departmentID, ok := h.handoffParams(c)
+	if !ok {
+		return
+	}
+
+	handoffID, err := ParseUUID(c.Param("handoff_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_handoff_id",
+			Message: "Invalid handoff ID format",
+		})
+		return
+	}
+
+	handoff, err := h.encounterService.AcknowledgeHandoff(c.Request.Context(), hospitalID, departmentID, handoffID, currentClaims(c).UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, handoff)
+}
+
+// handoffParams parses the hospital and department of a handoff queue. Users
+// see only the queues of their own hospital; platform administrators are not
+// restricted.
+func (h *Handlers) handoffParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	hospitalID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	claims := currentClaims(c)
+	if claims.Role != domain.RolePlatformAdmin {
+		if own, err := ParseUUID(claims.HospitalID); err != nil || own != hospitalID {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "forbidden",
+				Message: "Handoffs can only be handled by the receiving hospital",
+			})
+			return uuid.Nil, uuid.Nil, false
+		}
+	}
+
+	departmentID, err := ParseUUID(c.Param("department_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_department_id",
+			Message: "Invalid department ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return hospitalID, departmentID, true
+}
+
+// ScanDuplicatePatients runs duplicate detection for the hospital on demand
+func (h *Handlers) ScanDuplicatePatients(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return
+	}
+
+	result, err := h.mergeService.DetectDuplicates(c.Request.Context(), hospitalID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListDuplicatePatients returns the review queue, filtered by ?status=
+// (pending by default)
+func (h *Handlers) ListDuplicatePatients(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return
+	}
+
+	status := domain.DuplicateStatus(c.DefaultQuery("status", string(domain.DuplicatePending)))
+	if !status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be pending, merged or dismissed",
+		})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	reviews, err := h.mergeService.ListDuplicates(c.Request.Context(), hospitalID, status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]DuplicateCandidateResponse, len(reviews))
+	for i, review := range reviews {
+		responses[i] = DuplicateCandidateResponse{
+			DuplicateCandidate: *review.Candidate,
+			PatientA:           ToPatientResponse(review.PatientA),
+			PatientB:           ToPatientResponse(review.PatientB),
+		}
+	}
+
+	c.JSON(http.StatusOK, DuplicateListResponse{
+		Candidates: responses,
+		Total:      len(responses),
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// DismissDuplicatePatient removes a candidate from the queue as not the same person
+func (h *Handlers) DismissDuplicatePatient(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return
+	}
+
+	candidateID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_candidate_id",
+			Message: "Invalid candidate ID format",
+		})
+		return
+	}
+
+	candidate, err := h.mergeService.DismissDuplicate(c.Request.Context(), hospitalID, candidateID, currentClaims(c).UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, candidate)
+}
+
+// MergePatients merges a duplicate record into the surviving patient
+func (h *Handlers) MergePatients(c *gin.Context) {
+	var req MergePatientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	hospitalID, ok := h.adminHospital(c, req.HospitalID)
+	if !ok {
+		return
+	}
+
+	survivorID, err1 := ParseUUID(req.SurvivorID)
+	mergedID, err2 := ParseUUID(req.MergedID)
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_patient_id",
+			Message: "Invalid patient ID format",
+		})
+		return
+	}
+
+	var candidateID *uuid.UUID
+	if req.CandidateID != "" {
+		id, err := ParseUUID(req.CandidateID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_candidate_id",
+				Message: "Invalid candidate ID format",
+			})
+			return
+		}
+		candidateID = &id
+	}
+
+	merge, err := h.mergeService.MergePatients(c.Request.Context(), hospitalID, survivorID, mergedID, candidateID, currentClaims(c).UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, merge)
+}
+
+// ListPatientMerges returns the hospital's merges, newest first
+func (h *Handlers) ListPatientMerges(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	merges, err := h.mergeService.ListMerges(c.Request.Context(), hospitalID, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MergeListResponse{
+		Merges: merges,
+		Total:  len(merges),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// RevertPatientMerge un-merges two patients
+func (h *Handlers) RevertPatientMerge(c *gin.Context) {
+	hospitalID, ok := h.adminHospital(c, c.Query("hospital_id"))
+	if !ok {
+		return
+	}
+
+	mergeID, err := ParseUUID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_merge_id",
+			Message: "Invalid merge ID format",
+		})
+		return
+	}
+
+	merge, err := h.mergeService.UnmergePatients(c.Request.Context(), hospitalID, mergeID, currentClaims(c).UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, merge)
+}
+
+// adminHospital parses the hospital an admin operation targets. Admins act
+// only on their own hospital; platform administrators are not restricted.
+func (h *Handlers) adminHospital(c *gin.Context, value string) (uuid.UUID, bool) {
+	hospitalID, err := ParseUUID(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital_id",
+			Message: "Invalid hospital ID format",
+		})
+		return uuid.Nil, false
+	}
+
+	claims := currentClaims(c)
+	if claims.Role != domain.RolePlatformAdmin {
+		if own, err := ParseUUID(claims.HospitalID); err != nil || own != hospitalID {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "forbidden",
+				Message: "Administrators can only manage their own hospital",
+			})
+			return uuid.Nil, false
+		}
+	}
+
+	return hospitalID, true
+}
+
+// handleError handles domain errors and converts them to HTTP responses
+func (h *Handlers) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "report_not_found",
+			Message: "Report not found",
+		})
+	case errors.Is(err, domain.ErrCannotEditNonDraft):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "cannot_edit_non_draft",
+			Message: "Can only edit reports in draft status",
+		})
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_status_transition",
+			Message: "Invalid status transition",
+		})
 	case errors.Is(err, domain.ErrIncompleteReport):
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "incomplete_report",
-			Message: "Report is incomplete and cannot be finalized",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidReportType):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_report_type",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidSpecialty):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_specialty",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidOperativeNote):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_operative_note",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrContraindicatedInteraction):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "contraindicated_interaction",
+			Message: "Report contains contraindicated medication combinations; provide an override_reason to proceed",
 		})
 	case errors.Is(err, domain.ErrInvalidCNP):
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "invalid_cnp",
 			Message: "Invalid CNP format",
 		})
+	case errors.Is(err, domain.ErrPatientNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "patient_not_found",
+			Message: "Patient not found",
+		})
+	case errors.Is(err, domain.ErrPatientAlreadyExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "patient_already_exists",
+			Message: "A patient with this CNP is already registered",
+		})
+	case errors.Is(err, domain.ErrNoPreviousReport):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "no_previous_report",
+			Message: "Patient has no signed report to carry forward from",
+		})
+	case errors.Is(err, domain.ErrInvalidCarryForward):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_carry_forward",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrHospitalNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "hospital_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrHospitalInUse):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "hospital_in_use",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrHospitalCUIExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "cui_exists",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidCUI), errors.Is(err, domain.ErrInvalidSIRUTA), errors.Is(err, domain.ErrInvalidAddress):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_hospital",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidLogo):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_logo",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrLogoNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "logo_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidPrintLayout):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_print_layout",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrPrintLayoutNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "print_layout_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidFHIRBundle):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_fhir_bundle",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrVerificationNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "verification_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrDepartmentNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "department_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrDepartmentCodeExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "department_code_exists",
+			Message: "Department code already used in this hospital",
+		})
+	case errors.Is(err, domain.ErrDepartmentInUse):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "department_in_use",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidDepartment):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_department",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidTransferSummary):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_transfer_summary",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrHandoffNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "handoff_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrHandoffAcknowledged):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "handoff_acknowledged",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "template_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_template",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrTemplateIncomplete):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "template_incomplete",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrEncounterNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "encounter_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrEncounterClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "encounter_closed",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidTransfer):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_transfer",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrAdmissionNumberExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "admission_number_exists",
+			Message: "Admission number already used in this hospital",
+		})
+	case errors.Is(err, domain.ErrAdmissionConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "admission_conflict",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrDuplicateDischargeSummary):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "duplicate_discharge_summary",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidDate):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_date",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrDuplicateNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "duplicate_not_found",
+			Message: "Duplicate candidate not found",
+		})
+	case errors.Is(err, domain.ErrMergeNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "merge_not_found",
+			Message: "Patient merge not found",
+		})
+	case errors.Is(err, domain.ErrInvalidMerge):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "invalid_merge",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrMergeAlreadyReverted):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "merge_already_reverted",
+			Message: "Patient merge was already reverted",
+		})
+	case errors.Is(err, domain.ErrEmptyField):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrReferenceNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "reference_not_found",
+			Message: "Reference entry not found",
+		})
+	case errors.Is(err, domain.ErrUnknownDiagnosisCode):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "unknown_diagnosis_code",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrRetiredDiagnosisCode):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "retired_diagnosis_code",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrReconciliationIncomplete):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "reconciliation_incomplete",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidSig):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_sig",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidDose):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_dose",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrUnknownLabTest):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "unknown_lab_test",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrUnknownProcedureCode):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "unknown_procedure_code",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrRetiredProcedureCode):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "retired_procedure_code",
+			Message: err.Error(),
+		})
 	default:
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_server_error",
@@ -354,6 +2399,13 @@ func (h *Handlers) Register(c *gin.Context) {
 
 	authResp, err := h.authService.Register(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, domain.ErrHospitalNotFound) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_hospital_id",
+				Message: "Unknown hospital",
+			})
+			return
+		}
 		if err.Error() == "email already registered" {
 			c.JSON(http.StatusConflict, ErrorResponse{
 				Error:   "email_exists",
@@ -2,9 +2,13 @@ package server
 
 import (
 	"log"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tudormiron/medical-reports/internal/domain"
+	"github.com/tudormiron/medical-reports/internal/services"
 )
 
 // LoggerMiddleware logs request details
@@ -59,3 +63,67 @@ func ErrorHandlingMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+const claimsKey = "claims"
+
+// AuthMiddleware requires a valid bearer token and stores its claims in the
+// request context
+func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "unauthorized",
+				Message: "A bearer token is required",
+			})
+			return
+		}
+
+		claims, err := authService.ParseToken(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "invalid_token",
+				Message: "Invalid or expired token",
+			})
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		c.Next()
+	}
+}
+
+// RequireAdmin lets through platform administrators and administrators
+// assigned to a hospital. Administrators without a hospital are refused until
+// one is assigned to them. Must run after AuthMiddleware.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := currentClaims(c)
+		switch {
+		case claims == nil || (claims.Role != domain.RoleAdmin && claims.Role != domain.RolePlatformAdmin):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Error:   "forbidden",
+				Message: "This operation requires the " + domain.RoleAdmin + " role",
+			})
+			return
+		case claims.Role == domain.RoleAdmin && claims.HospitalID == "":
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Error:   "forbidden",
+				Message: "Your account is not assigned to a hospital",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// currentClaims returns the authenticated user, or nil on public routes
+func currentClaims(c *gin.Context) *domain.TokenClaims {
+	value, ok := c.Get(claimsKey)
+	if !ok {
+		return nil
+	}
+	claims, _ := value.(*domain.TokenClaims)
+	return claims
+}
@@ -15,9 +15,9 @@ type Server struct {
 	router   *gin.Engine
 }
 
-func NewServer(cfg *config.Config, reportService *services.ReportService, referenceService *services.ReferenceService, authService *services.AuthService) *Server {
-	handlers := NewHandlers(reportService, referenceService, authService)
-	router := setupRouter(handlers)
+func NewServer(cfg *config.Config, reportService *services.ReportService, referenceService *services.ReferenceService, authService *services.AuthService, patientService *services.PatientService, mergeService *services.PatientMergeService, encounterService *services.EncounterService, hospitalService *services.HospitalService, documentService *services.DocumentService) *Server {
+	handlers := NewHandlers(reportService, referenceService, authService, patientService, mergeService, encounterService, hospitalService, documentService)
+	router := setupRouter(handlers, authService)
 
 	return &Server{
 		config:   cfg,
@@ -26,7 +26,7 @@ func NewServer(cfg *config.Config, reportService *services.ReportService, refere
 	}
 }
 
-func setupRouter(handlers *Handlers) *gin.Engine {
+func setupRouter(handlers *Handlers, authService *services.AuthService) *gin.Engine {
 	// Set Gin to release mode in production
 	gin.SetMode(gin.ReleaseMode)
 
@@ -51,23 +51,111 @@ func setupRouter(handlers *Handlers) *gin.Engine {
 			auth.POST("/login", handlers.Login)
 		}
 
+		// Verification of printed reports (public route)
+		v1.GET("/verify/:token", handlers.VerifyReport)
+
 		// Reports
 		reports := v1.Group("/reports")
 		{
 			reports.POST("", handlers.CreateReport)
+			reports.POST("/carry-forward", handlers.CreateReportFromPrevious)
+			reports.POST("/fhir", handlers.ImportFHIRReport)
 			reports.GET("", handlers.ListReports)
+			reports.GET("/statistics/icd10-blocks", handlers.GetDiagnosisBlockStatistics)
 			reports.GET("/:id", handlers.GetReport)
 			reports.PUT("/:id/content", handlers.UpdateReportContent)
 			reports.PUT("/:id/status", handlers.UpdateReportStatus)
 			reports.DELETE("/:id", handlers.DeleteReport)
 			reports.GET("/:id/versions", handlers.GetReportVersions)
+			reports.GET("/:id/pdf", handlers.GetReportPDF)
+			reports.GET("/:id/html", handlers.GetReportHTML)
+			reports.GET("/:id/fhir", handlers.GetReportFHIR)
+		}
+
+		// Patient registry
+		patients := v1.Group("/patients")
+		{
+			patients.POST("", handlers.CreatePatient)
+			patients.GET("", handlers.SearchPatients)
+			patients.GET("/timeline", handlers.GetPatientTimeline)
+			patients.GET("/:id", handlers.GetPatient)
+			patients.PUT("/:id", handlers.UpdatePatient)
+			patients.GET("/:id/reports", handlers.ListPatientReports)
+			patients.GET("/:id/encounters", handlers.ListPatientEncounters)
+		}
+
+		// Encounters (admission episodes)
+		encounters := v1.Group("/encounters")
+		{
+			encounters.POST("", handlers.AdmitPatient)
+			encounters.GET("/:id", handlers.GetEncounter)
+			encounters.POST("/:id/transfer", handlers.TransferPatient)
+			encounters.POST("/:id/discharge", handlers.DischargePatient)
+			encounters.GET("/:id/reports", handlers.ListEncounterReports)
 		}
 
 		// Reference data
 		reference := v1.Group("/reference")
 		{
 			reference.GET("/icd10", handlers.SearchICD10)
+			reference.GET("/icd10/chapters", handlers.ListICD10Chapters)
+			reference.GET("/icd10/:code/children", handlers.GetICD10Children)
+			reference.GET("/icd10/:code/ancestors", handlers.GetICD10Ancestors)
 			reference.GET("/medications", handlers.SearchMedications)
+			reference.GET("/lab-tests", handlers.SearchLabTests)
+			reference.GET("/lab-tests/:code", handlers.GetLabTest)
+			reference.GET("/procedures", handlers.SearchProcedures)
+			reference.GET("/procedures/:code", handlers.GetProcedure)
+			reference.GET("/sig", handlers.ParseSig)
+		}
+
+		// Hospital master data
+		hospitals := v1.Group("/hospitals")
+		{
+			hospitals.GET("", handlers.ListHospitals)
+			hospitals.GET("/:id", handlers.GetHospital)
+			hospitals.GET("/:id/logo", handlers.GetHospitalLogo)
+			hospitals.GET("/:id/departments", handlers.ListDepartments)
+		}
+
+		// Receiving department queue (authenticated users of the hospital)
+		handoffs := v1.Group("/hospitals/:id/departments/:department_id/handoffs", AuthMiddleware(authService))
+		{
+			handoffs.GET("", handlers.ListDepartmentHandoffs)
+			handoffs.POST("/:handoff_id/acknowledge", handlers.AcknowledgeHandoff)
+		}
+
+		// Administration (admin role required)
+		admin := v1.Group("/admin", AuthMiddleware(authService), RequireAdmin())
+		{
+			admin.POST("/hospitals", handlers.CreateHospital)
+			admin.PUT("/hospitals/:id", handlers.UpdateHospital)
+			admin.DELETE("/hospitals/:id", handlers.DeleteHospital)
+			admin.PUT("/hospitals/:id/logo", handlers.UploadHospitalLogo)
+			admin.DELETE("/hospitals/:id/logo", handlers.DeleteHospitalLogo)
+			admin.GET("/hospitals/:id/print-layouts", handlers.ListPrintLayouts)
+			admin.GET("/hospitals/:id/print-layouts/:report_type", handlers.GetPrintLayout)
+			admin.PUT("/hospitals/:id/print-layouts/:report_type", handlers.SavePrintLayout)
+			admin.DELETE("/hospitals/:id/print-layouts/:report_type", handlers.DeletePrintLayout)
+			admin.POST("/hospitals/:id/print-layouts/:report_type/preview", handlers.PreviewPrintLayout)
+			admin.POST("/hospitals/:id/departments", handlers.CreateDepartment)
+			admin.PUT("/hospitals/:id/departments/:department_id", handlers.UpdateDepartment)
+			admin.DELETE("/hospitals/:id/departments/:department_id", handlers.DeleteDepartment)
+
+			admin.GET("/templates", handlers.ListTemplates)
+			admin.POST("/templates", handlers.CreateTemplate)
+			admin.GET("/templates/:id", handlers.GetTemplate)
+			admin.PUT("/templates/:id", handlers.UpdateTemplate)
+			admin.GET("/templates/:id/versions", handlers.ListTemplateVersions)
+			admin.POST("/templates/:id/activate", handlers.ActivateTemplate)
+			admin.POST("/templates/:id/deactivate", handlers.DeactivateTemplate)
+
+			admin.POST("/patients/duplicates/scan", handlers.ScanDuplicatePatients)
+			admin.GET("/patients/duplicates", handlers.ListDuplicatePatients)
+			admin.POST("/patients/duplicates/:id/dismiss", handlers.DismissDuplicatePatient)
+			admin.POST("/patients/merges", handlers.MergePatients)
+			admin.GET("/patients/merges", handlers.ListPatientMerges)
+			admin.POST("/patients/merges/:id/revert", handlers.RevertPatientMerge)
 		}
 	}
 